@@ -20,11 +20,36 @@ import (
 	"tech-test/backend/internal/config"
 	"tech-test/backend/internal/database"
 	"tech-test/backend/internal/handler"
+	"tech-test/backend/internal/jwtkeys"
+	"tech-test/backend/internal/mailer"
 	"tech-test/backend/internal/middleware"
+	"tech-test/backend/internal/repository/interfaces"
 	"tech-test/backend/internal/repository/memory"
+	"tech-test/backend/internal/repository/seed"
 	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/service/authz"
 	userService "tech-test/backend/internal/service/user"
 	fileService "tech-test/backend/internal/service/file"
+	uploadRequestService "tech-test/backend/internal/service/uploadrequest"
+	collectionService "tech-test/backend/internal/service/collection"
+	shareService "tech-test/backend/internal/service/share"
+	accessTokenService "tech-test/backend/internal/service/accesstoken"
+	mfaService "tech-test/backend/internal/service/mfa"
+	passwordResetService "tech-test/backend/internal/service/passwordreset"
+	emailVerificationService "tech-test/backend/internal/service/emailverification"
+	ssoService "tech-test/backend/internal/service/sso"
+	loginGuardService "tech-test/backend/internal/service/loginguard"
+	"tech-test/backend/internal/oidc"
+	"tech-test/backend/internal/passwordhash"
+	"tech-test/backend/internal/passwordpolicy"
+	tokenService "tech-test/backend/internal/service/token"
+	moderationService "tech-test/backend/internal/service/moderation"
+	organizationService "tech-test/backend/internal/service/organization"
+	auditService "tech-test/backend/internal/service/audit"
+	impersonationService "tech-test/backend/internal/service/impersonation"
+	privacyService "tech-test/backend/internal/service/privacy"
+	invitationService "tech-test/backend/internal/service/invitation"
+	privacyInterface "tech-test/backend/internal/service/interfaces/privacy"
 	_ "tech-test/backend/docs" 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -36,6 +61,7 @@ type Application struct {
 	httpServer *http.Server
 	router     *mux.Router
 	db         *gorm.DB
+	privacy    privacyInterface.Service
 }
 
 func main() {
@@ -93,37 +119,319 @@ func (app *Application) setupDependencies() error {
 		return fmt.Errorf("database ping failed: %w", err)
 	}
 
-	userRepo := memory.NewUserRepository()
+	// Before the user seed file, which may contain passwords to hash.
+	if err := passwordhash.Configure(passwordhash.Params{
+		Memory:      uint32(app.config.PasswordHash.Memory),
+		Iterations:  uint32(app.config.PasswordHash.Iterations),
+		Parallelism: uint8(app.config.PasswordHash.Parallelism),
+	}); err != nil {
+		return err
+	}
+
+	userRepo, err := app.newUserRepository(db)
+	if err != nil {
+		return err
+	}
 	fileRepo := sqlite.NewFileRepository(db)
+	uploadRequestRepo := sqlite.NewUploadRequestRepository(db)
+	collectionRepo := sqlite.NewCollectionRepository(db)
+	shareRecipientRepo := sqlite.NewShareRecipientRepository(db)
+	abuseReportRepo := sqlite.NewAbuseReportRepository(db)
+	tokenRepo := sqlite.NewTokenRepository(db)
+	accessTokenRepo := sqlite.NewAccessTokenRepository(db)
+	mfaRepo := sqlite.NewMFARepository(db)
+	settingRepo := sqlite.NewSettingRepository(db)
+	passwordResetRepo := sqlite.NewPasswordResetRepository(db)
+	emailVerificationRepo := sqlite.NewEmailVerificationRepository(db)
+	oidcRepo := sqlite.NewOIDCRepository(db)
+	loginGuardRepo := sqlite.NewLoginGuardRepository(db)
+	orgRepo := sqlite.NewOrganizationRepository(db)
+	auditRepo := sqlite.NewAuditRepository(db)
+	impersonationRepo := sqlite.NewImpersonationRepository(db)
+	erasureRepo := sqlite.NewErasureRepository(db)
+	invitationRepo := sqlite.NewInvitationRepository(db)
 
 	uploadDir := filepath.Join(".", "uploads")
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
 		return fmt.Errorf("failed to create upload directory: %w", err)
 	}
 
-	userService := userService.NewService(userRepo, app.logger)
+	keys, err := app.newKeyring()
+	if err != nil {
+		return err
+	}
+
+	authorizer := authz.NewAuthorizer(orgRepo, app.logger)
+	tokenService := tokenService.NewService(tokenRepo, userRepo, app.config.JWT, keys, app.logger)
+	passwordPolicy, err := passwordpolicy.New(app.config.PasswordPolicy)
+	if err != nil {
+		return err
+	}
+	auditService := auditService.NewService(auditRepo, app.logger)
+	userService := userService.NewService(userRepo, app.logger, app.config.Admin.Emails, tokenService, passwordPolicy, auditService)
+	if err := userService.BootstrapAdmins(context.Background()); err != nil {
+		return fmt.Errorf("failed to bootstrap admins: %w", err)
+	}
 	fileService := fileService.NewService(
 		fileRepo,
+		orgRepo,
+		authorizer,
 		app.logger,
 		uploadDir,
 	)
+	mfaService := mfaService.NewService(mfaRepo, settingRepo, userRepo, app.config.MFA.Issuer, app.logger)
+	accessTokenService := accessTokenService.NewService(accessTokenRepo, userRepo, authorizer, app.logger)
+	uploadRequestService := uploadRequestService.NewService(
+		uploadRequestRepo,
+		fileRepo,
+		authorizer,
+		app.logger,
+		app.config.File.MaxSize,
+	)
+	collectionService := collectionService.NewService(collectionRepo, fileRepo, authorizer, app.logger)
+	mail := app.newMailer()
+	shareService := shareService.NewService(
+		fileRepo,
+		shareRecipientRepo,
+		userRepo,
+		authorizer,
+		mail,
+		app.config.File.BaseURL,
+		app.logger,
+	)
+	passwordResetService := passwordResetService.NewService(
+		passwordResetRepo,
+		userRepo,
+		userService,
+		mail,
+		app.config.PasswordReset.URL,
+		app.config.PasswordReset.TokenTTL,
+		app.logger,
+	)
+	if mail == nil && app.config.EmailVerification.Required {
+		app.logger.Warn("Email verification is required but mail delivery is disabled; new users cannot upload or share until an admin verifies them")
+	}
+	emailVerificationService := emailVerificationService.NewService(
+		emailVerificationRepo,
+		userRepo,
+		userService,
+		mail,
+		app.config.EmailVerification.URL,
+		app.config.EmailVerification.TokenTTL,
+		app.config.EmailVerification.Required,
+		app.logger,
+	)
+	invitationService, err := invitationService.NewService(
+		invitationRepo,
+		orgRepo,
+		userService,
+		mail,
+		app.config.Registration,
+		app.logger,
+	)
+	if err != nil {
+		return err
+	}
+	oidcProviders, err := app.newOIDCProviders()
+	if err != nil {
+		return err
+	}
+	ssoService := ssoService.NewService(
+		oidcRepo,
+		userRepo,
+		invitationService,
+		oidcProviders,
+		app.config.OIDC.RedirectBaseURL,
+		app.logger,
+	)
+	loginGuardService := loginGuardService.NewService(loginGuardRepo, app.config.LoginGuard, app.logger)
+	orgService := organizationService.NewService(
+		orgRepo,
+		fileRepo,
+		userRepo,
+		app.config.Organization.DefaultStorageQuota,
+		app.logger,
+	)
+	impersonationService := impersonationService.NewService(
+		impersonationRepo,
+		userRepo,
+		tokenService,
+		auditService,
+		app.config.Impersonation,
+		app.logger,
+	)
+	privacyService := privacyService.NewService(
+		erasureRepo,
+		privacyService.Repositories{
+			Users:           userRepo,
+			Files:           fileRepo,
+			ShareRecipients: shareRecipientRepo,
+			Collections:     collectionRepo,
+			UploadRequests:  uploadRequestRepo,
+			Organizations:   orgRepo,
+			Tokens:          tokenRepo,
+			AccessTokens:    accessTokenRepo,
+			LoginGuard:      loginGuardRepo,
+		},
+		tokenService,
+		auditService,
+		app.config.Privacy.ErasureGracePeriod,
+		app.logger,
+	)
+	app.privacy = privacyService
+	moderationService := moderationService.NewService(
+		abuseReportRepo,
+		fileRepo,
+		userRepo,
+		mail,
+		app.logger,
+	)
 
 	app.setupRoutes(
-		handler.NewAuthHandler(userService),
+		handler.NewAuthHandler(userService, tokenService, mfaService, emailVerificationService, ssoService, loginGuardService, invitationService, passwordResetService),
 		handler.NewFileHandler(
 			fileService,
+			shareService,
+			app.config.File,
+		),
+		handler.NewUserHandler(userService, privacyService, emailVerificationService),
+		handler.NewUploadRequestHandler(
+			uploadRequestService,
 			app.config.File,
+			app.logger,
 		),
-		handler.NewUserHandler(userService),
+		handler.NewCollectionHandler(
+			collectionService,
+			app.config.File,
+			app.logger,
+		),
+		handler.NewModerationHandler(moderationService),
+		handler.NewAccessTokenHandler(accessTokenService),
+		handler.NewMFAHandler(mfaService),
+		handler.NewPasswordResetHandler(passwordResetService),
+		handler.NewEmailVerificationHandler(emailVerificationService),
+		handler.NewOIDCHandler(ssoService, app.config.OIDC, app.logger),
+		handler.NewLoginGuardHandler(loginGuardService),
+		handler.NewJWKSHandler(keys),
+		handler.NewSessionHandler(tokenService),
+		handler.NewOrganizationHandler(orgService),
+		handler.NewImpersonationHandler(impersonationService),
+		handler.NewAuditHandler(auditService),
+		handler.NewPrivacyHandler(privacyService, app.logger),
+		handler.NewInvitationHandler(invitationService),
+		keys,
+		tokenService,
+		accessTokenService,
+		userService,
+		emailVerificationService,
+		auditService,
 	)
 
 	return nil
 }
 
+// newUserRepository returns the configured user store, seeded from
+// USER_SEED_FILE when one is set.
+func (app *Application) newUserRepository(db *gorm.DB) (interfaces.UserRepository, error) {
+	var repo interfaces.UserRepository
+	switch app.config.Database.UserStore {
+	case config.UserStoreSQLite:
+		repo = sqlite.NewUserRepository(db)
+	case config.UserStoreMemory:
+		app.logger.Warn("Using in-memory user store, accounts will be lost on restart")
+		repo = memory.NewUserRepository()
+	default:
+		return nil, fmt.Errorf("unknown USER_STORE %q", app.config.Database.UserStore)
+	}
+
+	if path := app.config.Database.UserSeedFile; path != "" {
+		created, err := seed.ImportUsers(context.Background(), repo, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import users: %w", err)
+		}
+		app.logger.Info("Imported users from seed file",
+			zap.String("path", path),
+			zap.Int("created", created))
+	}
+
+	return repo, nil
+}
+
+// newKeyring loads the access token signing keys.
+func (app *Application) newKeyring() (*jwtkeys.Keyring, error) {
+	keys, err := jwtkeys.Load(app.config.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT keys: %w", err)
+	}
+
+	switch {
+	case keys.KeyID() == "" && app.config.JWT.Secret == config.DefaultJWTSecret:
+		app.logger.Warn("Signing access tokens with the default JWT_SECRET; set JWT_SECRET or JWT_SIGNING_KEY_FILE")
+	case keys.Ephemeral():
+		app.logger.Warn("No JWT_SIGNING_KEY_FILE set, signing with a key generated at startup; tokens will not survive a restart")
+	}
+	app.logger.Info("Loaded JWT keys",
+		zap.String("algorithm", keys.Algorithm()),
+		zap.String("kid", keys.KeyID()),
+		zap.Int("published", len(keys.JWKS().Keys)))
+	return keys, nil
+}
+
+// newOIDCProviders builds a client for each configured identity provider.
+func (app *Application) newOIDCProviders() ([]*oidc.Provider, error) {
+	var providers []*oidc.Provider
+	for _, cfg := range app.config.OIDC.Providers {
+		if cfg.Issuer == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("OIDC provider %q needs an issuer and a client ID", cfg.Name)
+		}
+		providers = append(providers, oidc.NewProvider(cfg, nil))
+		app.logger.Info("Configured OIDC provider",
+			zap.String("name", cfg.Name),
+			zap.String("issuer", cfg.Issuer))
+	}
+	return providers, nil
+}
+
+// newMailer returns the configured outgoing mailer, or nil when SMTP is
+// selected but no host is set.
+func (app *Application) newMailer() mailer.Mailer {
+	if app.config.Mail.Driver == config.MailDriverLog {
+		app.logger.Warn("MAIL_DRIVER=log, outgoing mail is written to the log")
+		return mailer.NewLogMailer(app.logger)
+	}
+	if app.config.Mail.Host == "" {
+		app.logger.Warn("SMTP_HOST not set, email delivery disabled")
+		return nil
+	}
+	return mailer.NewSMTPMailer(app.config.Mail)
+}
+
 func (app *Application) setupRoutes(
 	authHandler *handler.AuthHandler,
 	fileHandler *handler.FileHandler,
 	userHandler *handler.UserHandler,
+	uploadRequestHandler *handler.UploadRequestHandler,
+	collectionHandler *handler.CollectionHandler,
+	moderationHandler *handler.ModerationHandler,
+	accessTokenHandler *handler.AccessTokenHandler,
+	mfaHandler *handler.MFAHandler,
+	passwordResetHandler *handler.PasswordResetHandler,
+	emailVerificationHandler *handler.EmailVerificationHandler,
+	oidcHandler *handler.OIDCHandler,
+	loginGuardHandler *handler.LoginGuardHandler,
+	jwksHandler *handler.JWKSHandler,
+	sessionHandler *handler.SessionHandler,
+	organizationHandler *handler.OrganizationHandler,
+	impersonationHandler *handler.ImpersonationHandler,
+	auditHandler *handler.AuditHandler,
+	privacyHandler *handler.PrivacyHandler,
+	invitationHandler *handler.InvitationHandler,
+	keys *jwtkeys.Keyring,
+	revocations middleware.RevocationChecker,
+	accessTokens middleware.AccessTokenAuthenticator,
+	accounts middleware.AccountStatusChecker,
+	emailVerifications middleware.EmailVerificationChecker,
+	audit middleware.AuditRecorder,
 ) {
 	app.router.Use(middleware.CORS(app.logger))
 	app.router.Use(middleware.RateLimiterMiddleware())
@@ -133,17 +441,64 @@ func (app *Application) setupRoutes(
 	app.router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
 	app.router.HandleFunc("/health", app.healthCheck).Methods(http.MethodGet)
+	app.router.HandleFunc("/.well-known/jwks.json", jwksHandler.JWKS).Methods(http.MethodGet, http.MethodOptions)
 	app.router.HandleFunc("/api/register", middleware.ValidateRegister(authHandler.Register)).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/register/policy", invitationHandler.Policy).Methods(http.MethodGet, http.MethodOptions)
 	app.router.HandleFunc("/api/login", authHandler.Login).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/login/mfa", authHandler.LoginMFA).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/login/mfa/enroll", authHandler.LoginMFAEnroll).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/password/forgot", passwordResetHandler.ForgotPassword).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/password/reset", middleware.ValidateResetPassword(passwordResetHandler.ResetPassword)).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/email/verify", emailVerificationHandler.Verify).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/auth/oidc/providers", oidcHandler.Providers).Methods(http.MethodGet, http.MethodOptions)
+	app.router.HandleFunc("/api/auth/oidc/exchange", authHandler.LoginOIDC).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/api/auth/oidc/{provider}/login", oidcHandler.Login).Methods(http.MethodGet)
+	app.router.HandleFunc("/api/auth/oidc/{provider}/callback", oidcHandler.Callback).Methods(http.MethodGet)
+	app.router.HandleFunc("/api/token/refresh", authHandler.Refresh).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/shared/collections/{shareId}", collectionHandler.GetShared).Methods(http.MethodGet, http.MethodOptions)
+	app.router.HandleFunc("/shared/collections/{shareId}/zip", collectionHandler.DownloadSharedZip).Methods(http.MethodGet, http.MethodOptions)
+	app.router.HandleFunc("/shared/collections/{shareId}/files/{fileId}", collectionHandler.DownloadSharedFile).Methods(http.MethodGet, http.MethodOptions)
 	app.router.HandleFunc("/shared/{shareId}", fileHandler.GetSharedFile).Methods(http.MethodGet, http.MethodOptions)
+	app.router.HandleFunc("/shared/{shareId}/info", fileHandler.GetSharedInfo).Methods(http.MethodGet, http.MethodOptions)
+	app.router.HandleFunc("/shared/{shareId}/preview", fileHandler.GetSharedPreview).Methods(http.MethodGet, http.MethodOptions)
+	app.router.HandleFunc("/shared/{shareId}/report", moderationHandler.ReportShare).Methods(http.MethodPost, http.MethodOptions)
+	app.router.HandleFunc("/upload-requests/{token}", uploadRequestHandler.GetPublic).Methods(http.MethodGet, http.MethodOptions)
+	app.router.HandleFunc("/upload-requests/{token}", uploadRequestHandler.SubmitPublic).Methods(http.MethodPost, http.MethodOptions)
 
 	protected := app.router.PathPrefix("/api").Subrouter()
-	protected.Use(middleware.AuthMiddleware)
+	protected.Use(middleware.AuthMiddleware(keys, revocations, accessTokens, accounts))
+	protected.Use(middleware.WithActor())
+	protected.Use(middleware.Impersonation(audit, "/api/impersonation/end", "/api/logout"))
 
+	protected.Handle("/logout", middleware.RequireSession()(http.HandlerFunc(authHandler.Logout))).Methods(http.MethodPost, http.MethodOptions)
+	protected.HandleFunc("/impersonation/end", impersonationHandler.Stop).Methods(http.MethodPost, http.MethodOptions)
 	protected.HandleFunc("/users/me", userHandler.GetCurrentUser).Methods(http.MethodGet, http.MethodOptions)
+	protected.Handle("/users/me", middleware.RequireSession()(middleware.ValidateUpdateProfile(userHandler.UpdateProfile))).Methods(http.MethodPatch, http.MethodOptions)
+	protected.Handle("/users/me/password", middleware.RequireSession()(middleware.DenyImpersonation()(middleware.ValidateChangePassword(userHandler.ChangePassword)))).Methods(http.MethodPost, http.MethodOptions)
+	sessions := protected.PathPrefix("/users/me/sessions").Subrouter()
+	sessions.Use(middleware.RequireSession())
+	sessions.Use(middleware.DenyImpersonation())
+	sessions.HandleFunc("", sessionHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	sessions.HandleFunc("/revoke-others", sessionHandler.RevokeOthers).Methods(http.MethodPost, http.MethodOptions)
+	sessions.HandleFunc("/{id}", sessionHandler.Revoke).Methods(http.MethodDelete, http.MethodOptions)
+
+	// Exports and erasures are for the account holder, not an admin acting
+	// as them.
+	privacy := protected.PathPrefix("/users/me").Subrouter()
+	privacy.Use(middleware.RequireSession())
+	privacy.Use(middleware.DenyImpersonation())
+	privacy.HandleFunc("/export", privacyHandler.Export).Methods(http.MethodGet, http.MethodOptions)
+	privacy.HandleFunc("/erasure", privacyHandler.GetErasure).Methods(http.MethodGet, http.MethodOptions)
+	privacy.HandleFunc("/erasure", privacyHandler.RequestErasure).Methods(http.MethodPost, http.MethodOptions)
+	privacy.HandleFunc("/erasure", privacyHandler.CancelErasure).Methods(http.MethodDelete, http.MethodOptions)
+
+	protected.Handle("/email/verify/resend", middleware.RequireSession()(http.HandlerFunc(emailVerificationHandler.Resend))).Methods(http.MethodPost, http.MethodOptions)
+
+	// Uploading and sending links to others need a confirmed email address.
+	verified := middleware.RequireVerifiedEmail(emailVerifications)
 
 	files := protected.PathPrefix("/files").Subrouter()
-	files.HandleFunc("/upload", fileHandler.Upload).Methods(http.MethodPost, http.MethodOptions)
+	files.Handle("/upload", verified(http.HandlerFunc(fileHandler.Upload))).Methods(http.MethodPost, http.MethodOptions)
 	files.HandleFunc("/search", fileHandler.SearchFiles).Methods(http.MethodGet, http.MethodOptions)
 	files.HandleFunc("/my", fileHandler.GetUserFiles).Methods(http.MethodGet, http.MethodOptions)
 	files.HandleFunc("/{id}/download", fileHandler.Download).Methods(http.MethodGet, http.MethodOptions)
@@ -151,14 +506,102 @@ func (app *Application) setupRoutes(
 	files.HandleFunc("/{id}", fileHandler.GetByID).Methods(http.MethodGet, http.MethodOptions)
 	files.HandleFunc("/{id}", fileHandler.Delete).Methods(http.MethodDelete, http.MethodOptions)
 	files.HandleFunc("", fileHandler.List).Methods(http.MethodGet, http.MethodOptions)
-	files.HandleFunc("/{id}/share", fileHandler.GenerateShareableLink).Methods(http.MethodPost, http.MethodOptions)
+	files.Handle("/{id}/share", verified(http.HandlerFunc(fileHandler.GenerateShareableLink))).Methods(http.MethodPost, http.MethodOptions)
+	files.HandleFunc("/{id}/share/recipients", fileHandler.GetShareRecipients).Methods(http.MethodGet, http.MethodOptions)
+
+	uploadRequests := protected.PathPrefix("/upload-requests").Subrouter()
+	uploadRequests.Use(middleware.RequireSession())
+	uploadRequests.HandleFunc("", uploadRequestHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	uploadRequests.Handle("", verified(http.HandlerFunc(uploadRequestHandler.Create))).Methods(http.MethodPost, http.MethodOptions)
+	uploadRequests.HandleFunc("/{id}", uploadRequestHandler.Delete).Methods(http.MethodDelete, http.MethodOptions)
+
+	collections := protected.PathPrefix("/collections").Subrouter()
+	collections.Use(middleware.RequireSession())
+	collections.HandleFunc("", collectionHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	collections.HandleFunc("", collectionHandler.Create).Methods(http.MethodPost, http.MethodOptions)
+	collections.HandleFunc("/{id}", collectionHandler.GetByID).Methods(http.MethodGet, http.MethodOptions)
+	collections.HandleFunc("/{id}", collectionHandler.Update).Methods(http.MethodPut, http.MethodOptions)
+	collections.HandleFunc("/{id}", collectionHandler.Delete).Methods(http.MethodDelete, http.MethodOptions)
+	collections.Handle("/{id}/share", verified(http.HandlerFunc(collectionHandler.Share))).Methods(http.MethodPost, http.MethodOptions)
+
+	// Organization files can be listed with a personal access token; managing
+	// the organization needs a session.
+	protected.HandleFunc("/organizations/{id}/files", fileHandler.ListOrganizationFiles).Methods(http.MethodGet, http.MethodOptions)
+	protected.HandleFunc("/organizations/{id}/files/search", fileHandler.SearchOrganizationFiles).Methods(http.MethodGet, http.MethodOptions)
+
+	organizations := protected.PathPrefix("/organizations").Subrouter()
+	organizations.Use(middleware.RequireSession())
+	organizations.HandleFunc("", organizationHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	organizations.HandleFunc("", organizationHandler.Create).Methods(http.MethodPost, http.MethodOptions)
+	organizations.HandleFunc("/invitations", organizationHandler.ListMyInvitations).Methods(http.MethodGet, http.MethodOptions)
+	organizations.HandleFunc("/invitations/{invitationId}/accept", organizationHandler.AcceptInvitation).Methods(http.MethodPost, http.MethodOptions)
+	organizations.HandleFunc("/invitations/{invitationId}", organizationHandler.DeclineInvitation).Methods(http.MethodDelete, http.MethodOptions)
+	organizations.HandleFunc("/{id}", organizationHandler.Get).Methods(http.MethodGet, http.MethodOptions)
+	organizations.HandleFunc("/{id}", organizationHandler.Update).Methods(http.MethodPut, http.MethodOptions)
+	organizations.HandleFunc("/{id}", organizationHandler.Delete).Methods(http.MethodDelete, http.MethodOptions)
+	organizations.HandleFunc("/{id}/usage", organizationHandler.Usage).Methods(http.MethodGet, http.MethodOptions)
+	organizations.HandleFunc("/{id}/members", organizationHandler.ListMembers).Methods(http.MethodGet, http.MethodOptions)
+	organizations.HandleFunc("/{id}/members/{userId}", organizationHandler.UpdateMemberRole).Methods(http.MethodPut, http.MethodOptions)
+	organizations.HandleFunc("/{id}/members/{userId}", organizationHandler.RemoveMember).Methods(http.MethodDelete, http.MethodOptions)
+	organizations.HandleFunc("/{id}/invitations", organizationHandler.ListInvitations).Methods(http.MethodGet, http.MethodOptions)
+	organizations.HandleFunc("/{id}/invitations", organizationHandler.InviteMember).Methods(http.MethodPost, http.MethodOptions)
+	organizations.HandleFunc("/{id}/invitations/{invitationId}", organizationHandler.RevokeInvitation).Methods(http.MethodDelete, http.MethodOptions)
 
 	users := protected.PathPrefix("/users").Subrouter()
+	users.Use(middleware.RequireSession())
+	users.Use(middleware.RequireAdmin())
 	users.HandleFunc("", userHandler.GetAllUsers).Methods(http.MethodGet, http.MethodOptions)
 	users.HandleFunc("", userHandler.CreateUser).Methods(http.MethodPost, http.MethodOptions)
 	users.HandleFunc("/{id}", userHandler.GetUser).Methods(http.MethodGet, http.MethodOptions)
 	users.HandleFunc("/{id}", userHandler.UpdateUser).Methods(http.MethodPut, http.MethodOptions)
 	users.HandleFunc("/{id}", userHandler.DeleteUser).Methods(http.MethodDelete, http.MethodOptions)
+
+	tokens := protected.PathPrefix("/tokens").Subrouter()
+	tokens.Use(middleware.RequireSession())
+	tokens.Use(middleware.DenyImpersonation())
+	tokens.HandleFunc("", accessTokenHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	tokens.HandleFunc("", accessTokenHandler.Create).Methods(http.MethodPost, http.MethodOptions)
+	tokens.HandleFunc("/{id}", accessTokenHandler.Revoke).Methods(http.MethodDelete, http.MethodOptions)
+
+	mfa := protected.PathPrefix("/mfa").Subrouter()
+	mfa.Use(middleware.RequireSession())
+	mfa.Use(middleware.DenyImpersonation())
+	mfa.HandleFunc("", mfaHandler.Status).Methods(http.MethodGet, http.MethodOptions)
+	mfa.HandleFunc("/totp/enroll", mfaHandler.BeginEnrollment).Methods(http.MethodPost, http.MethodOptions)
+	mfa.HandleFunc("/totp/verify", mfaHandler.ConfirmEnrollment).Methods(http.MethodPost, http.MethodOptions)
+	mfa.HandleFunc("/totp/disable", mfaHandler.Disable).Methods(http.MethodPost, http.MethodOptions)
+	mfa.HandleFunc("/recovery-codes", mfaHandler.RegenerateRecoveryCodes).Methods(http.MethodPost, http.MethodOptions)
+
+	admin := protected.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.RequireSession())
+	admin.Use(middleware.RequireAdmin())
+	admin.HandleFunc("/reports", moderationHandler.ListReports).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/reports/{id}/resolve", moderationHandler.ResolveReport).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/files/{id}/release", moderationHandler.ReleaseFile).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/role", userHandler.UpdateRole).Methods(http.MethodPut, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/mfa", mfaHandler.ResetUser).Methods(http.MethodDelete, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/account", userHandler.GetAccountStatus).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/disable", userHandler.DisableUser).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/enable", userHandler.EnableUser).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/verify-email", userHandler.VerifyEmail).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/require-password-change", userHandler.RequirePasswordChange).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/temporary-password", userHandler.SetTemporaryPassword).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/settings/mfa", mfaHandler.GetPolicy).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/settings/mfa", mfaHandler.SetPolicy).Methods(http.MethodPut, http.MethodOptions)
+	admin.HandleFunc("/login-attempts", loginGuardHandler.ListAttempts).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/lockouts", loginGuardHandler.ListLockouts).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/lockouts/clear", loginGuardHandler.ClearLockout).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/organizations/{id}/quota", organizationHandler.SetStorageQuota).Methods(http.MethodPut, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/impersonate", impersonationHandler.Start).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/impersonations", impersonationHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/impersonations/{id}", impersonationHandler.End).Methods(http.MethodDelete, http.MethodOptions)
+	admin.HandleFunc("/audit", auditHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/erasure", privacyHandler.RequestUserErasure).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/users/{id}/erasure", privacyHandler.CancelUserErasure).Methods(http.MethodDelete, http.MethodOptions)
+	admin.HandleFunc("/erasures", privacyHandler.ListErasures).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/invitations", invitationHandler.List).Methods(http.MethodGet, http.MethodOptions)
+	admin.HandleFunc("/invitations", invitationHandler.Create).Methods(http.MethodPost, http.MethodOptions)
+	admin.HandleFunc("/invitations/{id}", invitationHandler.Revoke).Methods(http.MethodDelete, http.MethodOptions)
 }
 
 func (app *Application) run(ctx context.Context) error {
@@ -179,6 +622,8 @@ func (app *Application) run(ctx context.Context) error {
 		zap.String("env", app.config.Environment),
 		zap.String("version", "1.0.0"))
 
+	go app.processErasures(ctx)
+
 	serverErrors := make(chan error, 1)
 	go func() {
 		if err := app.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -205,6 +650,23 @@ func (app *Application) run(ctx context.Context) error {
 	return nil
 }
 
+// processErasures carries out due erasure requests until ctx is done.
+func (app *Application) processErasures(ctx context.Context) {
+	ticker := time.NewTicker(app.config.Privacy.ErasureCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := app.privacy.ProcessDue(ctx); err != nil {
+			app.logger.Error("Failed to process erasure requests", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (app *Application) cleanup() {
 	if app.db != nil {
 		if err := database.CloseDB(app.db); err != nil {
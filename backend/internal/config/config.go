@@ -3,24 +3,75 @@ package config
 
 import (
     "os"
+    "strconv"
+    "strings"
+    "time"
 )
 
 type Config struct {
-    Port        string
-    Environment string
-    Database    DatabaseConfig
-    JWT         JWTConfig
-    File        FileConfig
+    Port              string
+    Environment       string
+    Database          DatabaseConfig
+    JWT               JWTConfig
+    File              FileConfig
+    Mail              MailConfig
+    Admin             AdminConfig
+    MFA               MFAConfig
+    PasswordReset     PasswordResetConfig
+    EmailVerification EmailVerificationConfig
+    OIDC              OIDCConfig
+    LoginGuard        LoginGuardConfig
+    Organization      OrganizationConfig
+    Impersonation     ImpersonationConfig
+    Privacy           PrivacyConfig
+    Registration      RegistrationConfig
+    PasswordHash      PasswordHashConfig
+    PasswordPolicy    PasswordPolicyConfig
 }
 
 type DatabaseConfig struct {
     DBPath string
+    // UserStore selects the user repository backend: "sqlite" (default)
+    // or "memory", which loses every account on restart.
+    UserStore string
+    // UserSeedFile is an optional JSON array of users imported at startup.
+    UserSeedFile string
 }
 
+const (
+    UserStoreSQLite = "sqlite"
+    UserStoreMemory = "memory"
+)
+
 type JWTConfig struct {
+    // Secret signs HS256 access tokens when no signing key is configured.
     Secret string
+    // Algorithm is HS256, RS256 or EdDSA. It may be left empty when
+    // SigningKeyFile is set, since the key determines it. RS256 or EdDSA
+    // without a key file uses a throwaway key generated at startup.
+    Algorithm string
+    // SigningKeyFile is a PEM private key that new access tokens are
+    // signed with.
+    SigningKeyFile string
+    // VerificationKeyFiles are PEM keys that tokens are still accepted
+    // with and that are published in the JWKS: retired keys until their
+    // tokens expire, or the next key before it starts signing.
+    VerificationKeyFiles []string
+    Issuer               string
+    Audience             string
+    AccessTokenTTL       time.Duration
+    RefreshTokenTTL      time.Duration
 }
 
+const (
+    JWTAlgorithmHS256 = "HS256"
+    JWTAlgorithmRS256 = "RS256"
+    JWTAlgorithmEdDSA = "EdDSA"
+
+    // DefaultJWTSecret is only fit for development.
+    DefaultJWTSecret = "your-default-secret-key"
+)
+
 type FileConfig struct {
     UploadDir    string
     MaxSize      int64
@@ -28,15 +79,157 @@ type FileConfig struct {
     BaseURL      string
 }
 
+const (
+    MailDriverSMTP = "smtp"
+    MailDriverLog  = "log"
+)
+
+type MailConfig struct {
+    // Driver selects how mail is delivered: "smtp", or "log" to print
+    // messages to the application log during development.
+    Driver   string
+    Host     string
+    Port     string
+    Username string
+    Password string
+    From     string
+}
+
+// AdminConfig lists the bootstrap admins: accounts with these emails are
+// given the admin role once the address is verified, when the server starts
+// or when the link is followed.
+type AdminConfig struct {
+    Emails []string
+}
+
+type PasswordResetConfig struct {
+    // URL is the frontend page reset links point to.
+    URL      string
+    TokenTTL time.Duration
+}
+
+type EmailVerificationConfig struct {
+    // Required blocks uploads and sharing until the user confirms their
+    // email address.
+    Required bool
+    // URL is the frontend page verification links point to.
+    URL      string
+    TokenTTL time.Duration
+}
+
+type OIDCConfig struct {
+    Providers []OIDCProviderConfig
+    // RedirectBaseURL is the public backend URL identity providers send
+    // users back to, at /api/auth/oidc/{provider}/callback.
+    RedirectBaseURL string
+    // FrontendCallbackURL receives the one-time login ticket, or an error,
+    // in its URL fragment after a sign-in.
+    FrontendCallbackURL string
+}
+
+// OIDCProviderConfig describes one identity provider. Providers are listed
+// in OIDC_PROVIDERS and configured through OIDC_<NAME>_* variables.
+type OIDCProviderConfig struct {
+    Name         string
+    DisplayName  string
+    Issuer       string
+    ClientID     string
+    ClientSecret string
+    Scopes       []string
+}
+
+// LoginGuardConfig controls brute-force protection on password logins.
+type LoginGuardConfig struct {
+    // MaxFailures failed logins for one account within Window lock it for
+    // LockoutDuration. Failures before that delay the next attempt.
+    MaxFailures     int
+    Window          time.Duration
+    LockoutDuration time.Duration
+    // IPMaxFailures failed logins from one address lock that address out.
+    IPMaxFailures int
+}
+
+type OrganizationConfig struct {
+    // DefaultStorageQuota is the storage, in bytes, new organizations
+    // start with. Zero means unlimited.
+    DefaultStorageQuota int64
+}
+
+// ImpersonationConfig bounds how long admins may act as another user.
+type ImpersonationConfig struct {
+    DefaultDuration time.Duration
+    MaxDuration     time.Duration
+}
+
+// RegistrationConfig decides who may sign up.
+type RegistrationConfig struct {
+    // Mode is "open" (default), "invite" or "domain".
+    Mode string
+    // AllowedDomains are the email domains that may sign up in "domain"
+    // mode without an invitation.
+    AllowedDomains []string
+    // InvitationURL is the frontend page invitation links point to.
+    InvitationURL string
+    InvitationTTL time.Duration
+}
+
+// PasswordHashConfig sets the argon2id cost of new password hashes. Hashes
+// made with other settings are upgraded when their owner next logs in.
+type PasswordHashConfig struct {
+    // Memory is in KiB.
+    Memory      int
+    Iterations  int
+    Parallelism int
+}
+
+// PasswordPolicyConfig sets the rules for passwords users choose.
+type PasswordPolicyConfig struct {
+    MinLength     int
+    MaxLength     int
+    RequireUpper  bool
+    RequireLower  bool
+    RequireDigit  bool
+    RequireSymbol bool
+    // DisallowEmail refuses passwords containing the account's email
+    // address.
+    DisallowEmail bool
+    // BreachedListFile is an optional sorted file of SHA-1 hashes of
+    // breached passwords, which are refused.
+    BreachedListFile string
+}
+
+// PrivacyConfig controls account erasure.
+type PrivacyConfig struct {
+    // ErasureGracePeriod is how long a user has to cancel an erasure
+    // request before it is carried out.
+    ErasureGracePeriod time.Duration
+    // ErasureCheckInterval is how often due erasures are looked for.
+    ErasureCheckInterval time.Duration
+}
+
+type MFAConfig struct {
+    // Issuer is the account name authenticator apps show for this service.
+    Issuer string
+}
+
 func NewConfig() *Config {
     return &Config{
         Port:        getEnvOrDefault("PORT", "8080"),
         Environment: getEnvOrDefault("ENV", "development"),
         Database: DatabaseConfig{
-            DBPath: getEnvOrDefault("DB_PATH", "./database.db"),
+            DBPath:       getEnvOrDefault("DB_PATH", "./database.db"),
+            UserStore:    strings.ToLower(getEnvOrDefault("USER_STORE", UserStoreSQLite)),
+            UserSeedFile: os.Getenv("USER_SEED_FILE"),
         },
         JWT: JWTConfig{
-            Secret: getEnvOrDefault("JWT_SECRET", "your-default-secret-key"),
+            Secret:               getEnvOrDefault("JWT_SECRET", DefaultJWTSecret),
+            Algorithm:            os.Getenv("JWT_ALGORITHM"),
+            SigningKeyFile:       os.Getenv("JWT_SIGNING_KEY_FILE"),
+            VerificationKeyFiles: getEnvList("JWT_VERIFICATION_KEY_FILES"),
+            Issuer:               getEnvOrDefault("JWT_ISSUER", getEnvOrDefault("BACKEND_URL", "http://localhost:8080")),
+            Audience:             getEnvOrDefault("JWT_AUDIENCE", "pdf-manager"),
+            AccessTokenTTL:       getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+            RefreshTokenTTL:      getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
         },
         File: FileConfig{
             UploadDir:    getEnvOrDefault("UPLOAD_DIR", "./uploads"),
@@ -50,6 +243,72 @@ func NewConfig() *Config {
             },
             BaseURL: getEnvOrDefault("BACKEND_URL", "http://localhost:8080"),
         },
+        Mail: MailConfig{
+            Driver:   strings.ToLower(getEnvOrDefault("MAIL_DRIVER", MailDriverSMTP)),
+            Host:         os.Getenv("SMTP_HOST"),
+            Port:     getEnvOrDefault("SMTP_PORT", "587"),
+            Username: os.Getenv("SMTP_USERNAME"),
+            Password: os.Getenv("SMTP_PASSWORD"),
+            From:     getEnvOrDefault("MAIL_FROM", "no-reply@localhost"),
+        },
+        Admin: AdminConfig{
+            Emails: getEnvList("ADMIN_EMAILS"),
+        },
+        PasswordReset: PasswordResetConfig{
+            URL:      getEnvOrDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+            TokenTTL: getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+        },
+        EmailVerification: EmailVerificationConfig{
+            Required: getEnvBool("EMAIL_VERIFICATION_REQUIRED", true),
+            URL:      getEnvOrDefault("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email"),
+            TokenTTL: getEnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+        },
+        OIDC: OIDCConfig{
+            Providers:           getOIDCProviders(),
+            RedirectBaseURL:     getEnvOrDefault("OIDC_REDIRECT_BASE_URL", getEnvOrDefault("BACKEND_URL", "http://localhost:8080")),
+            FrontendCallbackURL: getEnvOrDefault("OIDC_FRONTEND_CALLBACK_URL", "http://localhost:3000/oidc/callback"),
+        },
+        LoginGuard: LoginGuardConfig{
+            MaxFailures:     getEnvInt("LOGIN_MAX_FAILURES", 5),
+            Window:          getEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+            LockoutDuration: getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+            IPMaxFailures:   getEnvInt("LOGIN_IP_MAX_FAILURES", 20),
+        },
+        Organization: OrganizationConfig{
+            DefaultStorageQuota: int64(getEnvInt("ORGANIZATION_STORAGE_QUOTA", 0)),
+        },
+        Impersonation: ImpersonationConfig{
+            DefaultDuration: getEnvDuration("IMPERSONATION_DEFAULT_DURATION", 30*time.Minute),
+            MaxDuration:     getEnvDuration("IMPERSONATION_MAX_DURATION", 2*time.Hour),
+        },
+        Registration: RegistrationConfig{
+            Mode:           strings.ToLower(getEnvOrDefault("REGISTRATION_MODE", "open")),
+            AllowedDomains: getEnvList("REGISTRATION_ALLOWED_DOMAINS"),
+            InvitationURL:  getEnvOrDefault("INVITATION_URL", "http://localhost:3000/register"),
+            InvitationTTL:  getEnvDuration("INVITATION_TTL", 7*24*time.Hour),
+        },
+        PasswordHash: PasswordHashConfig{
+            Memory:      getEnvInt("PASSWORD_ARGON2_MEMORY", 19*1024),
+            Iterations:  getEnvInt("PASSWORD_ARGON2_ITERATIONS", 2),
+            Parallelism: getEnvInt("PASSWORD_ARGON2_PARALLELISM", 1),
+        },
+        PasswordPolicy: PasswordPolicyConfig{
+            MinLength:        getEnvInt("PASSWORD_MIN_LENGTH", 8),
+            MaxLength:        getEnvInt("PASSWORD_MAX_LENGTH", 128),
+            RequireUpper:     getEnvBool("PASSWORD_REQUIRE_UPPERCASE", true),
+            RequireLower:     getEnvBool("PASSWORD_REQUIRE_LOWERCASE", true),
+            RequireDigit:     getEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+            RequireSymbol:    getEnvBool("PASSWORD_REQUIRE_SYMBOL", true),
+            DisallowEmail:    getEnvBool("PASSWORD_DISALLOW_EMAIL", true),
+            BreachedListFile: os.Getenv("PASSWORD_BREACHED_LIST_FILE"),
+        },
+        Privacy: PrivacyConfig{
+            ErasureGracePeriod:   getEnvDuration("ERASURE_GRACE_PERIOD", 7*24*time.Hour),
+            ErasureCheckInterval: getEnvDuration("ERASURE_CHECK_INTERVAL", time.Hour),
+        },
+        MFA: MFAConfig{
+            Issuer: getEnvOrDefault("MFA_ISSUER", "PDF Manager"),
+        },
     }
 }
 
@@ -59,3 +318,68 @@ func getEnvOrDefault(key, defaultValue string) string {
     }
     return defaultValue
 }
+
+// getEnvDuration reads a Go duration such as "15m", falling back to
+// defaultValue when the variable is unset or invalid.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+    if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+        return d
+    }
+    return defaultValue
+}
+
+// getOIDCProviders reads the providers named in OIDC_PROVIDERS, e.g.
+// "corp" is configured by OIDC_CORP_ISSUER, OIDC_CORP_CLIENT_ID,
+// OIDC_CORP_CLIENT_SECRET and optionally OIDC_CORP_SCOPES and
+// OIDC_CORP_DISPLAY_NAME.
+func getOIDCProviders() []OIDCProviderConfig {
+    var providers []OIDCProviderConfig
+    for _, name := range getEnvList("OIDC_PROVIDERS") {
+        name = strings.ToLower(name)
+        prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+        scopes := getEnvList(prefix + "SCOPES")
+        if len(scopes) == 0 {
+            scopes = []string{"openid", "email", "profile"}
+        }
+        providers = append(providers, OIDCProviderConfig{
+            Name:         name,
+            DisplayName:  getEnvOrDefault(prefix+"DISPLAY_NAME", name),
+            Issuer:       strings.TrimSuffix(os.Getenv(prefix+"ISSUER"), "/"),
+            ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+            ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+            Scopes:       scopes,
+        })
+    }
+    return providers
+}
+
+// getEnvInt reads a positive integer, falling back to defaultValue when
+// the variable is unset or invalid.
+func getEnvInt(key string, defaultValue int) int {
+    if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n > 0 {
+        return n
+    }
+    return defaultValue
+}
+
+// getEnvBool reads a boolean such as "true" or "0", falling back to
+// defaultValue when the variable is unset or invalid.
+func getEnvBool(key string, defaultValue bool) bool {
+    if b, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+        return b
+    }
+    return defaultValue
+}
+
+// getEnvList reads a comma-separated environment variable, dropping empty
+// entries.
+func getEnvList(key string) []string {
+    var values []string
+    for _, v := range strings.Split(os.Getenv(key), ",") {
+        if v = strings.TrimSpace(v); v != "" {
+            values = append(values, v)
+        }
+    }
+    return values
+}
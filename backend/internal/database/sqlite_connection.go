@@ -46,7 +46,12 @@ func SetupDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
 	err = db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.AutoMigrate(&domain.File{}); err != nil {
+		// Accounts that predate email verification are treated as verified
+		// rather than locked out of uploading.
+		backfillVerified := tx.Migrator().HasTable(&domain.User{}) &&
+			!tx.Migrator().HasColumn(&domain.User{}, "EmailVerifiedAt")
+
+		if err := tx.AutoMigrate(&domain.User{}, &domain.File{}, &domain.UploadRequest{}, &domain.Collection{}, &domain.ShareRecipient{}, &domain.AbuseReport{}, &domain.RefreshToken{}, &domain.Session{}, &domain.RevokedToken{}, &domain.UserRevocation{}, &domain.PersonalAccessToken{}, &domain.TOTPCredential{}, &domain.RecoveryCode{}, &domain.MFAChallenge{}, &domain.Setting{}, &domain.PasswordResetToken{}, &domain.EmailVerificationToken{}, &domain.UserIdentity{}, &domain.OIDCLoginState{}, &domain.OIDCLoginTicket{}, &domain.LoginThrottle{}, &domain.LoginAttempt{}, &domain.LockoutEvent{}, &domain.Organization{}, &domain.OrganizationMember{}, &domain.OrganizationInvitation{}, &domain.Impersonation{}, &domain.AuditEvent{}, &domain.ErasureRequest{}, &domain.Invitation{}); err != nil {
 			return fmt.Errorf("failed to migrate schema: %w", err)
 		}
 
@@ -54,6 +59,12 @@ func SetupDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
 			return fmt.Errorf("failed to populate content_type: %w", err)
 		}
 
+		if backfillVerified {
+			if err := tx.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL").Error; err != nil {
+				return fmt.Errorf("failed to backfill email_verified_at: %w", err)
+			}
+		}
+
 		return nil
 	})
 
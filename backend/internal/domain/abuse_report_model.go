@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+const (
+	AbuseReasonCopyright = "copyright"
+	AbuseReasonMalware   = "malware"
+	AbuseReasonPhishing  = "phishing"
+	AbuseReasonIllegal   = "illegal"
+	AbuseReasonOther     = "other"
+)
+
+const (
+	AbuseReportOpen      = "open"
+	AbuseReportDismissed = "dismissed"
+	AbuseReportActioned  = "actioned"
+)
+
+// Moderation actions an admin can take when resolving a report.
+const (
+	ModerationDismiss      = "dismiss"
+	ModerationDisableShare = "disable_share"
+	ModerationQuarantine   = "quarantine"
+)
+
+var AbuseReasons = map[string]bool{
+	AbuseReasonCopyright: true,
+	AbuseReasonMalware:   true,
+	AbuseReasonPhishing:  true,
+	AbuseReasonIllegal:   true,
+	AbuseReasonOther:     true,
+}
+
+// AbuseReport is a complaint about a public share link, filed by anyone who
+// can reach it.
+type AbuseReport struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	FileID        uint       `json:"fileId" gorm:"not null;index"`
+	ShareID       string     `json:"shareId" gorm:"not null"`
+	Reason        string     `json:"reason" gorm:"not null"`
+	Details       string     `json:"details"`
+	ReporterEmail string     `json:"reporterEmail,omitempty"`
+	ReporterIP    string     `json:"reporterIp"`
+	Status        string     `json:"status" gorm:"not null;index;default:open"`
+	Action        string     `json:"action,omitempty"`
+	Note          string     `json:"note,omitempty"`
+	ResolvedBy    *uint      `json:"resolvedBy,omitempty"`
+	ResolvedAt    *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+type AbuseReportRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+	Email   string `json:"email"`
+}
+
+type ResolveReportRequest struct {
+	Action string `json:"action"`
+	Note   string `json:"note"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Scope limits what a personal access token may be used for.
+type Scope string
+
+const (
+	ScopeFilesRead  Scope = "files:read"
+	ScopeFilesWrite Scope = "files:write"
+	ScopeShare      Scope = "share"
+)
+
+// AccessTokenPrefix starts every personal access token, which is how
+// AuthMiddleware tells them apart from JWTs.
+const AccessTokenPrefix = "pat_"
+
+func (s Scope) Valid() bool {
+	return s == ScopeFilesRead || s == ScopeFilesWrite || s == ScopeShare
+}
+
+// PersonalAccessToken lets scripts call the API on a user's behalf without
+// their password. Only a hash of the secret is stored; Prefix keeps enough
+// of it for the user to recognise the token in a list.
+type PersonalAccessToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"userId" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"not null"`
+	TokenHash  string     `json:"-" gorm:"not null;uniqueIndex"`
+	Scopes     string     `json:"-" gorm:"not null"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func (t *PersonalAccessToken) OwnerID() uint {
+	return t.UserID
+}
+
+func (t *PersonalAccessToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+func (t *PersonalAccessToken) ScopeList() []Scope {
+	var scopes []Scope
+	for _, s := range strings.Split(t.Scopes, ",") {
+		if s != "" {
+			scopes = append(scopes, Scope(s))
+		}
+	}
+	return scopes
+}
+
+// AccessTokenInfo is how a token is shown to its owner.
+type AccessTokenInfo struct {
+	*PersonalAccessToken
+	ScopeNames []Scope `json:"scopes"`
+}
+
+func (t *PersonalAccessToken) ToInfo() AccessTokenInfo {
+	return AccessTokenInfo{PersonalAccessToken: t, ScopeNames: t.ScopeList()}
+}
+
+// CreatedAccessToken is returned once, when the token is created. Token is
+// the only time the secret is ever shown.
+type CreatedAccessToken struct {
+	AccessTokenInfo
+	Token string `json:"token"`
+}
+
+type CreateAccessTokenRequest struct {
+	Name      string     `json:"name" example:"nightly-report-upload"`
+	Scopes    []Scope    `json:"scopes" example:"files:write"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
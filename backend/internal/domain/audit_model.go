@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// AuditAction names what an audit event records.
+type AuditAction string
+
+const (
+	AuditImpersonationStarted AuditAction = "impersonation.started"
+	AuditImpersonationEnded   AuditAction = "impersonation.ended"
+	// AuditImpersonatedRequest is one API request made while impersonating.
+	AuditImpersonatedRequest AuditAction = "impersonation.request"
+
+	AuditErasureRequested AuditAction = "erasure.requested"
+	AuditErasureCancelled AuditAction = "erasure.cancelled"
+	AuditErasureCompleted AuditAction = "erasure.completed"
+
+	AuditUserDisabled           AuditAction = "user.disabled"
+	AuditUserEnabled            AuditAction = "user.enabled"
+	AuditPasswordChangeRequired AuditAction = "user.password_change_required"
+	AuditTemporaryPasswordSet   AuditAction = "user.temporary_password_set"
+	AuditEmailVerified          AuditAction = "user.email_verified"
+)
+
+// AuditEvent is an entry in the audit trail. ActorID is who did it and
+// SubjectID whose account it was done to or as.
+type AuditEvent struct {
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	Action          AuditAction `json:"action" gorm:"not null;index"`
+	ActorID         uint        `json:"actorId" gorm:"not null;index"`
+	SubjectID       uint        `json:"subjectId,omitempty" gorm:"index"`
+	ImpersonationID string      `json:"impersonationId,omitempty" gorm:"index"`
+	Method          string      `json:"method,omitempty"`
+	Path            string      `json:"path,omitempty"`
+	Status          int         `json:"status,omitempty"`
+	IP              string      `json:"ip,omitempty"`
+	Details         string      `json:"details,omitempty"`
+	CreatedAt       time.Time   `json:"createdAt" gorm:"index"`
+}
+
+// AuditFilter narrows an audit trail listing. Zero fields match everything.
+type AuditFilter struct {
+	// UserID matches events where the user is the actor or the subject.
+	UserID          uint
+	ImpersonationID string
+	Action          AuditAction
+	Limit           int
+}
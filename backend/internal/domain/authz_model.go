@@ -0,0 +1,107 @@
+package domain
+
+import "context"
+
+// Action is an operation an actor wants to perform on a resource.
+type Action string
+
+const (
+	ActionCreate   Action = "create"
+	ActionRead     Action = "read"
+	ActionDownload Action = "download"
+	ActionShare    Action = "share"
+	ActionUpdate   Action = "update"
+	ActionDelete   Action = "delete"
+	// ActionListAll covers listing resources across every user.
+	ActionListAll Action = "list_all"
+)
+
+// Actor is the authenticated caller on whose behalf an operation runs.
+type Actor struct {
+	UserID uint
+	Admin  bool
+	// TokenID is set when the caller authenticated with a personal access
+	// token, in which case only Scopes are granted.
+	TokenID uint
+	Scopes  []Scope
+}
+
+// HasScope reports whether the actor's credentials allow scope. Sessions
+// from a password login carry every scope.
+func (a Actor) HasScope(scope Scope) bool {
+	if a.TokenID == 0 {
+		return true
+	}
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource is anything owned by a single user that access can be checked
+// against.
+type Resource interface {
+	OwnerID() uint
+}
+
+// OrganizationResource is a resource that may belong to an organization,
+// whose members then get access according to their role.
+type OrganizationResource interface {
+	Resource
+	// Organization returns the owning organization's ID, or zero.
+	Organization() uint
+}
+
+// OwnedBy stands for everything a user owns, for checks such as listing a
+// user's files.
+type OwnedBy uint
+
+func (o OwnedBy) OwnerID() uint {
+	return uint(o)
+}
+
+// InOrganization stands for everything in an organization, for checks such
+// as listing its files.
+type InOrganization uint
+
+func (o InOrganization) OwnerID() uint {
+	return 0
+}
+
+func (o InOrganization) Organization() uint {
+	return uint(o)
+}
+
+type actorContextKey struct{}
+
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx. Requests without one are
+// treated as anonymous, which is never authorized for owned resources.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorContextKey{}).(Actor)
+	return actor
+}
+
+func (f *File) OwnerID() uint {
+	return f.UserID
+}
+
+func (f *File) Organization() uint {
+	if f.OrganizationID == nil {
+		return 0
+	}
+	return *f.OrganizationID
+}
+
+func (c *Collection) OwnerID() uint {
+	return c.UserID
+}
+
+func (u *UploadRequest) OwnerID() uint {
+	return u.UserID
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Collection groups several of a user's files so they can be shared through
+// a single link.
+type Collection struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"userId" gorm:"not null;index"`
+	Name        string    `json:"name" gorm:"not null"`
+	ShareableID string    `json:"shareableId" gorm:"index"`
+	Files       []File    `json:"files" gorm:"many2many:collection_files;"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type CollectionRequest struct {
+	Name    string `json:"name"`
+	FileIDs []uint `json:"fileIds"`
+}
+
+// SharedCollectionFile is the public view of a file inside a shared collection.
+type SharedCollectionFile struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	MimeType    string `json:"mimeType"`
+	Size        int64  `json:"size"`
+	DownloadURL string `json:"downloadUrl"`
+}
+
+type SharedCollection struct {
+	Name      string                 `json:"name"`
+	Files     []SharedCollectionFile `json:"files"`
+	TotalSize int64                  `json:"totalSize"`
+	ZipURL    string                 `json:"zipUrl"`
+}
+
+func (c *Collection) ToShared(baseURL string) SharedCollection {
+	shared := SharedCollection{
+		Name:   c.Name,
+		Files:  make([]SharedCollectionFile, 0, len(c.Files)),
+		ZipURL: fmt.Sprintf("%s/shared/collections/%s/zip", baseURL, c.ShareableID),
+	}
+	for _, f := range c.Files {
+		shared.Files = append(shared.Files, SharedCollectionFile{
+			ID:          f.ID,
+			Name:        f.Name,
+			MimeType:    f.MimeType,
+			Size:        f.Size,
+			DownloadURL: fmt.Sprintf("%s/shared/collections/%s/files/%d", baseURL, c.ShareableID, f.ID),
+		})
+		shared.TotalSize += f.Size
+	}
+	return shared
+}
+
+// FileByID returns the member file with the given ID, if it is part of the collection.
+func (c *Collection) FileByID(id uint) (*File, bool) {
+	for i := range c.Files {
+		if c.Files[i].ID == id {
+			return &c.Files[i], true
+		}
+	}
+	return nil, false
+}
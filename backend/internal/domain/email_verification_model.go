@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// EmailVerificationToken is emailed to a new user to prove they own their
+// address. Only its hash is stored.
+type EmailVerificationToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
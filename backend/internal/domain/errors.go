@@ -11,14 +11,20 @@ const (
 	ErrCodeAuthentication   = 4001
 	ErrCodeAuthorization    = 4003
 	
-	ErrCodeNotFound        = 4004
-	ErrCodeConflict        = 4009
-	ErrCodeInternal        = 5000
-	ErrCodeUserNotFound    = 4040
-	ErrCodeDuplicateEmail  = 4010
-	ErrCodeInvalidFileType = 4011
-	ErrCodeFileTooLarge    = 4012
-	ErrCodeFileNotFound    = 4041
+	ErrCodeNotFound         = 4004
+	ErrCodeConflict         = 4009
+	ErrCodeInternal         = 5000
+	ErrCodeUserNotFound     = 4040
+	ErrCodeDuplicateEmail   = 4010
+	ErrCodeInvalidFileType  = 4011
+	ErrCodeFileTooLarge     = 4012
+	ErrCodeFileNotFound     = 4041
+	ErrCodeLinkExpired      = 4100
+	ErrCodeTokenRevoked     = 4013
+	ErrCodeEmailNotVerified = 4014
+	ErrCodeQuotaExceeded    = 4015
+	ErrCodeQuarantined      = 4510
+	ErrCodeShareDisabled    = 4511
 )
 
 type APIError struct {
@@ -124,6 +130,239 @@ var (
 		"File not found",
 		nil,
 	)
+
+	ErrLinkExpired = NewAPIError(
+		http.StatusGone,
+		ErrCodeLinkExpired,
+		"Link has expired",
+		nil,
+	)
+
+	ErrInvalidRefreshToken = NewAPIError(
+		http.StatusUnauthorized,
+		ErrCodeAuthentication,
+		"Invalid or expired refresh token",
+		nil,
+	)
+
+	// ErrRefreshTokenReused means a rotated refresh token was presented
+	// again, which suggests it was stolen.
+	ErrRefreshTokenReused = NewAPIError(
+		http.StatusUnauthorized,
+		ErrCodeTokenRevoked,
+		"Refresh token has already been used",
+		nil,
+	)
+
+	ErrMemberNotFound = NewAPIError(
+		http.StatusNotFound,
+		ErrCodeNotFound,
+		"Member not found",
+		nil,
+	)
+
+	ErrOrgInvitationNotFound = NewAPIError(
+		http.StatusNotFound,
+		ErrCodeNotFound,
+		"Invitation not found or expired",
+		nil,
+	)
+
+	// ErrOrgInvitationUnverified keeps someone who signed up with another
+	// person's address from joining the organizations they were invited to.
+	ErrOrgInvitationUnverified = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeEmailNotVerified,
+		"Verify your email address to see and accept invitations",
+		nil,
+	)
+
+	ErrSessionNotFound = NewAPIError(
+		http.StatusNotFound,
+		ErrCodeNotFound,
+		"Session not found",
+		nil,
+	)
+
+	ErrInvalidMFACode = NewAPIError(
+		http.StatusUnauthorized,
+		ErrCodeAuthentication,
+		"Invalid verification code",
+		nil,
+	)
+
+	ErrInvalidMFAChallenge = NewAPIError(
+		http.StatusUnauthorized,
+		ErrCodeAuthentication,
+		"Invalid or expired login challenge",
+		nil,
+	)
+
+	ErrInvalidResetToken = NewAPIError(
+		http.StatusBadRequest,
+		ErrCodeInvalidInput,
+		"Invalid or expired password reset token",
+		nil,
+	)
+
+	ErrInvalidVerificationToken = NewAPIError(
+		http.StatusBadRequest,
+		ErrCodeInvalidInput,
+		"Invalid or expired email verification token",
+		nil,
+	)
+
+	ErrEmailNotVerified = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeEmailNotVerified,
+		"Verify your email address before uploading or sharing files",
+		nil,
+	)
+
+	ErrInvalidOIDCState = NewAPIError(
+		http.StatusBadRequest,
+		ErrCodeInvalidInput,
+		"Invalid or expired sign-in attempt, please try again",
+		nil,
+	)
+
+	ErrInvalidOIDCTicket = NewAPIError(
+		http.StatusUnauthorized,
+		ErrCodeAuthentication,
+		"Invalid or expired sign-in ticket",
+		nil,
+	)
+
+	ErrOIDCEmailUnverified = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeAuthorization,
+		"The identity provider did not confirm your email address",
+		nil,
+	)
+
+	// ErrOIDCAccountUnverified stops a provider sign-in from taking over an
+	// account whose owner never proved the address, which anyone could have
+	// registered in advance.
+	ErrOIDCAccountUnverified = NewAPIError(
+		http.StatusConflict,
+		ErrCodeEmailNotVerified,
+		"An account with this email already exists; verify its email address before signing in with this provider",
+		nil,
+	)
+
+	// ErrInvalidCredentials is returned for both unknown emails and wrong
+	// passwords so logins cannot be used to discover accounts.
+	ErrInvalidCredentials = NewAPIError(
+		http.StatusUnauthorized,
+		ErrCodeAuthentication,
+		"Invalid email or password",
+		nil,
+	)
+
+	ErrIncorrectPassword = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeAuthorization,
+		"Current password is incorrect",
+		nil,
+	)
+
+	ErrLastAdmin = NewAPIError(
+		http.StatusConflict,
+		ErrCodeConflict,
+		"Cannot remove the last admin",
+		nil,
+	)
+
+	ErrAccountDisabled = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeAuthorization,
+		"Account is disabled",
+		nil,
+	)
+
+	ErrTooManyLoginAttempts = NewAPIError(
+		http.StatusTooManyRequests,
+		ErrCodeAuthentication,
+		"Too many failed login attempts, please try again later",
+		nil,
+	)
+
+	ErrInsufficientScope = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeAuthorization,
+		"Access token does not have the required scope",
+		nil,
+	)
+
+	ErrTokenRevoked = NewAPIError(
+		http.StatusUnauthorized,
+		ErrCodeTokenRevoked,
+		"Token has been revoked",
+		nil,
+	)
+
+	ErrImpersonationRestricted = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeAuthorization,
+		"Not allowed while impersonating a user",
+		nil,
+	)
+
+	ErrImpersonationNotFound = NewAPIError(
+		http.StatusNotFound,
+		ErrCodeNotFound,
+		"Impersonation session not found",
+		nil,
+	)
+
+	ErrErasureNotFound = NewAPIError(
+		http.StatusNotFound,
+		ErrCodeNotFound,
+		"No pending erasure request",
+		nil,
+	)
+
+	ErrRegistrationClosed = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeAuthorization,
+		"Registration is by invitation only",
+		nil,
+	)
+
+	ErrInvitationInvalid = NewAPIError(
+		http.StatusForbidden,
+		ErrCodeAuthorization,
+		"Invitation is invalid, used or expired",
+		nil,
+	)
+
+	ErrInvitationNotFound = NewAPIError(
+		http.StatusNotFound,
+		ErrCodeNotFound,
+		"Invitation not found",
+		nil,
+	)
+
+	ErrStorageQuotaExceeded = NewAPIError(
+		http.StatusRequestEntityTooLarge,
+		ErrCodeQuotaExceeded,
+		"Organization storage quota exceeded",
+		nil,
+	)
+
+	ErrFileQuarantined = NewAPIError(
+		http.StatusUnavailableForLegalReasons,
+		ErrCodeQuarantined,
+		"File has been quarantined by a moderator",
+		nil,
+	)
+
+	ErrShareDisabled = NewAPIError(
+		http.StatusUnavailableForLegalReasons,
+		ErrCodeShareDisabled,
+		"Sharing of this file has been disabled by a moderator",
+		nil,
+	)
 )
 
 
@@ -10,6 +10,9 @@ import (
 type File struct {
 	ID          uint      `json:"id" gorm:"primary_key"`
 	UserID      uint      `json:"userId" gorm:"not null;index"`
+	// OrganizationID is set for files that belong to an organization rather
+	// than to UserID, who uploaded them.
+	OrganizationID *uint `json:"organizationId,omitempty" gorm:"index"`
 	Name        string    `json:"name" gorm:"not null;index"`
 	Path        string    `json:"-" gorm:"not null"`  
 	MimeType    string    `json:"mimeType" gorm:"not null"`
@@ -18,6 +21,22 @@ type File struct {
 	UpdatedAt   time.Time `json:"updatedAt"`
 	ShareableID string    `json:"shareableId" gorm:"index"`
 	ContentType string    `json:"contentType" gorm:"not null"`
+	// ShareExpiresAt ends the public link at that time. Links without an
+	// expiry stay valid until the file is shared again.
+	ShareExpiresAt *time.Time `json:"shareExpiresAt,omitempty"`
+
+	// PageCount is counted when a PDF is stored, so showing it on a share
+	// page does not read the file. It is nil when the count is unknown.
+	PageCount   *int `json:"pageCount,omitempty"`
+	Quarantined bool `json:"quarantined" gorm:"not null;default:false"`
+	// ShareDisabled is set when a moderator takes down the file's public
+	// link. The file stays available to its owner but cannot be shared again.
+	ShareDisabled bool `json:"shareDisabled" gorm:"not null;default:false"`
+
+	ExternallySubmitted bool   `json:"externallySubmitted" gorm:"not null;default:false"`
+	UploadRequestID     *uint  `json:"uploadRequestId,omitempty" gorm:"index"`
+	SubmitterName       string `json:"submitterName,omitempty"`
+	SubmitterEmail      string `json:"submitterEmail,omitempty"`
 }
 
 
@@ -71,6 +90,12 @@ func NewFile(userID uint, name string, size int64, contentType string) (*File, e
 	}, nil
 }
 
+// IsShareExpired reports whether the public link of the file has passed its
+// expiry. Links without an expiry never expire.
+func (f *File) IsShareExpired() bool {
+	return f.ShareExpiresAt != nil && time.Now().After(*f.ShareExpiresAt)
+}
+
 func (f *File) ToDTO(baseURL string) FileDTO {
 	return FileDTO{
 		ID:          f.ID,
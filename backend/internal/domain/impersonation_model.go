@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// Impersonation is a time-limited session an administrator holds as another
+// user to reproduce a support issue. Its ID doubles as the ID of the user
+// session it runs in, so ending either cuts off its token.
+type Impersonation struct {
+	ID      string `json:"id" gorm:"primaryKey"`
+	AdminID uint   `json:"adminId" gorm:"not null;index"`
+	UserID  uint   `json:"userId" gorm:"not null;index"`
+	Reason  string `json:"reason" gorm:"not null"`
+	// AllowDestructive lets the administrator change things as the user.
+	// Without it the impersonation is read-only.
+	AllowDestructive bool       `json:"allowDestructive"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	ExpiresAt        time.Time  `json:"expiresAt" gorm:"not null"`
+	EndedAt          *time.Time `json:"endedAt,omitempty"`
+}
+
+func (i *Impersonation) IsActive() bool {
+	return i.EndedAt == nil && time.Now().Before(i.ExpiresAt)
+}
+
+type StartImpersonationRequest struct {
+	// Reason is recorded in the audit trail, e.g. a ticket reference.
+	Reason string `json:"reason" example:"Ticket #4821: upload fails"`
+	// DurationMinutes defaults to IMPERSONATION_DEFAULT_DURATION and is
+	// capped at IMPERSONATION_MAX_DURATION.
+	DurationMinutes  int  `json:"durationMinutes,omitempty" example:"30"`
+	AllowDestructive bool `json:"allowDestructive,omitempty"`
+}
+
+// ImpersonationToken is handed to the administrator to act as the user.
+// It cannot be refreshed.
+type ImpersonationToken struct {
+	AccessToken   string         `json:"token"`
+	ExpiresIn     int64          `json:"expiresIn" example:"1800"`
+	Impersonation *Impersonation `json:"impersonation"`
+}
+
+// Impersonator describes who is acting as the current user, so clients can
+// flag the session.
+type Impersonator struct {
+	AdminID          uint      `json:"adminId"`
+	AdminEmail       string    `json:"adminEmail"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	AllowDestructive bool      `json:"allowDestructive"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// RegistrationMode decides who may create an account.
+type RegistrationMode string
+
+const (
+	// RegistrationOpen lets anyone sign up.
+	RegistrationOpen RegistrationMode = "open"
+	// RegistrationInvite needs an invitation from an admin.
+	RegistrationInvite RegistrationMode = "invite"
+	// RegistrationDomain admits addresses at the allowed email domains, and
+	// anyone else with an invitation.
+	RegistrationDomain RegistrationMode = "domain"
+)
+
+func (m RegistrationMode) IsValid() bool {
+	switch m {
+	case RegistrationOpen, RegistrationInvite, RegistrationDomain:
+		return true
+	}
+	return false
+}
+
+// Invitation lets one person create an account whatever the registration
+// mode, optionally with a preset role and organization membership. Only the
+// hash of its code is stored, and it can be accepted once.
+type Invitation struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	CodeHash string `json:"-" gorm:"not null;uniqueIndex"`
+	// Email, when set, is the only address that may accept the invitation.
+	Email string `json:"email,omitempty"`
+	Role  Role   `json:"role" gorm:"not null"`
+	// OrganizationID, when set, is joined with OrganizationRole on sign-up.
+	OrganizationID   *uint      `json:"organizationId,omitempty"`
+	OrganizationRole OrgRole    `json:"organizationRole,omitempty"`
+	CreatedBy        uint       `json:"createdBy" gorm:"not null"`
+	ExpiresAt        time.Time  `json:"expiresAt" gorm:"not null;index"`
+	AcceptedAt       *time.Time `json:"acceptedAt,omitempty"`
+	AcceptedBy       *uint      `json:"acceptedBy,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
+
+func (i *Invitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email,omitempty" example:"jane@example.com"`
+	// Role defaults to member.
+	Role           Role  `json:"role,omitempty" example:"member"`
+	OrganizationID *uint `json:"organizationId,omitempty"`
+	// OrganizationRole defaults to member.
+	OrganizationRole OrgRole `json:"organizationRole,omitempty" example:"member"`
+	// ExpiresInHours defaults to INVITATION_TTL.
+	ExpiresInHours int `json:"expiresInHours,omitempty" example:"168"`
+}
+
+// CreatedInvitation is shown to the admin once; the code cannot be
+// retrieved later.
+type CreatedInvitation struct {
+	*Invitation
+	Code string `json:"code"`
+	Link string `json:"link"`
+	// Sent reports whether the invitation was emailed to Email.
+	Sent bool `json:"sent"`
+}
+
+// RegistrationPolicy tells clients whether to ask for an invitation code.
+type RegistrationPolicy struct {
+	Mode           RegistrationMode `json:"mode" example:"invite"`
+	AllowedDomains []string         `json:"allowedDomains,omitempty"`
+}
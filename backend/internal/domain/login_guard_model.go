@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// LoginThrottle counts recent failed logins for one account or one client
+// address. Key is "email:<address>" or "ip:<address>".
+type LoginThrottle struct {
+	Key           string    `json:"key" gorm:"primaryKey"`
+	Failures      int       `json:"failures" gorm:"not null"`
+	LastFailureAt time.Time `json:"lastFailureAt"`
+	// NextAttemptAt is when the next attempt is allowed, growing with each
+	// failure so guessing slows down before the lockout.
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	LockedUntil   *time.Time `json:"lockedUntil,omitempty"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// LoginAttempt is one password login, kept so admins can spot attacks.
+type LoginAttempt struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Email     string    `json:"email" gorm:"not null;index"`
+	IP        string    `json:"ip" gorm:"not null;index"`
+	UserID    *uint     `json:"userId,omitempty"`
+	Success   bool      `json:"success" gorm:"not null"`
+	Reason    string    `json:"reason,omitempty" example:"invalid_credentials"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
+
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureThrottled          = "throttled"
+	LoginFailureInvalidMFACode     = "invalid_mfa_code"
+)
+
+const (
+	LockoutScopeAccount = "account"
+	LockoutScopeIP      = "ip"
+)
+
+// LockoutEvent records an account or address being locked out.
+type LockoutEvent struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Scope       string    `json:"scope" gorm:"not null" example:"account"`
+	Subject     string    `json:"subject" gorm:"not null;index" example:"user@example.com"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"lockedUntil"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"index"`
+}
+
+type LoginAttemptFilter struct {
+	Email string
+	IP    string
+	Limit int
+}
+
+// ClearLockoutRequest names the account or address to unlock.
+type ClearLockoutRequest struct {
+	Email string `json:"email,omitempty" example:"user@example.com"`
+	IP    string `json:"ip,omitempty" example:"203.0.113.7"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+// TOTPCredential is a user's authenticator app enrollment. It is pending
+// until the user proves it works by entering a code.
+type TOTPCredential struct {
+	UserID  uint   `gorm:"primaryKey;autoIncrement:false"`
+	Secret  string `gorm:"not null"`
+	Enabled bool   `gorm:"not null"`
+	// LastStep is the time step of the last accepted code, to stop replays.
+	LastStep  int64 `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RecoveryCode is a single-use code that stands in for a TOTP code when the
+// user has lost their authenticator. Only its hash is stored.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"not null;uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAChallenge is the short-lived, single-use proof that a user passed the
+// password step of a login and still owes a second factor.
+type MFAChallenge struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	Attempts  int       `gorm:"not null;default:0"`
+	CreatedAt time.Time
+}
+
+func (c *MFAChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// LoginChallenge is returned by /api/login instead of tokens when a second
+// factor is needed. EnrollmentRequired means the user has no authenticator
+// yet but 2FA is mandatory, so they must enroll before finishing.
+type LoginChallenge struct {
+	MFARequired        bool   `json:"mfaRequired"`
+	Challenge          string `json:"challenge"`
+	EnrollmentRequired bool   `json:"enrollmentRequired"`
+	ExpiresIn          int64  `json:"expiresIn" example:"300"`
+}
+
+type MFALoginRequest struct {
+	Challenge    string `json:"challenge"`
+	Code         string `json:"code,omitempty" example:"123456"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
+}
+
+type MFAChallengeRequest struct {
+	Challenge string `json:"challenge"`
+}
+
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+type MFAStatus struct {
+	TOTPEnabled            bool  `json:"totpEnabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recoveryCodesRemaining"`
+}
+
+type MFAPolicyRequest struct {
+	Required bool `json:"required"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// UserIdentity links a user to their account at an external identity
+// provider, identified by the provider's stable subject.
+type UserIdentity struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"-" gorm:"not null;index"`
+	Provider    string     `json:"provider" gorm:"not null;uniqueIndex:idx_identity_subject"`
+	Subject     string     `json:"-" gorm:"not null;uniqueIndex:idx_identity_subject"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// OIDCLoginState remembers an authorization request between sending the
+// user to the provider and their return. It is found by the hash of the
+// state parameter and can be used once.
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"not null;uniqueIndex"`
+	Provider     string    `gorm:"not null"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+// OIDCLoginTicket is handed to the frontend after a successful sign-in and
+// exchanged once for the app's own tokens, so tokens never appear in a URL.
+type OIDCLoginTicket struct {
+	ID        uint      `gorm:"primaryKey"`
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	UserID    uint      `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+type OIDCProviderInfo struct {
+	Name        string `json:"name" example:"corp"`
+	DisplayName string `json:"displayName" example:"Corporate SSO"`
+	LoginURL    string `json:"loginUrl" example:"http://localhost:8080/api/auth/oidc/corp/login"`
+}
+
+type OIDCExchangeRequest struct {
+	Ticket string `json:"ticket"`
+}
@@ -0,0 +1,125 @@
+package domain
+
+import "time"
+
+// OrgRole is a user's role within one organization.
+type OrgRole string
+
+const (
+	// OrgRoleOwner can do everything, including deleting the organization.
+	OrgRoleOwner OrgRole = "owner"
+	// OrgRoleAdmin manages members and every file in the organization.
+	OrgRoleAdmin OrgRole = "admin"
+	// OrgRoleMember reads and uploads files, and manages their own.
+	OrgRoleMember OrgRole = "member"
+)
+
+func (r OrgRole) IsValid() bool {
+	switch r {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember:
+		return true
+	}
+	return false
+}
+
+// CanManage reports whether the role may manage the organization's members
+// and all of its files.
+func (r OrgRole) CanManage() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin
+}
+
+// Organization is a team workspace whose files are shared by its members.
+type Organization struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null"`
+	// StorageQuota caps the total size of the organization's files in
+	// bytes. Zero means unlimited.
+	StorageQuota int64     `json:"storageQuota" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	// Role is the caller's role in the organization.
+	Role OrgRole `json:"role,omitempty" gorm:"-"`
+}
+
+type OrganizationMember struct {
+	OrganizationID uint      `json:"organizationId" gorm:"primaryKey;autoIncrement:false"`
+	UserID         uint      `json:"userId" gorm:"primaryKey;autoIncrement:false;index"`
+	Role           OrgRole   `json:"role" gorm:"not null"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// Email, FirstName and Surname are filled in when listing members.
+	Email     string `json:"email,omitempty" gorm:"-"`
+	FirstName string `json:"firstName,omitempty" gorm:"-"`
+	Surname   string `json:"surname,omitempty" gorm:"-"`
+}
+
+// StorageUsage is how much of an organization's quota its files take up.
+type StorageUsage struct {
+	Files int64 `json:"files"`
+	Bytes int64 `json:"bytes"`
+	// Quota is zero when storage is unlimited.
+	Quota int64 `json:"quota"`
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" example:"Finance"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" example:"Finance"`
+}
+
+// OrganizationInvitation asks whoever holds an email address to join an
+// organization. It is made the same way whether or not the address has an
+// account, and nobody becomes a member until they accept.
+type OrganizationInvitation struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID uint      `json:"organizationId" gorm:"not null;uniqueIndex:idx_organization_invitation"`
+	Email          string    `json:"email" gorm:"not null;uniqueIndex:idx_organization_invitation;index"`
+	Role           OrgRole   `json:"role" gorm:"not null"`
+	InvitedBy      uint      `json:"invitedBy" gorm:"not null"`
+	ExpiresAt      time.Time `json:"expiresAt" gorm:"not null"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// OrganizationName is filled in for the invitee.
+	OrganizationName string `json:"organizationName,omitempty" gorm:"-"`
+}
+
+func (i *OrganizationInvitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
+
+type InviteMemberRequest struct {
+	Email string  `json:"email" example:"jane@example.com"`
+	Role  OrgRole `json:"role" example:"member"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role OrgRole `json:"role" example:"admin"`
+}
+
+type SetStorageQuotaRequest struct {
+	// Quota is in bytes; zero removes the limit.
+	Quota int64 `json:"quota" example:"10737418240"`
+}
+
+// FileScope selects whose files a listing covers: one user's personal files
+// or everything in an organization.
+type FileScope struct {
+	UserID         uint
+	OrganizationID uint
+}
+
+func PersonalFiles(userID uint) FileScope {
+	return FileScope{UserID: userID}
+}
+
+func OrganizationFiles(organizationID uint) FileScope {
+	return FileScope{OrganizationID: organizationID}
+}
+
+// Resource is what access to the scope is checked against.
+func (s FileScope) Resource() Resource {
+	if s.OrganizationID != 0 {
+		return InOrganization(s.OrganizationID)
+	}
+	return OwnedBy(s.UserID)
+}
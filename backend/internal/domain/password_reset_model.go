@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// PasswordResetToken lets a user who forgot their password choose a new
+// one. Only the hash of the emailed token is stored, and it can be used once.
+type PasswordResetToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PasswordChangeChallenge is returned by /api/login instead of tokens when
+// an admin requires the user to choose a new password. ResetToken is used
+// with /api/password/reset like an emailed one. RecoveryCodes are passed on
+// from a login that just completed a required 2FA enrollment.
+type PasswordChangeChallenge struct {
+	PasswordChangeRequired bool     `json:"passwordChangeRequired"`
+	ResetToken             string   `json:"resetToken"`
+	ExpiresIn              int64    `json:"expiresIn" example:"3600"`
+	RecoveryCodes          []string `json:"recoveryCodes,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
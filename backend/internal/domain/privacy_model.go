@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+type ErasureStatus string
+
+const (
+	// ErasurePending requests are carried out once ScheduledFor passes.
+	ErasurePending   ErasureStatus = "pending"
+	ErasureCancelled ErasureStatus = "cancelled"
+	ErasureCompleted ErasureStatus = "completed"
+)
+
+// ErasureRequest asks for an account and everything it owns to be deleted.
+// It is carried out after a grace period during which the user can change
+// their mind. Completed requests are kept, without any personal data, as a
+// record that the erasure happened.
+type ErasureRequest struct {
+	ID     uint          `json:"id" gorm:"primaryKey"`
+	UserID uint          `json:"userId" gorm:"not null;index"`
+	Status ErasureStatus `json:"status" gorm:"not null;index" example:"pending"`
+	// RequestedBy is the user themselves or the admin acting on their
+	// behalf.
+	RequestedBy  uint       `json:"requestedBy" gorm:"not null"`
+	Reason       string     `json:"reason,omitempty"`
+	ScheduledFor time.Time  `json:"scheduledFor" gorm:"not null;index"`
+	CancelledAt  *time.Time `json:"cancelledAt,omitempty"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type RequestErasureRequest struct {
+	Reason string `json:"reason,omitempty" example:"Closing my account"`
+	// Immediate skips the grace period. Admins only.
+	Immediate bool `json:"immediate,omitempty"`
+}
+
+// UserExport is everything stored about a user, apart from the contents of
+// their files.
+type UserExport struct {
+	ExportedAt      time.Time             `json:"exportedAt"`
+	Profile         *User                 `json:"profile"`
+	Files           []File                `json:"files"`
+	ShareRecipients []ShareRecipient      `json:"shareRecipients"`
+	Collections     []Collection          `json:"collections"`
+	UploadRequests  []UploadRequest       `json:"uploadRequests"`
+	Organizations   []Organization        `json:"organizations"`
+	Sessions        []Session             `json:"sessions"`
+	AccessTokens    []PersonalAccessToken `json:"accessTokens"`
+	LoginAttempts   []LoginAttempt        `json:"loginAttempts"`
+	Erasure         *ErasureRequest       `json:"erasure,omitempty"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Session is one login on one device. Its ID is the FamilyID of the refresh
+// tokens rotated from that login and the sid claim of its access tokens, so
+// revoking it cuts off both.
+type Session struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"-" gorm:"not null;index"`
+	Device     string     `json:"device" example:"Firefox on Windows"`
+	UserAgent  string     `json:"userAgent"`
+	IP         string     `json:"ip" example:"203.0.113.7"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastSeenAt time.Time  `json:"lastSeenAt"`
+	ExpiresAt  time.Time  `json:"expiresAt" gorm:"not null"`
+	RevokedAt  *time.Time `json:"-"`
+	// Current marks the session the request was made from.
+	Current bool `json:"current" gorm:"-"`
+}
+
+// SessionClient describes the device a session is used from.
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Setting is a deployment-wide option that admins can change at runtime.
+type Setting struct {
+	Key       string `gorm:"primaryKey"`
+	Value     string `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+const (
+	// SettingMFARequired makes every account enroll in 2FA.
+	SettingMFARequired = "mfa_required"
+)
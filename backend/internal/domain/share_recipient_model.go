@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ShareRecipient is a named person a shared file was emailed to. Each
+// recipient gets a personalized link so access can be tracked per person.
+type ShareRecipient struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	FileID         uint       `json:"fileId" gorm:"not null;index"`
+	SharedBy       uint       `json:"sharedBy" gorm:"not null"`
+	Email          string     `json:"email" gorm:"not null"`
+	Token          string     `json:"-" gorm:"not null;uniqueIndex"`
+	Message        string     `json:"message,omitempty"`
+	SentAt         *time.Time `json:"sentAt,omitempty"`
+	SendError      string     `json:"sendError,omitempty"`
+	AccessCount    int        `json:"accessCount" gorm:"not null;default:0"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type ShareRequest struct {
+	Recipients []string   `json:"recipients"`
+	Message    string     `json:"message"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+}
+
+func (r *ShareRecipient) Link(baseURL, shareableID string) string {
+	return fmt.Sprintf("%s/shared/%s/preview?r=%s", baseURL, shareableID, r.Token)
+}
+
+// SharePreview is the public metadata shown before a shared file is downloaded.
+type SharePreview struct {
+	Name        string     `json:"name"`
+	MimeType    string     `json:"mimeType"`
+	Size        int64      `json:"size"`
+	PageCount   *int       `json:"pageCount,omitempty"`
+	OwnerName   string     `json:"ownerName"`
+	SharedAt    time.Time  `json:"sharedAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	PreviewURL  string     `json:"previewUrl"`
+	DownloadURL string     `json:"downloadUrl"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// RefreshToken is a long-lived credential that can be exchanged once for a
+// new access token. Only a hash of the token is stored. Tokens rotated from
+// the same login share a FamilyID, so presenting an already-rotated token
+// revokes the whole family.
+type RefreshToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"userId" gorm:"not null;index"`
+	FamilyID   string     `json:"-" gorm:"not null;index"`
+	TokenHash  string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt  time.Time  `json:"expiresAt" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	ReplacedBy *uint      `json:"-"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// RevokedToken is an access token that must be rejected until it expires.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// UserRevocation invalidates every access token a user was issued before
+// RevokedAt.
+type UserRevocation struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	RevokedAt time.Time `gorm:"not null"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn" example:"900"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// UploadRequest is a public link through which unauthenticated visitors can
+// drop files into the account of the user who created it.
+type UploadRequest struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"userId" gorm:"not null;index"`
+	Token        string    `json:"token" gorm:"not null;uniqueIndex"`
+	Target       string    `json:"target" gorm:"not null"`
+	AllowedTypes string    `json:"allowedTypes"`
+	MaxSize      int64     `json:"maxSize" gorm:"not null"`
+	PasswordHash string    `json:"-"`
+	ExpiresAt    time.Time `json:"expiresAt" gorm:"not null;index"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// UploadRequestInfo is what a visitor of the public link gets to see.
+type UploadRequestInfo struct {
+	Target           string    `json:"target"`
+	AllowedTypes     []string  `json:"allowedTypes"`
+	MaxSize          int64     `json:"maxSize"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	PasswordRequired bool      `json:"passwordRequired"`
+}
+
+type CreateUploadRequestRequest struct {
+	Target       string    `json:"target"`
+	AllowedTypes []string  `json:"allowedTypes"`
+	MaxSize      int64     `json:"maxSize"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Password     string    `json:"password"`
+}
+
+func (u *UploadRequest) IsExpired() bool {
+	return time.Now().After(u.ExpiresAt)
+}
+
+func (u *UploadRequest) AllowedTypeList() []string {
+	if u.AllowedTypes == "" {
+		return []string{}
+	}
+	return strings.Split(u.AllowedTypes, ",")
+}
+
+// Allows checks a submitted file against the request's type and size policy.
+// Requests that name no types accept the types any upload may have.
+func (u *UploadRequest) Allows(mimeType string, size int64) error {
+	if size > u.MaxSize {
+		return NewFileTooLargeError(size, u.MaxSize)
+	}
+
+	allowed := u.AllowedTypeList()
+	if len(allowed) == 0 {
+		if AllowedMimeTypes[mimeType] {
+			return nil
+		}
+		return ErrInvalidFileType
+	}
+	for _, t := range allowed {
+		if t == mimeType {
+			return nil
+		}
+	}
+	return ErrInvalidFileType
+}
+
+func (u *UploadRequest) ToInfo() UploadRequestInfo {
+	return UploadRequestInfo{
+		Target:           u.Target,
+		AllowedTypes:     u.AllowedTypeList(),
+		MaxSize:          u.MaxSize,
+		ExpiresAt:        u.ExpiresAt,
+		PasswordRequired: u.PasswordHash != "",
+	}
+}
@@ -2,6 +2,18 @@ package domain
 
 import "time"
 
+// Role controls which parts of the API a user may access.
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+)
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	return r == RoleAdmin || r == RoleMember
+}
 
 type User struct {
 	ID        uint      `json:"id" gorm:"primaryKey" example:"1"`
@@ -10,10 +22,95 @@ type User struct {
 	FirstName string    `json:"firstName" gorm:"not null" example:"John"`
 	Surname   string    `json:"surname" gorm:"not null" example:"Doe"`
 	DOB       time.Time `json:"dob" gorm:"not null" example:"1990-01-01T00:00:00Z"`
+	Role      Role      `json:"role" gorm:"not null;default:member" example:"member"`
+	// EmailVerifiedAt is set once the user proves they own Email.
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty" example:"2024-01-01T00:00:00Z"`
+	// DisabledAt is set while an admin has disabled the account. Disabled
+	// users cannot log in, and tokens they already hold stop working.
+	DisabledAt     *time.Time `json:"disabledAt,omitempty" example:"2024-01-01T00:00:00Z"`
+	DisabledReason string     `json:"disabledReason,omitempty" example:"Left the company"`
+	// PasswordChangeRequired makes the next login end in a password change
+	// rather than a session.
+	PasswordChangeRequired bool       `json:"passwordChangeRequired,omitempty" gorm:"not null;default:false"`
+	LastLoginAt            *time.Time `json:"lastLoginAt,omitempty" example:"2024-01-01T00:00:00Z"`
+	LastLoginIP            string     `json:"lastLoginIp,omitempty" example:"203.0.113.7"`
 	CreatedAt time.Time `json:"createdAt,omitempty" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt time.Time `json:"updatedAt,omitempty" example:"2024-01-01T00:00:00Z"`
 }
 
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsLastAdmin reports whether removing the user id from users would leave
+// no enabled admin. Disabled admins cannot act, so they do not count, and
+// removing one never leaves the site without an admin.
+func IsLastAdmin(users []User, id uint) bool {
+	last := false
+	for i := range users {
+		u := &users[i]
+		if !u.IsAdmin() || u.IsDisabled() {
+			continue
+		}
+		if u.ID != id {
+			return false
+		}
+		last = true
+	}
+	return last
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// UpdateProfileRequest changes the caller's own profile. Omitted fields are
+// left as they are.
+type UpdateProfileRequest struct {
+	FirstName *string `json:"firstName,omitempty" example:"John"`
+	Surname   *string `json:"surname,omitempty" example:"Doe"`
+	DOB       *string `json:"dob,omitempty" example:"1990-01-01"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// DisableUserRequest says why an admin is disabling an account.
+type DisableUserRequest struct {
+	Reason string `json:"reason,omitempty" example:"Left the company"`
+}
+
+// TemporaryPasswordRequest sets a password the user must change at their
+// next login. An empty password has one generated.
+type TemporaryPasswordRequest struct {
+	Password string `json:"password,omitempty"`
+}
+
+type TemporaryPassword struct {
+	Password string `json:"temporaryPassword" example:"Xq7!vR2#pL9@wM4$"`
+}
+
+// AccountStatus summarises an account's lifecycle state for admins.
+type AccountStatus struct {
+	UserID                 uint       `json:"userId"`
+	Disabled               bool       `json:"disabled"`
+	DisabledAt             *time.Time `json:"disabledAt,omitempty"`
+	DisabledReason         string     `json:"disabledReason,omitempty"`
+	PasswordChangeRequired bool       `json:"passwordChangeRequired"`
+	LastLoginAt            *time.Time `json:"lastLoginAt,omitempty"`
+	LastLoginIP            string     `json:"lastLoginIp,omitempty"`
+}
+
+type UpdateRoleRequest struct {
+	Role Role `json:"role" example:"admin"`
+}
+
 type UserContract struct {
 	UserID     string    `json:"userId"`
 	FileName   string    `json:"fileName"`
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	accessTokenInterface "tech-test/backend/internal/service/interfaces/accesstoken"
+	"tech-test/backend/internal/utils"
+)
+
+type AccessTokenHandler struct {
+	accessTokenService accessTokenInterface.Service
+}
+
+func NewAccessTokenHandler(accessTokenService accessTokenInterface.Service) *AccessTokenHandler {
+	return &AccessTokenHandler{accessTokenService: accessTokenService}
+}
+
+// Create godoc
+// @Summary Create a personal access token
+// @Description Create a named, scoped token for scripts. The secret is only returned in this response.
+// @Tags Access tokens
+// @Accept json
+// @Produce json
+// @Param request body domain.CreateAccessTokenRequest true "Token details"
+// @Success 201 {object} domain.CreatedAccessToken
+// @Failure 400 {object} domain.APIError
+// @Router /api/tokens [post]
+func (h *AccessTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.CreateAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	token, err := h.accessTokenService.Create(r.Context(), userID, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, token)
+}
+
+// List godoc
+// @Summary List personal access tokens
+// @Tags Access tokens
+// @Produce json
+// @Success 200 {array} domain.AccessTokenInfo
+// @Router /api/tokens [get]
+func (h *AccessTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	tokens, err := h.accessTokenService.ListByUser(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": tokens,
+	})
+}
+
+// Revoke godoc
+// @Summary Revoke a personal access token
+// @Tags Access tokens
+// @Produce json
+// @Param id path int true "Token ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/tokens/{id} [delete]
+func (h *AccessTokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid token ID",
+			err,
+		))
+		return
+	}
+
+	if err := h.accessTokenService.Revoke(r.Context(), uint(id)); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Access token revoked successfully",
+	})
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"tech-test/backend/internal/domain"
+	auditInterface "tech-test/backend/internal/service/interfaces/audit"
+	"tech-test/backend/internal/utils"
+)
+
+type AuditHandler struct {
+	auditService auditInterface.Service
+}
+
+func NewAuditHandler(auditService auditInterface.Service) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// List godoc
+// @Summary List the audit trail
+// @Description Newest first, optionally filtered by a user (as actor or subject), an impersonation session or an action. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param userId query int false "User ID"
+// @Param impersonationId query string false "Impersonation ID"
+// @Param action query string false "Action, e.g. impersonation.request"
+// @Param limit query int false "Maximum entries (default 100)"
+// @Success 200 {array} domain.AuditEvent
+// @Router /api/admin/audit [get]
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	userID, _ := strconv.ParseUint(query.Get("userId"), 10, 32)
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	events, err := h.auditService.List(r.Context(), domain.AuditFilter{
+		UserID:          uint(userID),
+		ImpersonationID: query.Get("impersonationId"),
+		Action:          domain.AuditAction(query.Get("action")),
+		Limit:           limit,
+	})
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": events,
+	})
+}
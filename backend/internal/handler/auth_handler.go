@@ -2,40 +2,132 @@
 package handler
 
 import (
-    "bytes"
     "encoding/json"
-    "io"
     "log"
+    "math"
+    "net"
     "net/http"
+    "strconv"
     "time"
     "tech-test/backend/internal/domain"
+    emailVerificationInterface "tech-test/backend/internal/service/interfaces/emailverification"
+    invitationInterface "tech-test/backend/internal/service/interfaces/invitation"
+    loginGuardInterface "tech-test/backend/internal/service/interfaces/loginguard"
+    mfaInterface "tech-test/backend/internal/service/interfaces/mfa"
+    passwordResetInterface "tech-test/backend/internal/service/interfaces/passwordreset"
+    ssoInterface "tech-test/backend/internal/service/interfaces/sso"
+    tokenInterface "tech-test/backend/internal/service/interfaces/token"
     userInterface "tech-test/backend/internal/service/interfaces/user"
     "tech-test/backend/internal/utils"
     "tech-test/backend/internal/middleware"
-    "fmt"
     "errors"
 )                       
 
 type AuthHandler struct {
-    userService userInterface.UserService
+    userService              userInterface.UserService
+    tokenService             tokenInterface.Service
+    mfaService               mfaInterface.Service
+    emailVerificationService emailVerificationInterface.Service
+    ssoService               ssoInterface.Service
+    loginGuard               loginGuardInterface.Service
+    invitationService        invitationInterface.Service
+    passwordReset            passwordResetInterface.Service
 }
 
-func NewAuthHandler(userService userInterface.UserService) *AuthHandler {
+func NewAuthHandler(
+    userService userInterface.UserService,
+    tokenService tokenInterface.Service,
+    mfaService mfaInterface.Service,
+    emailVerificationService emailVerificationInterface.Service,
+    ssoService ssoInterface.Service,
+    loginGuard loginGuardInterface.Service,
+    invitationService invitationInterface.Service,
+    passwordReset passwordResetInterface.Service,
+) *AuthHandler {
     return &AuthHandler{
-        userService: userService,
+        userService:              userService,
+        tokenService:             tokenService,
+        mfaService:               mfaService,
+        emailVerificationService: emailVerificationService,
+        ssoService:               ssoService,
+        loginGuard:               loginGuard,
+        invitationService:        invitationService,
+        passwordReset:            passwordReset,
     }
 }
 
 
+// Login godoc
+// @Summary Log in with email and password
+// @Description Unknown emails and wrong passwords get the same error. Repeated failures slow down and then temporarily lock out the account or address; a 429 carries a Retry-After header. Returns a challenge instead of tokens when a second factor is needed, and a reset token instead of tokens when an admin requires a new password. Disabled accounts get a 403.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.LoginRequest true "Credentials"
+// @Success 200 {object} LoginResponse
+// @Failure 401 {object} domain.APIError
+// @Failure 403 {object} domain.APIError "Account is disabled"
+// @Failure 429 {object} domain.APIError
+// @Router /api/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-    var loginRequest struct {
-        Email    string `json:"email"`
-        Password string `json:"password"`
+    var loginRequest domain.LoginRequest
+    if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
+        utils.RespondWithError(w, domain.NewAPIError(
+            http.StatusBadRequest,
+            domain.ErrCodeInvalidInput,
+            "Invalid request format",
+            err,
+        ))
+        return
+    }
+
+    ip := remoteIP(r)
+    if !h.checkLoginGuard(w, r, loginRequest.Email, ip) {
+        return
+    }
+
+    user, err := h.userService.Login(r.Context(), loginRequest.Email, loginRequest.Password)
+    if err != nil {
+        if errors.Is(err, domain.ErrInvalidCredentials) {
+            if err := h.loginGuard.RecordFailure(r.Context(), loginRequest.Email, ip, domain.LoginFailureInvalidCredentials); err != nil {
+                log.Printf("Failed to record failed login: %v", err)
+            }
+        }
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
     }
 
-    body, err := io.ReadAll(r.Body)
+    challenge, err := h.mfaService.BeginLogin(r.Context(), user)
     if err != nil {
-        log.Printf("Error reading body: %v", err)
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+    if challenge != nil {
+        // The account's failures are only cleared once the second factor
+        // passes, so codes cannot be guessed across fresh challenges.
+        utils.RespondWithJSON(w, http.StatusOK, challenge)
+        return
+    }
+
+    if err := h.loginGuard.RecordSuccess(r.Context(), loginRequest.Email, ip, user.ID); err != nil {
+        log.Printf("Failed to record login: %v", err)
+    }
+    h.startSession(w, r, user, nil)
+}
+
+// LoginOIDC godoc
+// @Summary Exchange a single sign-on ticket for tokens
+// @Description Completes a sign-in started at /api/auth/oidc/{provider}/login. Like /api/login, it returns a challenge instead when a second factor is needed.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.OIDCExchangeRequest true "Ticket from the callback redirect"
+// @Success 200 {object} LoginResponse
+// @Failure 401 {object} domain.APIError
+// @Router /api/auth/oidc/exchange [post]
+func (h *AuthHandler) LoginOIDC(w http.ResponseWriter, r *http.Request) {
+    var req domain.OIDCExchangeRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         utils.RespondWithError(w, domain.NewAPIError(
             http.StatusBadRequest,
             domain.ErrCodeInvalidInput,
@@ -44,90 +136,266 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
         ))
         return
     }
-    log.Printf("Received login request: %s", string(body))
 
-    r.Body = io.NopCloser(bytes.NewBuffer(body))
+    user, err := h.ssoService.ExchangeTicket(r.Context(), req.Ticket)
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
 
-    if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
-        log.Printf("Error decoding login request: %v", err)
+    challenge, err := h.mfaService.BeginLogin(r.Context(), user)
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+    if challenge != nil {
+        utils.RespondWithJSON(w, http.StatusOK, challenge)
+        return
+    }
+
+    h.startSession(w, r, user, nil)
+}
+
+// LoginMFA godoc
+// @Summary Complete a two-factor login
+// @Description Finish a login that returned mfaRequired, using a TOTP code or a recovery code. If the login also completed a required enrollment, new recovery codes are returned. Wrong codes count towards the same lockout as wrong passwords.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.MFALoginRequest true "Challenge and code"
+// @Success 200 {object} LoginResponse
+// @Failure 401 {object} domain.APIError
+// @Failure 429 {object} domain.APIError
+// @Router /api/login/mfa [post]
+func (h *AuthHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+    var req domain.MFALoginRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         utils.RespondWithError(w, domain.NewAPIError(
             http.StatusBadRequest,
             domain.ErrCodeInvalidInput,
-            "Invalid request format",
+            "Invalid request body",
             err,
         ))
         return
     }
 
-    log.Printf("Attempting login for email: %s", loginRequest.Email)
+    pending, err := h.mfaService.ChallengeUser(r.Context(), req.Challenge)
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    // Wrong codes count against the account like wrong passwords, so each
+    // challenge's own attempt limit cannot be sidestepped by logging in
+    // again.
+    ip := remoteIP(r)
+    if !h.checkLoginGuard(w, r, pending.Email, ip) {
+        return
+    }
 
-    user, err := h.userService.GetUserByEmail(r.Context(), loginRequest.Email)
+    user, recoveryCodes, err := h.mfaService.CompleteLogin(r.Context(), req)
     if err != nil {
+        if errors.Is(err, domain.ErrInvalidMFACode) {
+            if err := h.loginGuard.RecordFailure(r.Context(), pending.Email, ip, domain.LoginFailureInvalidMFACode); err != nil {
+                log.Printf("Failed to record failed login: %v", err)
+            }
+        }
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    if err := h.loginGuard.RecordSuccess(r.Context(), user.Email, ip, user.ID); err != nil {
+        log.Printf("Failed to record login: %v", err)
+    }
+    h.startSession(w, r, user, recoveryCodes)
+}
+
+// checkLoginGuard responds with an error and returns false when the account
+// or address must wait before trying to log in again.
+func (h *AuthHandler) checkLoginGuard(w http.ResponseWriter, r *http.Request, email, ip string) bool {
+    wait, err := h.loginGuard.Check(r.Context(), email, ip)
+    if err == nil {
+        return true
+    }
+    if wait > 0 {
+        w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+    }
+    utils.RespondWithError(w, domain.WrapError(err))
+    return false
+}
+
+// LoginMFAEnroll godoc
+// @Summary Enroll an authenticator during login
+// @Description When 2FA is mandatory and the login challenge says enrollmentRequired, get a TOTP secret to add to an authenticator app, then finish with /api/login/mfa.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.MFAChallengeRequest true "Login challenge"
+// @Success 200 {object} domain.TOTPEnrollment
+// @Failure 401 {object} domain.APIError
+// @Router /api/login/mfa/enroll [post]
+func (h *AuthHandler) LoginMFAEnroll(w http.ResponseWriter, r *http.Request) {
+    var req domain.MFAChallengeRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusNotFound,
-            domain.ErrCodeNotFound,
-            fmt.Sprintf("No account found with email: %s", loginRequest.Email),
+            http.StatusBadRequest,
+            domain.ErrCodeInvalidInput,
+            "Invalid request body",
             err,
         ))
         return
     }
 
-    
-    if !utils.CheckPasswordHash(loginRequest.Password, user.Password) {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusUnauthorized,
-            domain.ErrCodeAuthentication,
-            "Incorrect password",
-            errors.New("password mismatch"),
-        ))
+    enrollment, err := h.mfaService.EnrollWithChallenge(r.Context(), req.Challenge)
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    utils.RespondWithJSON(w, http.StatusOK, enrollment)
+}
+
+// startSession issues tokens for a user who has fully logged in. A user an
+// admin has told to change their password gets a reset token instead, so
+// they can only choose a new one and log in again.
+func (h *AuthHandler) startSession(w http.ResponseWriter, r *http.Request, user *domain.User, recoveryCodes []string) {
+    if user.IsDisabled() {
+        utils.RespondWithError(w, domain.ErrAccountDisabled)
         return
     }
 
-    token, err := utils.GenerateToken(user.ID)
+    if user.PasswordChangeRequired {
+        challenge, err := h.passwordReset.BeginForcedChange(r.Context(), user)
+        if err != nil {
+            utils.RespondWithError(w, domain.WrapError(err))
+            return
+        }
+        challenge.RecoveryCodes = recoveryCodes
+        utils.RespondWithJSON(w, http.StatusOK, challenge)
+        return
+    }
+
+    tokens, err := h.tokenService.Issue(r.Context(), user, sessionClient(r))
     if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    if err := h.userService.RecordLogin(r.Context(), user.ID, remoteIP(r)); err != nil {
+        log.Printf("Failed to record last login for user %d: %v", user.ID, err)
+    }
+
+    response := map[string]interface{}{
+        "token":        tokens.AccessToken,
+        "refreshToken": tokens.RefreshToken,
+        "expiresIn":    tokens.ExpiresIn,
+        "user":         user,
+    }
+    if len(recoveryCodes) > 0 {
+        response["recoveryCodes"] = recoveryCodes
+    }
+    utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// sessionClient describes the device a request came from.
+func sessionClient(r *http.Request) domain.SessionClient {
+    return domain.SessionClient{
+        UserAgent: r.UserAgent(),
+        IP:        remoteIP(r),
+    }
+}
+
+func remoteIP(r *http.Request) string {
+    ip, _, err := net.SplitHostPort(r.RemoteAddr)
+    if err != nil {
+        return r.RemoteAddr
+    }
+    return ip
+}
+
+// Refresh godoc
+// @Summary Refresh an access token
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token can be used once; reusing one ends the session.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.RefreshRequest true "Refresh token"
+// @Success 200 {object} domain.TokenPair
+// @Failure 401 {object} domain.APIError
+// @Router /api/token/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+    var req domain.RefreshRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
         utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Error generating token",
+            http.StatusBadRequest,
+            domain.ErrCodeInvalidInput,
+            "refreshToken is required",
             err,
         ))
         return
     }
 
-    utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
-        "token": token,
-        "user":  user,
+    tokens, err := h.tokenService.Refresh(r.Context(), req.RefreshToken, sessionClient(r))
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    utils.RespondWithJSON(w, http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke the current access token and end its session.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.RefreshRequest false "Refresh token to revoke"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} domain.APIError
+// @Router /api/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+    claims, ok := middleware.GetClaimsFromContext(r.Context())
+    if !ok {
+        utils.RespondWithError(w, domain.ErrUnauthorized)
+        return
+    }
+
+    var req domain.RefreshRequest
+    if r.ContentLength != 0 {
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            utils.RespondWithError(w, domain.NewAPIError(
+                http.StatusBadRequest,
+                domain.ErrCodeInvalidInput,
+                "Invalid request body",
+                err,
+            ))
+            return
+        }
+    }
+
+    if err := h.tokenService.Logout(r.Context(), claims.UserID, claims.SessionID, claims.ID, claims.ExpiresAt.Time, req.RefreshToken); err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+        "message": "Logged out successfully",
     })
 }
 
 // Register godoc
 // @Summary Register a new user
-// @Description Register a new user in the system
+// @Description Register a new user in the system. Depending on REGISTRATION_MODE, anyone may register, only addresses at allowed domains, or only holders of an invitation code.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body RegisterRequest true "Registration details"
 // @Success 201 {object} map[string]string "User registered successfully"
 // @Failure 400 {object} domain.APIError "Invalid request body"
+// @Failure 403 {object} domain.APIError "Registration closed or invitation invalid"
 // @Router /api/register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-    // Read and log the request body
-    body, err := io.ReadAll(r.Body)
-    if err != nil {
-        log.Printf("Error reading body: %v", err)
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusBadRequest,
-            domain.ErrCodeInvalidInput,
-            "Invalid request body",
-            err,
-        ))
-        return
-    }
-    log.Printf("Received registration request: %s", string(body))
-
-    
-    r.Body = io.NopCloser(bytes.NewBuffer(body))
-
     var req RegisterRequest
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         log.Printf("Error decoding registration request: %v", err)
@@ -140,8 +408,6 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    log.Printf("Parsed registration request: %+v", req)
-
     dob, err := time.Parse("2006-01-02", req.DOB)
     if err != nil {
         utils.RespondWithError(w, domain.NewAPIError(
@@ -161,7 +427,14 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
         DOB:       dob,
     }
 
-    if err := h.userService.Register(r.Context(), user); err != nil {
+    if err := h.invitationService.Register(r.Context(), user, req.InviteCode); err != nil {
+        // Refusals by the registration or password policy keep their own
+        // status and message; everything else is reported as before.
+        var apiErr *domain.APIError
+        if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusForbidden || apiErr.StatusCode == http.StatusBadRequest) {
+            utils.RespondWithError(w, apiErr)
+            return
+        }
         utils.RespondWithError(w, domain.NewAPIError(
             http.StatusBadRequest,
             domain.ErrCodeInvalidInput,
@@ -171,8 +444,13 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    // The account exists either way; a failed email can be resent later.
+    if err := h.emailVerificationService.SendVerification(r.Context(), user); err != nil {
+        log.Printf("Error sending verification email to user %d: %v", user.ID, err)
+    }
+
     utils.RespondWithJSON(w, http.StatusCreated, map[string]string{
-        "message": "User registered successfully",
+        "message": "User registered successfully. Check your email to verify your address.",
     })
 }
 
@@ -192,6 +470,8 @@ type RegisterRequest struct {
     FirstName string `json:"firstName" binding:"required"`
     Surname   string `json:"surname" binding:"required"`
     DOB       string `json:"dob" binding:"required"`
+    // InviteCode is required when registration is by invitation only.
+    InviteCode string `json:"inviteCode,omitempty"`
 }
 
 func (h *AuthHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
@@ -225,6 +505,6 @@ func (h *AuthHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
     
     user.Password = ""
 
-    log.Printf("Successfully retrieved user: %+v", user)
+    log.Printf("Successfully retrieved user ID: %d", user.ID)
     utils.RespondWithJSON(w, http.StatusOK, user)
 }
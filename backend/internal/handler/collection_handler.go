@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	collectionInterface "tech-test/backend/internal/service/interfaces/collection"
+	"tech-test/backend/internal/utils"
+)
+
+type CollectionHandler struct {
+	collectionService collectionInterface.Service
+	config            config.FileConfig
+	logger            *zap.Logger
+}
+
+func NewCollectionHandler(collectionService collectionInterface.Service, config config.FileConfig, logger *zap.Logger) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+		config:            config,
+		logger:            logger,
+	}
+}
+
+func (h *CollectionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	collection, err := h.collectionService.Create(r.Context(), userID, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, collection)
+}
+
+func (h *CollectionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	collections, err := h.collectionService.ListByUser(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": collections,
+	})
+}
+
+func (h *CollectionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseCollectionID(w, r)
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionService.GetByID(r.Context(), id)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, collection)
+}
+
+func (h *CollectionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseCollectionID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	collection, err := h.collectionService.Update(r.Context(), id, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, collection)
+}
+
+func (h *CollectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseCollectionID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.Delete(r.Context(), id); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Collection deleted successfully",
+	})
+}
+
+func (h *CollectionHandler) Share(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseCollectionID(w, r)
+	if !ok {
+		return
+	}
+
+	shareableID, err := h.collectionService.Share(r.Context(), id)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"shareableId":   shareableID,
+		"shareableLink": fmt.Sprintf("%s/shared/collections/%s", h.config.BaseURL, shareableID),
+		"message":       "Collection shared successfully",
+	})
+}
+
+// GetShared returns the public JSON listing of a shared collection.
+func (h *CollectionHandler) GetShared(w http.ResponseWriter, r *http.Request) {
+	collection, err := h.collectionService.GetByShareID(r.Context(), mux.Vars(r)["shareId"])
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, collection.ToShared(h.config.BaseURL))
+}
+
+// DownloadSharedFile streams a single member of a shared collection.
+func (h *CollectionHandler) DownloadSharedFile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	collection, err := h.collectionService.GetByShareID(r.Context(), vars["shareId"])
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	fileID, err := strconv.ParseUint(vars["fileId"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid file ID",
+			err,
+		))
+		return
+	}
+
+	file, ok := collection.FileByID(uint(fileID))
+	if !ok {
+		utils.RespondWithError(w, domain.ErrFileNotFound)
+		return
+	}
+
+	content, err := os.Open(file.Path)
+	if err != nil {
+		h.logger.Error("Failed to open shared collection file",
+			zap.String("path", file.Path),
+			zap.Error(err))
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusNotFound,
+			domain.ErrCodeNotFound,
+			"File not found on disk",
+			err,
+		))
+		return
+	}
+	defer content.Close()
+
+	contentType := file.MimeType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", file.Name))
+
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Error("Failed to stream shared collection file",
+			zap.String("path", file.Path),
+			zap.Error(err))
+	}
+}
+
+// DownloadSharedZip streams every file of a shared collection as one ZIP
+// archive. The archive is written directly to the response, so a file that
+// disappears from disk halfway through is skipped rather than failing the
+// whole download.
+func (h *CollectionHandler) DownloadSharedZip(w http.ResponseWriter, r *http.Request) {
+	collection, err := h.collectionService.GetByShareID(r.Context(), mux.Vars(r)["shareId"])
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	// Several files can take longer to send than the server's write
+	// timeout allows, and a cut-off archive is unreadable.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to lift write deadline for collection archive", zap.Error(err))
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.zip\"", zipBaseName(collection.Name)))
+
+	archive := zip.NewWriter(w)
+	defer archive.Close()
+
+	names := make(map[string]int)
+	for _, file := range collection.Files {
+		if err := addFileToZip(archive, file, uniqueZipName(names, file.Name)); err != nil {
+			h.logger.Error("Failed to add file to collection archive",
+				zap.Uint("fileID", file.ID),
+				zap.String("path", file.Path),
+				zap.Error(err))
+		}
+	}
+}
+
+func parseCollectionID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid collection ID",
+			err,
+		))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func addFileToZip(archive *zip.Writer, file domain.File, name string) error {
+	content, err := os.Open(file.Path)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: file.CreatedAt,
+	}
+	entry, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(entry, content)
+	return err
+}
+
+// uniqueZipName disambiguates files that share a name inside one archive.
+func uniqueZipName(seen map[string]int, name string) string {
+	count := seen[name]
+	seen[name] = count + 1
+	if count == 0 {
+		return name
+	}
+
+	ext := ""
+	if dot := strings.LastIndex(name, "."); dot > 0 {
+		ext = name[dot:]
+		name = name[:dot]
+	}
+	return fmt.Sprintf("%s (%d)%s", name, count, ext)
+}
+
+func zipBaseName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
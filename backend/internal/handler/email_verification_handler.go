@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	emailVerificationInterface "tech-test/backend/internal/service/interfaces/emailverification"
+	"tech-test/backend/internal/utils"
+)
+
+type EmailVerificationHandler struct {
+	emailVerificationService emailVerificationInterface.Service
+}
+
+func NewEmailVerificationHandler(emailVerificationService emailVerificationInterface.Service) *EmailVerificationHandler {
+	return &EmailVerificationHandler{emailVerificationService: emailVerificationService}
+}
+
+// Verify godoc
+// @Summary Confirm an email address
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.VerifyEmailRequest true "Token from the verification email"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} domain.APIError
+// @Router /api/email/verify [post]
+func (h *EmailVerificationHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	var req domain.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	if err := h.emailVerificationService.Verify(r.Context(), req.Token); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Email address verified",
+	})
+}
+
+// Resend godoc
+// @Summary Send a new verification email to the current user
+// @Tags Authentication
+// @Produce json
+// @Success 202 {object} map[string]string
+// @Failure 409 {object} domain.APIError
+// @Failure 429 {object} domain.APIError
+// @Router /api/email/verify/resend [post]
+func (h *EmailVerificationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.emailVerificationService.Resend(r.Context(), userID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusAccepted, map[string]string{
+		"message": "Verification email sent",
+	})
+}
@@ -7,10 +7,12 @@ import (
     "github.com/gorilla/mux"
     "tech-test/backend/internal/domain"
     fileInterface "tech-test/backend/internal/service/interfaces/file"
+    shareInterface "tech-test/backend/internal/service/interfaces/share"
     "tech-test/backend/internal/utils"
     "tech-test/backend/internal/middleware"
     "os"
     "fmt"
+    "encoding/json"
     "io"
     "math"
     "log"
@@ -22,13 +24,14 @@ import (
 )
 
 type FileHandler struct {
-    fileService fileInterface.Service
+    fileService  fileInterface.Service
+    shareService shareInterface.Service
     uploadDir   string
     config      config.FileConfig
     logger      *zap.Logger
 }
 
-func NewFileHandler(fileService fileInterface.Service, config config.FileConfig) *FileHandler {
+func NewFileHandler(fileService fileInterface.Service, shareService shareInterface.Service, config config.FileConfig) *FileHandler {
     absUploadDir, err := filepath.Abs(config.UploadDir)
     if err != nil {
         log.Printf("Error getting absolute path: %v", err)
@@ -36,10 +39,11 @@ func NewFileHandler(fileService fileInterface.Service, config config.FileConfig)
     }
 
     return &FileHandler{
-        fileService: fileService,
-        uploadDir:   absUploadDir,
-        config:      config,
-        logger:      zap.NewExample(),
+        fileService:  fileService,
+        shareService: shareService,
+        uploadDir:    absUploadDir,
+        config:       config,
+        logger:       zap.NewExample(),
     }
 }
 
@@ -66,72 +70,27 @@ func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    file, header, err := r.FormFile("file")
-    if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusBadRequest,
-            domain.ErrCodeInvalidInput,
-            "No file provided",
-            err,
-        ))
+    fileRecord, apiErr := storeFormFile(r, h.uploadDir)
+    if apiErr != nil {
+        utils.RespondWithError(w, apiErr)
         return
     }
-    defer file.Close()
+    fileRecord.UserID = userID
 
-    filename := uuid.New().String() + "_" + header.Filename
-    filePath := filepath.Join(h.uploadDir, filename)
-
-    if err := os.MkdirAll(h.uploadDir, 0755); err != nil {
-        h.logger.Error("Failed to create upload directory", 
-            zap.String("dir", h.uploadDir),
-            zap.Error(err))
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to create upload directory",
-            err,
-        ))
-        return
-    }
-
-    dst, err := os.Create(filePath)
-    if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to create file",
-            err,
-        ))
-        return
-    }
-    defer dst.Close()
-
-    if _, err := io.Copy(dst, file); err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to save file",
-            err,
-        ))
-        return
-    }
-
-    fileRecord := &domain.File{
-        UserID:   userID,
-        Name:     header.Filename,
-        MimeType: header.Header.Get("Content-Type"),
-        Size:     header.Size,
-        Path:     filePath,
+    if orgID := r.FormValue("organizationId"); orgID != "" {
+        id, err := strconv.ParseUint(orgID, 10, 32)
+        if err != nil {
+            os.Remove(fileRecord.Path)
+            utils.RespondWithError(w, domain.NewInvalidInputError("Invalid organization ID"))
+            return
+        }
+        organizationID := uint(id)
+        fileRecord.OrganizationID = &organizationID
     }
 
     if err := h.fileService.Upload(r.Context(), fileRecord); err != nil {
-        os.Remove(filePath)
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to record file information",
-            err,
-        ))
+        os.Remove(fileRecord.Path)
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
@@ -141,12 +100,7 @@ func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
 func (h *FileHandler) List(w http.ResponseWriter, r *http.Request) {
     files, err := h.fileService.List(r.Context())
     if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to list files",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
@@ -168,12 +122,7 @@ func (h *FileHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
     file, err := h.fileService.GetByID(r.Context(), uint(id))
     if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusNotFound,
-            domain.ErrCodeNotFound,
-            "File not found",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
@@ -195,27 +144,17 @@ func (h *FileHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
     file, err := h.fileService.GetByID(r.Context(), uint(id))
     if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusNotFound,
-            domain.ErrCodeNotFound,
-            "File not found",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
-    os.Remove(file.Path)
-
     if err := h.fileService.Delete(r.Context(), uint(id)); err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to delete file record",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
+    os.Remove(file.Path)
+
     utils.RespondWithJSON(w, http.StatusOK, map[string]string{
         "message": "File deleted successfully",
     })
@@ -234,17 +173,34 @@ func (h *FileHandler) GetUserFiles(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    h.listFiles(w, r, domain.PersonalFiles(userID))
+}
+
+// ListOrganizationFiles godoc
+// @Summary List an organization's files
+// @Tags Organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/organizations/{id}/files [get]
+func (h *FileHandler) ListOrganizationFiles(w http.ResponseWriter, r *http.Request) {
+    orgID, ok := organizationID(w, r)
+    if !ok {
+        return
+    }
+
+    h.listFiles(w, r, domain.OrganizationFiles(orgID))
+}
+
+func (h *FileHandler) listFiles(w http.ResponseWriter, r *http.Request, scope domain.FileScope) {
     page, _ := strconv.Atoi(r.URL.Query().Get("page"))
     pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
 
-    files, total, err := h.fileService.GetUserFilesPaginated(r.Context(), userID, page, pageSize)
+    files, total, err := h.fileService.GetFilesPaginated(r.Context(), scope, page, pageSize)
     if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to fetch files",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
@@ -270,14 +226,9 @@ func (h *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    file, err := h.fileService.GetByID(r.Context(), uint(fileID))
+    file, err := h.fileService.GetForDownload(r.Context(), uint(fileID))
     if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusNotFound,
-            domain.ErrCodeNotFound,
-            "File not found",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
@@ -335,10 +286,11 @@ func (h *FileHandler) View(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    file, err := h.fileService.GetByID(r.Context(), uint(fileID))
+    file, err := h.fileService.GetForDownload(r.Context(), uint(fileID))
     if err != nil {
-        log.Printf("File with ID %d not found: %v", fileID, err)
-        http.Error(w, "File not found", http.StatusNotFound)
+        log.Printf("File with ID %d not available: %v", fileID, err)
+        apiErr := domain.WrapError(err)
+        http.Error(w, apiErr.Message, apiErr.StatusCode)
         return
     }
 
@@ -368,19 +320,35 @@ func (h *FileHandler) SearchFiles(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    h.searchFiles(w, r, domain.PersonalFiles(userID))
+}
+
+// SearchOrganizationFiles godoc
+// @Summary Search an organization's files
+// @Tags Organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param q query string true "Search term"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/organizations/{id}/files/search [get]
+func (h *FileHandler) SearchOrganizationFiles(w http.ResponseWriter, r *http.Request) {
+    orgID, ok := organizationID(w, r)
+    if !ok {
+        return
+    }
+
+    h.searchFiles(w, r, domain.OrganizationFiles(orgID))
+}
+
+func (h *FileHandler) searchFiles(w http.ResponseWriter, r *http.Request, scope domain.FileScope) {
     searchTerm := r.URL.Query().Get("q")
 
-    log.Printf("Searching files for userID: %d with term: %s", userID, searchTerm)
+    log.Printf("Searching files for userID: %d, organizationID: %d with term: %s", scope.UserID, scope.OrganizationID, searchTerm)
 
-    files, err := h.fileService.SearchFiles(r.Context(), userID, searchTerm)
+    files, err := h.fileService.SearchFiles(r.Context(), scope, searchTerm)
     if err != nil {
         log.Printf("Error searching files: %v", err)
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to search files",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
@@ -390,6 +358,9 @@ func (h *FileHandler) SearchFiles(w http.ResponseWriter, r *http.Request) {
     })
 }
 
+// GenerateShareableLink creates a new public link for a file. The body is
+// optional; when it lists recipients, each of them is emailed a personalized
+// link together with the message, and an existing link is kept.
 func (h *FileHandler) GenerateShareableLink(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     fileIDStr := vars["id"]
@@ -405,30 +376,104 @@ func (h *FileHandler) GenerateShareableLink(w http.ResponseWriter, r *http.Reque
         return
     }
 
-    shareableID := uuid.New().String()
-
-    err = h.fileService.UpdateShareableID(r.Context(), strconv.FormatUint(fileID, 10), shareableID)
-    if err != nil {
+    var shareRequest domain.ShareRequest
+    if err := json.NewDecoder(r.Body).Decode(&shareRequest); err != nil && err != io.EOF {
         utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to update file: " + err.Error(),
+            http.StatusBadRequest,
+            domain.ErrCodeInvalidInput,
+            "Invalid request body",
             err,
         ))
         return
     }
 
+    file, err := h.fileService.GetByID(r.Context(), uint(fileID))
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    // Refuse the request before touching the link, so a bad recipient list
+    // does not rotate or re-date a link that is already in use.
+    if len(shareRequest.Recipients) > 0 {
+        if err := h.shareService.CheckRecipients(r.Context(), file, shareRequest); err != nil {
+            utils.RespondWithError(w, domain.WrapError(err))
+            return
+        }
+    }
+
+    // Adding recipients keeps the current link and its expiry, so personal
+    // links sent earlier go on working, unless a new expiry is given or the
+    // link has already expired. Sharing without recipients issues a new link
+    // with the requested expiry.
+    keepLink := file.ShareableID != "" && len(shareRequest.Recipients) > 0 && !file.IsShareExpired()
+    if !keepLink || shareRequest.ExpiresAt != nil {
+        if err := h.fileService.UpdateShareExpiry(r.Context(), uint(fileID), shareRequest.ExpiresAt); err != nil {
+            utils.RespondWithError(w, domain.WrapError(err))
+            return
+        }
+        file.ShareExpiresAt = shareRequest.ExpiresAt
+    }
+
+    shareableID := file.ShareableID
+    if !keepLink {
+        shareableID = uuid.New().String()
+
+        err = h.fileService.UpdateShareableID(r.Context(), strconv.FormatUint(fileID, 10), shareableID)
+        if err != nil {
+            utils.RespondWithError(w, domain.WrapError(err))
+            return
+        }
+        file.ShareableID = shareableID
+    }
+
     shareableLink := fmt.Sprintf("%s/shared/%s", h.config.BaseURL, shareableID)
 
     response := map[string]interface{}{
         "shareableId":   shareableID,
         "shareableLink": shareableLink,
+        "previewLink":   shareableLink + "/preview",
+        "expiresAt":     file.ShareExpiresAt,
         "message":       "File shared successfully",
     }
 
+    if len(shareRequest.Recipients) > 0 {
+        recipients, err := h.shareService.Notify(r.Context(), file, shareRequest)
+        if err != nil {
+            utils.RespondWithError(w, domain.WrapError(err))
+            return
+        }
+        response["recipients"] = recipients
+    }
+
     utils.RespondWithJSON(w, http.StatusOK, response)
 }
 
+// GetShareRecipients lists who a file was emailed to and how often each
+// recipient opened their link.
+func (h *FileHandler) GetShareRecipients(w http.ResponseWriter, r *http.Request) {
+    fileID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+    if err != nil {
+        utils.RespondWithError(w, domain.NewAPIError(
+            http.StatusBadRequest,
+            domain.ErrCodeInvalidInput,
+            "Invalid file ID",
+            err,
+        ))
+        return
+    }
+
+    recipients, err := h.shareService.ListRecipients(r.Context(), uint(fileID))
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return
+    }
+
+    utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+        "data": recipients,
+    })
+}
+
 func (h *FileHandler) GetSharedFile(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     shareID := vars["shareId"]
@@ -441,15 +486,18 @@ func (h *FileHandler) GetSharedFile(w http.ResponseWriter, r *http.Request) {
         h.logger.Error("Failed to get file by share ID", 
             zap.String("shareId", shareID),
             zap.Error(err))
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusNotFound,
-            domain.ErrCodeNotFound,
-            "File not found",
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
+    if token := r.URL.Query().Get("r"); token != "" {
+        if err := h.shareService.RecordAccess(r.Context(), file.ID, token); err != nil {
+            h.logger.Warn("Failed to record recipient access",
+                zap.String("shareId", shareID),
+                zap.Error(err))
+        }
+    }
+
     filePath := file.Path  
 
     h.logger.Debug("Attempting to access file", 
@@ -544,12 +592,7 @@ func (h *FileHandler) ShareFile(w http.ResponseWriter, r *http.Request) {
 
     err = h.fileService.UpdateShareableID(r.Context(), strconv.FormatUint(fileID, 10), shareableID)
     if err != nil {
-        utils.RespondWithError(w, domain.NewAPIError(
-            http.StatusInternalServerError,
-            domain.ErrCodeInternal,
-            "Failed to update file: " + err.Error(),
-            err,
-        ))
+        utils.RespondWithError(w, domain.WrapError(err))
         return
     }
 
@@ -562,3 +605,79 @@ func (h *FileHandler) ShareFile(w http.ResponseWriter, r *http.Request) {
     utils.RespondWithJSON(w, http.StatusOK, response)
 }
 
+// storeFormFile copies the "file" field of an already parsed multipart form
+// into uploadDir and returns an unsaved record describing it. Callers are
+// responsible for removing the file again if the record cannot be persisted.
+func storeFormFile(r *http.Request, uploadDir string) (*domain.File, *domain.APIError) {
+    file, header, err := r.FormFile("file")
+    if err != nil {
+        return nil, domain.NewAPIError(
+            http.StatusBadRequest,
+            domain.ErrCodeInvalidInput,
+            "No file provided",
+            err,
+        )
+    }
+    defer file.Close()
+
+    filePath, _, apiErr := saveUpload(file, header.Filename, uploadDir, -1)
+    if apiErr != nil {
+        return nil, apiErr
+    }
+
+    return &domain.File{
+        Name:     header.Filename,
+        MimeType: header.Header.Get("Content-Type"),
+        Size:     header.Size,
+        Path:     filePath,
+    }, nil
+}
+
+// saveUpload writes src to a new file in uploadDir and returns its path and
+// size. When maxSize is not negative, anything larger is removed again and
+// refused.
+func saveUpload(src io.Reader, name, uploadDir string, maxSize int64) (string, int64, *domain.APIError) {
+    filename := uuid.New().String() + "_" + filepath.Base(name)
+    filePath := filepath.Join(uploadDir, filename)
+
+    if err := os.MkdirAll(uploadDir, 0755); err != nil {
+        return "", 0, domain.NewAPIError(
+            http.StatusInternalServerError,
+            domain.ErrCodeInternal,
+            "Failed to create upload directory",
+            err,
+        )
+    }
+
+    dst, err := os.Create(filePath)
+    if err != nil {
+        return "", 0, domain.NewAPIError(
+            http.StatusInternalServerError,
+            domain.ErrCodeInternal,
+            "Failed to create file",
+            err,
+        )
+    }
+    defer dst.Close()
+
+    if maxSize >= 0 {
+        // One byte over the limit is enough to tell it was exceeded.
+        src = io.LimitReader(src, maxSize+1)
+    }
+    size, err := io.Copy(dst, src)
+    if err != nil {
+        os.Remove(filePath)
+        return "", 0, domain.NewAPIError(
+            http.StatusInternalServerError,
+            domain.ErrCodeInternal,
+            "Failed to save file",
+            err,
+        )
+    }
+    if maxSize >= 0 && size > maxSize {
+        os.Remove(filePath)
+        return "", 0, domain.NewFileTooLargeError(size, maxSize)
+    }
+
+    return filePath, size, nil
+}
@@ -0,0 +1,474 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/middleware"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/service/authz"
+	"tech-test/backend/internal/service/file"
+	"tech-test/backend/internal/service/share"
+	"tech-test/backend/internal/testutil"
+)
+
+type fileFixture struct {
+	*testutil.Env
+	files     interfaces.FileRepository
+	orgs      interfaces.OrganizationRepository
+	router    *mux.Router
+	uploadDir string
+	owner     *domain.User
+	other     *domain.User
+	admin     *domain.User
+	file      *domain.File
+}
+
+// newFileFixture serves the file routes for a database holding a file
+// owner, another user and an admin, and one file owned by the first.
+// withMailer decides whether sharing by email is available.
+func newFileFixture(t *testing.T, withMailer bool) *fileFixture {
+	t.Helper()
+
+	env := testutil.NewEnv(t)
+	f := &fileFixture{
+		Env:       env,
+		files:     sqlite.NewFileRepository(env.DB),
+		orgs:      sqlite.NewOrganizationRepository(env.DB),
+		uploadDir: t.TempDir(),
+		owner:     env.CreateUser(t, &domain.User{Email: "owner@example.com", FirstName: "Olive", Surname: "Owner"}),
+		other:     env.CreateUser(t, &domain.User{Email: "other@example.com", FirstName: "Otto", Surname: "Other"}),
+		admin:     env.CreateUser(t, &domain.User{Email: "admin@example.com", FirstName: "Ada", Surname: "Admin", Role: domain.RoleAdmin}),
+	}
+	f.file = f.createFile(t, f.owner.ID, "share-123")
+
+	logger := zap.NewNop()
+	authorizer := authz.NewAuthorizer(f.orgs, logger)
+	var m mailer.Mailer
+	if withMailer {
+		m = f.Mailer()
+	}
+
+	fileHandler := NewFileHandler(
+		file.NewService(f.files, f.orgs, authorizer, logger, f.uploadDir),
+		share.NewService(f.files, sqlite.NewShareRecipientRepository(f.DB), f.Users, authorizer, m, testutil.BaseURL, logger),
+		config.FileConfig{UploadDir: f.uploadDir, MaxSize: 1 << 20, BaseURL: testutil.BaseURL},
+	)
+
+	f.router = mux.NewRouter()
+	files := f.router.PathPrefix("/api/files").Subrouter()
+	files.Use(testActor)
+	files.HandleFunc("/search", fileHandler.SearchFiles).Methods(http.MethodGet)
+	files.HandleFunc("/my", fileHandler.GetUserFiles).Methods(http.MethodGet)
+	files.HandleFunc("/{id}/download", fileHandler.Download).Methods(http.MethodGet)
+	files.HandleFunc("/{id}/view", fileHandler.View).Methods(http.MethodGet)
+	files.HandleFunc("/{id}", fileHandler.GetByID).Methods(http.MethodGet)
+	files.HandleFunc("/{id}", fileHandler.Delete).Methods(http.MethodDelete)
+	files.HandleFunc("", fileHandler.List).Methods(http.MethodGet)
+	files.HandleFunc("/{id}/share", fileHandler.GenerateShareableLink).Methods(http.MethodPost)
+	files.HandleFunc("/{id}/share/recipients", fileHandler.GetShareRecipients).Methods(http.MethodGet)
+	f.router.HandleFunc("/shared/{shareId}", fileHandler.GetSharedFile).Methods(http.MethodGet)
+	f.router.HandleFunc("/shared/{shareId}/info", fileHandler.GetSharedInfo).Methods(http.MethodGet)
+	f.router.HandleFunc("/shared/{shareId}/preview", fileHandler.GetSharedPreview).Methods(http.MethodGet)
+	return f
+}
+
+func (f *fileFixture) createFile(t *testing.T, userID uint, shareableID string) *domain.File {
+	t.Helper()
+
+	path := filepath.Join(f.uploadDir, fmt.Sprintf("%d_report.pdf", time.Now().UnixNano()))
+	if err := os.WriteFile(path, []byte("%PDF-1.4\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	created := &domain.File{
+		UserID:      userID,
+		Name:        "report.pdf",
+		Path:        path,
+		MimeType:    "application/pdf",
+		ContentType: "application/pdf",
+		Size:        1024,
+		ShareableID: shareableID,
+	}
+	if err := f.files.Create(context.Background(), created); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	return created
+}
+
+// testActor stands in for the authentication middleware, taking the caller
+// from the X-Test-User header and their role from X-Test-Admin.
+func testActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, err := strconv.ParseUint(r.Header.Get("X-Test-User"), 10, 32); err == nil {
+			userID := uint(id)
+			ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+			ctx = domain.ContextWithActor(ctx, domain.Actor{
+				UserID: userID,
+				Admin:  r.Header.Get("X-Test-Admin") == "true",
+			})
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *fileFixture) do(t *testing.T, user *domain.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("failed to encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	if user != nil {
+		req.Header.Set("X-Test-User", idString(user.ID))
+		req.Header.Set("X-Test-Admin", strconv.FormatBool(user.IsAdmin()))
+	}
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func idString(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func (f *fileFixture) reload(t *testing.T) *domain.File {
+	t.Helper()
+
+	stored, err := f.files.GetByID(context.Background(), f.file.ID)
+	if err != nil {
+		t.Fatalf("failed to reload file: %v", err)
+	}
+	return stored
+}
+
+func shareResponse(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func TestGenerateShareableLinkKeepsLinkWhenAddingRecipients(t *testing.T) {
+	f := newFileFixture(t, true)
+	path := "/api/files/" + idString(f.file.ID) + "/share"
+
+	rec := f.do(t, f.owner, http.MethodPost, path, domain.ShareRequest{
+		Recipients: []string{"alice@example.com"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := shareResponse(t, rec)["shareableId"]; got != "share-123" {
+		t.Errorf("shareableId = %v, want the existing share-123", got)
+	}
+	if got := f.reload(t).ShareableID; got != "share-123" {
+		t.Errorf("stored shareable ID = %q, want share-123", got)
+	}
+
+	messages := f.Sink.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("sink received %d messages, want 1", len(messages))
+	}
+	if !strings.Contains(messages[0].Body, testutil.BaseURL+"/shared/share-123/preview?r=") {
+		t.Errorf("email does not link to the existing share:\n%s", messages[0].Body)
+	}
+}
+
+func TestGenerateShareableLinkWithoutRecipientsIssuesNewLink(t *testing.T) {
+	f := newFileFixture(t, true)
+
+	rec := f.do(t, f.owner, http.MethodPost, "/api/files/"+idString(f.file.ID)+"/share", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+	id := shareResponse(t, rec)["shareableId"]
+	if id == "share-123" || id == "" {
+		t.Errorf("shareableId = %v, want a new ID", id)
+	}
+	if got := f.reload(t).ShareableID; got != id {
+		t.Errorf("stored shareable ID = %q, want %v", got, id)
+	}
+	if got := len(f.Sink.Messages()); got != 0 {
+		t.Errorf("sink received %d messages, want 0", got)
+	}
+}
+
+func TestGenerateShareableLinkRefusedRecipientsLeaveLinkUnchanged(t *testing.T) {
+	tests := []struct {
+		name       string
+		withMailer bool
+		recipients []string
+		status     int
+	}{
+		{name: "invalid address", withMailer: true, recipients: []string{"alice@example.com", "not-an-email"}, status: http.StatusBadRequest},
+		{name: "no mailer", withMailer: false, recipients: []string{"alice@example.com"}, status: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFileFixture(t, tt.withMailer)
+
+			rec := f.do(t, f.owner, http.MethodPost, "/api/files/"+idString(f.file.ID)+"/share", domain.ShareRequest{
+				Recipients: tt.recipients,
+			})
+			if rec.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+
+			if got := f.reload(t).ShareableID; got != "share-123" {
+				t.Errorf("shareable ID changed to %q", got)
+			}
+			if got := len(f.Sink.Messages()); got != 0 {
+				t.Errorf("sink received %d messages, want 0", got)
+			}
+		})
+	}
+}
+
+func TestFileRoutesAuthorization(t *testing.T) {
+	callers := []string{"anonymous", "other", "admin", "owner"}
+
+	routes := []struct {
+		method string
+		path   string
+		// want holds the expected status for each of callers.
+		want []int
+		// mutates routes get a fresh fixture for every caller.
+		mutates bool
+	}{
+		{method: http.MethodGet, path: "/api/files/{id}", want: []int{401, 404, 200, 200}},
+		{method: http.MethodGet, path: "/api/files/{id}/download", want: []int{401, 404, 200, 200}},
+		{method: http.MethodGet, path: "/api/files/{id}/view", want: []int{401, 404, 200, 200}},
+		{method: http.MethodGet, path: "/api/files/{id}/share/recipients", want: []int{401, 404, 200, 200}},
+		{method: http.MethodGet, path: "/api/files", want: []int{401, 403, 200, 403}},
+		{method: http.MethodPost, path: "/api/files/{id}/share", want: []int{401, 404, 403, 200}, mutates: true},
+		{method: http.MethodDelete, path: "/api/files/{id}", want: []int{401, 404, 200, 200}, mutates: true},
+	}
+
+	for _, route := range routes {
+		var shared *fileFixture
+		if !route.mutates {
+			shared = newFileFixture(t, false)
+		}
+		for i, caller := range callers {
+			want := route.want[i]
+			t.Run(route.method+" "+route.path+" as "+caller, func(t *testing.T) {
+				f := shared
+				if route.mutates {
+					f = newFileFixture(t, false)
+				}
+				user := map[string]*domain.User{"owner": f.owner, "other": f.other, "admin": f.admin}[caller]
+				path := strings.Replace(route.path, "{id}", idString(f.file.ID), 1)
+
+				rec := f.do(t, user, route.method, path, nil)
+				if rec.Code != want {
+					t.Fatalf("got status %d, want %d: %s", rec.Code, want, rec.Body.String())
+				}
+
+				switch route.method {
+				case http.MethodDelete:
+					_, err := f.files.GetByID(context.Background(), f.file.ID)
+					if deleted := err == nil; deleted == (want == http.StatusOK) {
+						t.Errorf("file record kept = %v after status %d", deleted, rec.Code)
+					}
+				case http.MethodPost:
+					if got := f.reload(t).ShareableID; (got == "share-123") != (want != http.StatusOK) {
+						t.Errorf("shareable ID is %q after status %d", got, rec.Code)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestFileRoutesMissingFile(t *testing.T) {
+	f := newFileFixture(t, false)
+
+	for _, path := range []string{"/api/files/9999", "/api/files/9999/download", "/api/files/9999/view"} {
+		if rec := f.do(t, f.owner, http.MethodGet, path, nil); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s: got status %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func TestFileListingsOnlyShowOwnFiles(t *testing.T) {
+	f := newFileFixture(t, false)
+	f.createFile(t, f.other.ID, "")
+
+	for _, path := range []string{"/api/files/my?page=1&page_size=10", "/api/files/search?q=report"} {
+		rec := f.do(t, f.other, http.MethodGet, path, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: got status %d: %s", path, rec.Code, rec.Body.String())
+		}
+
+		var body struct {
+			Data []domain.File `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("GET %s: invalid JSON: %v", path, err)
+		}
+		if len(body.Data) != 1 {
+			t.Fatalf("GET %s: got %d files, want 1", path, len(body.Data))
+		}
+		if body.Data[0].UserID != f.other.ID {
+			t.Errorf("GET %s: listed file of user %d", path, body.Data[0].UserID)
+		}
+	}
+}
+
+func TestShareExpiryIsShownOnThePublicPages(t *testing.T) {
+	f := newFileFixture(t, true)
+	expiresAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	rec := f.do(t, f.owner, http.MethodPost, "/api/files/"+idString(f.file.ID)+"/share", domain.ShareRequest{
+		ExpiresAt: &expiresAt,
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+	body := shareResponse(t, rec)
+	if got := body["expiresAt"]; got != expiresAt.Format(time.RFC3339) {
+		t.Errorf("expiresAt = %v, want %s", got, expiresAt.Format(time.RFC3339))
+	}
+	shareID := body["shareableId"].(string)
+
+	rec = f.do(t, nil, http.MethodGet, "/shared/"+shareID+"/info", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("info: got status %d: %s", rec.Code, rec.Body.String())
+	}
+	var preview domain.SharePreview
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("invalid info response: %v", err)
+	}
+	if preview.ExpiresAt == nil || !preview.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("info expiresAt = %v, want %v", preview.ExpiresAt, expiresAt)
+	}
+
+	rec = f.do(t, nil, http.MethodGet, "/shared/"+shareID+"/preview", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("preview: got status %d", rec.Code)
+	}
+	if want := "<dt>Expires</dt><dd>" + expiresAt.Format("2 Jan 2006 15:04 MST"); !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("preview page does not show the expiry %q", want)
+	}
+}
+
+func TestExpiredShareLinkIsGone(t *testing.T) {
+	f := newFileFixture(t, true)
+	expired := time.Now().Add(-time.Minute)
+	if err := f.files.UpdateShareExpiry(context.Background(), f.file.ID, &expired); err != nil {
+		t.Fatalf("failed to expire link: %v", err)
+	}
+
+	for _, path := range []string{"/shared/share-123", "/shared/share-123/info", "/shared/share-123/preview"} {
+		if rec := f.do(t, nil, http.MethodGet, path, nil); rec.Code != http.StatusGone {
+			t.Errorf("GET %s: got status %d, want %d", path, rec.Code, http.StatusGone)
+		}
+	}
+}
+
+func TestGenerateShareableLinkExpiry(t *testing.T) {
+	day := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	week := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		current *time.Time
+		request domain.ShareRequest
+		status  int
+		// newLink reports whether share-123 must be replaced.
+		newLink bool
+		want    *time.Time
+	}{
+		{
+			name:    "new link without expiry clears it",
+			current: &day,
+			request: domain.ShareRequest{},
+			status:  http.StatusOK,
+			newLink: true,
+		},
+		{
+			name:    "adding recipients keeps the expiry",
+			current: &day,
+			request: domain.ShareRequest{Recipients: []string{"alice@example.com"}},
+			status:  http.StatusOK,
+			want:    &day,
+		},
+		{
+			name:    "adding recipients with an expiry changes it",
+			current: &day,
+			request: domain.ShareRequest{Recipients: []string{"alice@example.com"}, ExpiresAt: &week},
+			status:  http.StatusOK,
+			want:    &week,
+		},
+		{
+			name:    "adding recipients to an expired link issues a new one",
+			current: &past,
+			request: domain.ShareRequest{Recipients: []string{"alice@example.com"}},
+			status:  http.StatusOK,
+			newLink: true,
+		},
+		{
+			name:    "expiry in the past is refused",
+			current: &day,
+			request: domain.ShareRequest{ExpiresAt: &past},
+			status:  http.StatusBadRequest,
+			want:    &day,
+		},
+		{
+			name:    "refused recipients leave the expiry",
+			current: &day,
+			request: domain.ShareRequest{Recipients: []string{"not-an-email"}, ExpiresAt: &week},
+			status:  http.StatusBadRequest,
+			want:    &day,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFileFixture(t, true)
+			if err := f.files.UpdateShareExpiry(context.Background(), f.file.ID, tt.current); err != nil {
+				t.Fatalf("failed to set expiry: %v", err)
+			}
+
+			rec := f.do(t, f.owner, http.MethodPost, "/api/files/"+idString(f.file.ID)+"/share", tt.request)
+			if rec.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+
+			stored := f.reload(t)
+			if changed := stored.ShareableID != "share-123"; changed != tt.newLink {
+				t.Errorf("shareable ID = %q, new link %v, want %v", stored.ShareableID, changed, tt.newLink)
+			}
+			switch {
+			case tt.want == nil && stored.ShareExpiresAt != nil:
+				t.Errorf("share expiry = %v, want none", stored.ShareExpiresAt)
+			case tt.want != nil && (stored.ShareExpiresAt == nil || !stored.ShareExpiresAt.Equal(*tt.want)):
+				t.Errorf("share expiry = %v, want %v", stored.ShareExpiresAt, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	impersonationInterface "tech-test/backend/internal/service/interfaces/impersonation"
+	"tech-test/backend/internal/utils"
+)
+
+type ImpersonationHandler struct {
+	impersonationService impersonationInterface.Service
+}
+
+func NewImpersonationHandler(impersonationService impersonationInterface.Service) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// Start godoc
+// @Summary Impersonate a user
+// @Description Issues a short-lived token to act as the user while reproducing a support issue. The token cannot be refreshed, deletes are refused unless allowDestructive is set, and every request made with it is written to the audit trail. Administrators cannot be impersonated. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body domain.StartImpersonationRequest true "Reason and duration"
+// @Success 201 {object} domain.ImpersonationToken
+// @Failure 403 {object} domain.APIError
+// @Router /api/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Start(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	userID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid user ID"))
+		return
+	}
+
+	var req domain.StartImpersonationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+		return
+	}
+
+	token, err := h.impersonationService.Start(r.Context(), adminID, uint(userID), req, sessionClient(r))
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, token)
+}
+
+// List godoc
+// @Summary List impersonation sessions
+// @Description Most recent first. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Maximum entries (default 100)"
+// @Success 200 {array} domain.Impersonation
+// @Router /api/admin/impersonations [get]
+func (h *ImpersonationHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	impersonations, err := h.impersonationService.List(r.Context(), limit)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": impersonations,
+	})
+}
+
+// End godoc
+// @Summary End an impersonation session
+// @Description Its token stops working immediately. Admin only.
+// @Tags Admin
+// @Param id path string true "Impersonation ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/impersonations/{id} [delete]
+func (h *ImpersonationHandler) End(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.impersonationService.End(r.Context(), mux.Vars(r)["id"], adminID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Impersonation ended",
+	})
+}
+
+// Stop godoc
+// @Summary Stop impersonating
+// @Description Ends the impersonation the request is made with. The impersonation token stops working; the administrator's own session is unaffected.
+// @Tags Authentication
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} domain.APIError
+// @Router /api/impersonation/end [post]
+func (h *ImpersonationHandler) Stop(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaimsFromContext(r.Context())
+	if !ok || claims.Act == nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Not an impersonation session"))
+		return
+	}
+
+	if err := h.impersonationService.End(r.Context(), claims.SessionID, claims.Act.UserID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Impersonation ended",
+	})
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	invitationInterface "tech-test/backend/internal/service/interfaces/invitation"
+	"tech-test/backend/internal/utils"
+)
+
+type InvitationHandler struct {
+	invitationService invitationInterface.Service
+}
+
+func NewInvitationHandler(invitationService invitationInterface.Service) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService}
+}
+
+// Policy godoc
+// @Summary Get the registration policy
+// @Description Tells the sign-up form whether anyone may register, only invited users, or only addresses at the listed domains.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} domain.RegistrationPolicy
+// @Router /api/register/policy [get]
+func (h *InvitationHandler) Policy(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, h.invitationService.Policy())
+}
+
+// Create godoc
+// @Summary Create an invitation
+// @Description Returns a single-use code and sign-up link, which are not shown again. An invitation with an email can only be used by that address and is sent to it when mail is configured. The role and organization are applied to the new account. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body domain.CreateInvitationRequest true "Invitation"
+// @Success 201 {object} domain.CreatedInvitation
+// @Failure 400 {object} domain.APIError
+// @Router /api/admin/invitations [post]
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+		return
+	}
+
+	invitation, err := h.invitationService.Create(r.Context(), req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, invitation)
+}
+
+// List godoc
+// @Summary List invitations
+// @Description Newest first, including used and expired ones. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Maximum entries (default 100)"
+// @Success 200 {array} domain.Invitation
+// @Router /api/admin/invitations [get]
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	invitations, err := h.invitationService.List(r.Context(), limit)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": invitations,
+	})
+}
+
+// Revoke godoc
+// @Summary Revoke an invitation
+// @Description Its code stops working. Accounts already created with it are unaffected. Admin only.
+// @Tags Admin
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/invitations/{id} [delete]
+func (h *InvitationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid invitation ID"))
+		return
+	}
+
+	if err := h.invitationService.Revoke(r.Context(), uint(id)); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Invitation revoked",
+	})
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"tech-test/backend/internal/jwtkeys"
+	"tech-test/backend/internal/utils"
+)
+
+type JWKSHandler struct {
+	keys *jwtkeys.Keyring
+}
+
+func NewJWKSHandler(keys *jwtkeys.Keyring) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// JWKS godoc
+// @Summary Public keys for verifying access tokens
+// @Description Keys are identified by the kid header of each token. The set is empty when tokens are signed with a shared HS256 secret.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} jwtkeys.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	utils.RespondWithJSON(w, http.StatusOK, h.keys.JWKS())
+}
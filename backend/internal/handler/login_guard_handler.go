@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"tech-test/backend/internal/domain"
+	loginGuardInterface "tech-test/backend/internal/service/interfaces/loginguard"
+	"tech-test/backend/internal/utils"
+)
+
+type LoginGuardHandler struct {
+	loginGuard loginGuardInterface.Service
+}
+
+func NewLoginGuardHandler(loginGuard loginGuardInterface.Service) *LoginGuardHandler {
+	return &LoginGuardHandler{loginGuard: loginGuard}
+}
+
+// ListAttempts godoc
+// @Summary List recent login attempts
+// @Description Newest first, optionally filtered by email or client address. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param email query string false "Account email"
+// @Param ip query string false "Client address"
+// @Param limit query int false "Maximum entries (default 100)"
+// @Success 200 {array} domain.LoginAttempt
+// @Router /api/admin/login-attempts [get]
+func (h *LoginGuardHandler) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	attempts, err := h.loginGuard.ListAttempts(r.Context(), domain.LoginAttemptFilter{
+		Email: query.Get("email"),
+		IP:    query.Get("ip"),
+		Limit: limit,
+	})
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": attempts,
+	})
+}
+
+// ListLockouts godoc
+// @Summary List lockout events
+// @Description Accounts and addresses locked out after repeated failed logins, newest first. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Maximum entries (default 100)"
+// @Success 200 {array} domain.LockoutEvent
+// @Router /api/admin/lockouts [get]
+func (h *LoginGuardHandler) ListLockouts(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	lockouts, err := h.loginGuard.ListLockouts(r.Context(), limit)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": lockouts,
+	})
+}
+
+// ClearLockout godoc
+// @Summary Lift a login lockout
+// @Description Clears the failures, delays and lockout for an account, an address or both. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body domain.ClearLockoutRequest true "Account or address"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} domain.APIError
+// @Router /api/admin/lockouts/clear [post]
+func (h *LoginGuardHandler) ClearLockout(w http.ResponseWriter, r *http.Request) {
+	var req domain.ClearLockoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	if err := h.loginGuard.Clear(r.Context(), req); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Lockout cleared",
+	})
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	mfaInterface "tech-test/backend/internal/service/interfaces/mfa"
+	"tech-test/backend/internal/utils"
+)
+
+type MFAHandler struct {
+	mfaService mfaInterface.Service
+}
+
+func NewMFAHandler(mfaService mfaInterface.Service) *MFAHandler {
+	return &MFAHandler{mfaService: mfaService}
+}
+
+// Status godoc
+// @Summary Get two-factor authentication status
+// @Tags MFA
+// @Produce json
+// @Success 200 {object} domain.MFAStatus
+// @Router /api/mfa [get]
+func (h *MFAHandler) Status(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	status, err := h.mfaService.Status(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, status)
+}
+
+// BeginEnrollment godoc
+// @Summary Start TOTP enrollment
+// @Description Returns a secret and an otpauth URI to show as a QR code. 2FA is enabled once a code is verified.
+// @Tags MFA
+// @Produce json
+// @Success 200 {object} domain.TOTPEnrollment
+// @Failure 409 {object} domain.APIError
+// @Router /api/mfa/totp/enroll [post]
+func (h *MFAHandler) BeginEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	enrollment, err := h.mfaService.BeginEnrollment(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, enrollment)
+}
+
+// ConfirmEnrollment godoc
+// @Summary Verify TOTP enrollment
+// @Description Enables 2FA and returns single-use recovery codes, which are only shown once.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param request body domain.TOTPCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} map[string][]string
+// @Failure 401 {object} domain.APIError
+// @Router /api/mfa/totp/verify [post]
+func (h *MFAHandler) ConfirmEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(r.Context(), userID, req.Code)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"recoveryCodes": codes,
+	})
+}
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param request body domain.TOTPCodeRequest true "TOTP or recovery code"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} domain.APIError "2FA is mandatory"
+// @Router /api/mfa/totp/disable [post]
+func (h *MFAHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaService.Disable(r.Context(), userID, req.Code); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Replace recovery codes
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param request body domain.TOTPCodeRequest true "Current TOTP code"
+// @Success 200 {object} map[string][]string
+// @Router /api/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"recoveryCodes": codes,
+	})
+}
+
+// GetPolicy godoc
+// @Summary Get the 2FA policy
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} domain.MFAPolicyRequest
+// @Router /api/admin/settings/mfa [get]
+func (h *MFAHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	required, err := h.mfaService.IsRequired(r.Context())
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, domain.MFAPolicyRequest{Required: required})
+}
+
+// SetPolicy godoc
+// @Summary Require 2FA for all accounts
+// @Description When required, users without an authenticator must enroll during their next login.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body domain.MFAPolicyRequest true "Policy"
+// @Success 200 {object} domain.MFAPolicyRequest
+// @Router /api/admin/settings/mfa [put]
+func (h *MFAHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	var req domain.MFAPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	if err := h.mfaService.SetRequired(r.Context(), req.Required); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, req)
+}
+
+// ResetUser godoc
+// @Summary Reset a user's 2FA
+// @Description Remove a user's authenticator and recovery codes when they have lost both.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Router /api/admin/users/{id}/mfa [delete]
+func (h *MFAHandler) ResetUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid user ID",
+			err,
+		))
+		return
+	}
+
+	if err := h.mfaService.Reset(r.Context(), uint(userID)); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Two-factor authentication reset",
+	})
+}
+
+func (h *MFAHandler) codeRequest(w http.ResponseWriter, r *http.Request) (uint, domain.TOTPCodeRequest, bool) {
+	var req domain.TOTPCodeRequest
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return 0, req, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return 0, req, false
+	}
+	return userID, req, true
+}
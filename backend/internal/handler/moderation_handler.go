@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	moderationInterface "tech-test/backend/internal/service/interfaces/moderation"
+	"tech-test/backend/internal/utils"
+)
+
+type ModerationHandler struct {
+	moderationService moderationInterface.Service
+}
+
+func NewModerationHandler(moderationService moderationInterface.Service) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// ReportShare lets anyone who can reach a public share link report it.
+func (h *ModerationHandler) ReportShare(w http.ResponseWriter, r *http.Request) {
+	var req domain.AbuseReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	report, err := h.moderationService.Report(r.Context(), mux.Vars(r)["shareId"], ip, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":      report.ID,
+		"message": "Report received. Thank you.",
+	})
+}
+
+func (h *ModerationHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.moderationService.ListReports(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": reports,
+	})
+}
+
+func (h *ModerationHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	reportID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid report ID",
+			err,
+		))
+		return
+	}
+
+	var req domain.ResolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	report, err := h.moderationService.Resolve(r.Context(), adminID, uint(reportID), req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, report)
+}
+
+func (h *ModerationHandler) ReleaseFile(w http.ResponseWriter, r *http.Request) {
+	fileID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid file ID",
+			err,
+		))
+		return
+	}
+
+	if err := h.moderationService.ReleaseFile(r.Context(), uint(fileID)); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "File released from moderation",
+	})
+}
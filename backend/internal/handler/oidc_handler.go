@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	ssoInterface "tech-test/backend/internal/service/interfaces/sso"
+	"tech-test/backend/internal/utils"
+)
+
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	ssoService ssoInterface.Service
+	config     config.OIDCConfig
+	logger     *zap.Logger
+}
+
+func NewOIDCHandler(ssoService ssoInterface.Service, config config.OIDCConfig, logger *zap.Logger) *OIDCHandler {
+	return &OIDCHandler{
+		ssoService: ssoService,
+		config:     config,
+		logger:     logger,
+	}
+}
+
+// Providers godoc
+// @Summary List single sign-on providers
+// @Tags Authentication
+// @Produce json
+// @Success 200 {array} domain.OIDCProviderInfo
+// @Router /api/auth/oidc/providers [get]
+func (h *OIDCHandler) Providers(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": h.ssoService.Providers(),
+	})
+}
+
+// Login godoc
+// @Summary Start signing in with an identity provider
+// @Description Redirects the browser to the provider's sign-in page
+// @Tags Authentication
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} domain.APIError
+// @Router /api/auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	authURL, state, err := h.ssoService.BeginLogin(r.Context(), mux.Vars(r)["provider"])
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	// Binding the state to this browser stops an attacker from completing
+	// their own sign-in in a victim's browser.
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oidc",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.config.RedirectBaseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback godoc
+// @Summary Finish signing in with an identity provider
+// @Description Redirects to the frontend with a one-time ticket, or an error, in the URL fragment
+// @Tags Authentication
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 302
+// @Router /api/auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+	query := r.URL.Query()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     "/api/auth/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.logger.Info("Identity provider returned an error",
+			zap.String("provider", provider),
+			zap.String("error", providerErr),
+			zap.String("description", query.Get("error_description")))
+		h.redirectToFrontend(w, r, url.Values{"error": {"Sign-in was cancelled or denied"}})
+		return
+	}
+
+	state := query.Get("state")
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || state == "" || cookie.Value != state {
+		h.redirectToFrontend(w, r, url.Values{"error": {domain.ErrInvalidOIDCState.Message}})
+		return
+	}
+
+	ticket, err := h.ssoService.CompleteLogin(r.Context(), provider, state, query.Get("code"))
+	if err != nil {
+		message := "Sign-in failed"
+		var apiErr *domain.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 {
+			message = apiErr.Message
+		}
+		h.redirectToFrontend(w, r, url.Values{"error": {message}})
+		return
+	}
+
+	h.redirectToFrontend(w, r, url.Values{"ticket": {ticket}})
+}
+
+// redirectToFrontend passes values in the fragment, which browsers do not
+// send to servers or leak through the Referer header.
+func (h *OIDCHandler) redirectToFrontend(w http.ResponseWriter, r *http.Request, values url.Values) {
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	http.Redirect(w, r, h.config.FrontendCallbackURL+"#"+values.Encode(), http.StatusFound)
+}
@@ -0,0 +1,472 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	orgInterface "tech-test/backend/internal/service/interfaces/organization"
+	"tech-test/backend/internal/utils"
+)
+
+type OrganizationHandler struct {
+	orgService orgInterface.Service
+}
+
+func NewOrganizationHandler(orgService orgInterface.Service) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// List godoc
+// @Summary List your organizations
+// @Description Every organization you belong to, with your role in it.
+// @Tags Organizations
+// @Produce json
+// @Success 200 {array} domain.Organization
+// @Router /api/organizations [get]
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	orgs, err := h.orgService.List(r.Context())
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": orgs,
+	})
+}
+
+// Create godoc
+// @Summary Create an organization
+// @Description You become its owner.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param request body domain.CreateOrganizationRequest true "Organization"
+// @Success 201 {object} domain.Organization
+// @Failure 400 {object} domain.APIError
+// @Router /api/organizations [post]
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+		return
+	}
+
+	org, err := h.orgService.Create(r.Context(), req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, org)
+}
+
+// Get godoc
+// @Summary Get an organization
+// @Tags Organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {object} domain.Organization
+// @Failure 404 {object} domain.APIError
+// @Router /api/organizations/{id} [get]
+func (h *OrganizationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	org, err := h.orgService.Get(r.Context(), id)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, org)
+}
+
+// Update godoc
+// @Summary Rename an organization
+// @Description Owners and admins only.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param request body domain.UpdateOrganizationRequest true "Organization"
+// @Success 200 {object} domain.Organization
+// @Failure 403 {object} domain.APIError
+// @Router /api/organizations/{id} [put]
+func (h *OrganizationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+		return
+	}
+
+	org, err := h.orgService.Update(r.Context(), id, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, org)
+}
+
+// Delete godoc
+// @Summary Delete an organization
+// @Description Owners only. The organization's files must be deleted first.
+// @Tags Organizations
+// @Param id path int true "Organization ID"
+// @Success 200 {object} map[string]string
+// @Failure 409 {object} domain.APIError
+// @Router /api/organizations/{id} [delete]
+func (h *OrganizationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.Delete(r.Context(), id); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Organization deleted",
+	})
+}
+
+// Usage godoc
+// @Summary Get an organization's storage usage
+// @Description The number and total size of its files, and its quota in bytes (0 when unlimited).
+// @Tags Organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {object} domain.StorageUsage
+// @Router /api/organizations/{id}/usage [get]
+func (h *OrganizationHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := h.orgService.GetStorageUsage(r.Context(), id)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, usage)
+}
+
+// ListMembers godoc
+// @Summary List an organization's members
+// @Tags Organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {array} domain.OrganizationMember
+// @Router /api/organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.orgService.ListMembers(r.Context(), id)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": members,
+	})
+}
+
+// InviteMember godoc
+// @Summary Invite a member
+// @Description Invites an email address to join, as a member unless another role is given. The response is the same whether or not the address has an account, and nobody joins until they accept. Inviting the same address again replaces the earlier invitation. Owners and admins only; only owners can invite owners.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param request body domain.InviteMemberRequest true "Invitation"
+// @Success 202 {object} domain.OrganizationInvitation
+// @Failure 403 {object} domain.APIError
+// @Failure 409 {object} domain.APIError
+// @Router /api/organizations/{id}/invitations [post]
+func (h *OrganizationHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.InviteMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+		return
+	}
+
+	invitation, err := h.orgService.InviteMember(r.Context(), id, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusAccepted, invitation)
+}
+
+// ListInvitations godoc
+// @Summary List an organization's pending invitations
+// @Description Owners and admins only.
+// @Tags Organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {array} domain.OrganizationInvitation
+// @Router /api/organizations/{id}/invitations [get]
+func (h *OrganizationHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.orgService.ListInvitations(r.Context(), id)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": invitations,
+	})
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke an invitation
+// @Description Owners and admins only.
+// @Tags Organizations
+// @Param id path int true "Organization ID"
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/organizations/{id}/invitations/{invitationId} [delete]
+func (h *OrganizationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	invitationID, ok := orgInvitationID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.RevokeInvitation(r.Context(), id, invitationID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Invitation revoked",
+	})
+}
+
+// ListMyInvitations godoc
+// @Summary List your invitations to organizations
+// @Description Unexpired invitations sent to your email address, which must be verified.
+// @Tags Organizations
+// @Produce json
+// @Success 200 {array} domain.OrganizationInvitation
+// @Failure 403 {object} domain.APIError
+// @Router /api/organizations/invitations [get]
+func (h *OrganizationHandler) ListMyInvitations(w http.ResponseWriter, r *http.Request) {
+	invitations, err := h.orgService.ListMyInvitations(r.Context())
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": invitations,
+	})
+}
+
+// AcceptInvitation godoc
+// @Summary Accept an invitation
+// @Description Joins the organization with the role the invitation offers. Your email address must be verified.
+// @Tags Organizations
+// @Produce json
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} domain.Organization
+// @Failure 404 {object} domain.APIError
+// @Failure 409 {object} domain.APIError
+// @Router /api/organizations/invitations/{invitationId}/accept [post]
+func (h *OrganizationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationID, ok := orgInvitationID(w, r)
+	if !ok {
+		return
+	}
+
+	org, err := h.orgService.AcceptInvitation(r.Context(), invitationID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, org)
+}
+
+// DeclineInvitation godoc
+// @Summary Decline an invitation
+// @Tags Organizations
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/organizations/invitations/{invitationId} [delete]
+func (h *OrganizationHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationID, ok := orgInvitationID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.DeclineInvitation(r.Context(), invitationID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Invitation declined",
+	})
+}
+
+// UpdateMemberRole godoc
+// @Summary Change a member's role
+// @Description Owners and admins only; only owners can promote to or demote from owner.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Param request body domain.UpdateMemberRoleRequest true "Role"
+// @Success 200 {object} map[string]string
+// @Failure 409 {object} domain.APIError
+// @Router /api/organizations/{id}/members/{userId} [put]
+func (h *OrganizationHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := memberUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+		return
+	}
+
+	if err := h.orgService.UpdateMemberRole(r.Context(), id, userID, req.Role); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Member role updated",
+	})
+}
+
+// RemoveMember godoc
+// @Summary Remove a member
+// @Description Owners and admins can remove members, and anyone can remove themselves to leave. The last owner cannot leave.
+// @Tags Organizations
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 409 {object} domain.APIError
+// @Router /api/organizations/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := memberUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.RemoveMember(r.Context(), id, userID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Member removed",
+	})
+}
+
+// SetStorageQuota godoc
+// @Summary Set an organization's storage quota
+// @Description Uploads that would take the organization past the quota are rejected. Zero removes the limit. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param request body domain.SetStorageQuotaRequest true "Quota in bytes"
+// @Success 200 {object} map[string]string
+// @Router /api/admin/organizations/{id}/quota [put]
+func (h *OrganizationHandler) SetStorageQuota(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.SetStorageQuotaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+		return
+	}
+
+	if err := h.orgService.SetStorageQuota(r.Context(), id, req.Quota); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Storage quota updated",
+	})
+}
+
+func organizationID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid organization ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func memberUserID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["userId"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid user ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func orgInvitationID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["invitationId"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid invitation ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"tech-test/backend/internal/domain"
+	passwordResetInterface "tech-test/backend/internal/service/interfaces/passwordreset"
+	"tech-test/backend/internal/utils"
+)
+
+type PasswordResetHandler struct {
+	passwordResetService passwordResetInterface.Service
+}
+
+func NewPasswordResetHandler(passwordResetService passwordResetInterface.Service) *PasswordResetHandler {
+	return &PasswordResetHandler{passwordResetService: passwordResetService}
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset email
+// @Description Always answers 202 so callers cannot tell whether the address is registered
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.ForgotPasswordRequest true "Account email"
+// @Success 202 {object} map[string]string
+// @Failure 503 {object} domain.APIError
+// @Router /api/password/forgot [post]
+func (h *PasswordResetHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req domain.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	if err := h.passwordResetService.RequestReset(r.Context(), req.Email); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusAccepted, map[string]string{
+		"message": "If an account exists for that email, a reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Set a new password with a reset token
+// @Description Consumes the token and signs the user out everywhere
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body domain.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} domain.APIError
+// @Router /api/password/reset [post]
+func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req domain.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Password has been reset",
+	})
+}
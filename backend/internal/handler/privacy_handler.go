@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	privacyInterface "tech-test/backend/internal/service/interfaces/privacy"
+	"tech-test/backend/internal/utils"
+)
+
+type PrivacyHandler struct {
+	privacyService privacyInterface.Service
+	logger         *zap.Logger
+}
+
+func NewPrivacyHandler(privacyService privacyInterface.Service, logger *zap.Logger) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+		logger:         logger,
+	}
+}
+
+// Export godoc
+// @Summary Export your data
+// @Description Streams a ZIP archive with your profile (profile.json), file metadata (files.json), share links and recipients (shares.json), sessions, access tokens and login history (access.json), organization memberships (organizations.json), and the contents of every file you own under files/.
+// @Tags Privacy
+// @Produce application/zip
+// @Success 200 {file} file
+// @Router /api/users/me/export [get]
+func (h *PrivacyHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(uint)
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	export, err := h.privacyService.Export(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	// An account's files can take longer to send than the server's write
+	// timeout allows.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to lift write deadline for export", zap.Error(err))
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"export-%s.zip\"", export.ExportedAt.Format("2006-01-02")))
+
+	archive := zip.NewWriter(w)
+	defer archive.Close()
+
+	documents := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"files.json", export.Files},
+		{"shares.json", map[string]interface{}{
+			"recipients":     export.ShareRecipients,
+			"collections":    export.Collections,
+			"uploadRequests": export.UploadRequests,
+		}},
+		{"access.json", map[string]interface{}{
+			"sessions":      export.Sessions,
+			"accessTokens":  export.AccessTokens,
+			"loginAttempts": export.LoginAttempts,
+		}},
+		{"organizations.json", export.Organizations},
+	}
+	for _, doc := range documents {
+		if err := addJSONToZip(archive, doc.name, export.ExportedAt, doc.data); err != nil {
+			h.logger.Error("Failed to write export document",
+				zap.Uint("userID", userID),
+				zap.String("name", doc.name),
+				zap.Error(err))
+			return
+		}
+	}
+
+	names := make(map[string]int)
+	for _, file := range export.Files {
+		if err := addFileToZip(archive, file, "files/"+uniqueZipName(names, file.Name)); err != nil {
+			h.logger.Error("Failed to add file to export",
+				zap.Uint("userID", userID),
+				zap.Uint("fileID", file.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+// RequestErasure godoc
+// @Summary Ask for your account to be erased
+// @Description Your account, files, share links, collections, upload requests, tokens and login history are deleted once ERASURE_GRACE_PERIOD has passed, unless you cancel first. Organizations you are the only owner of pass to their longest-standing admin or member.
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param request body domain.RequestErasureRequest false "Reason"
+// @Success 202 {object} domain.ErasureRequest
+// @Failure 409 {object} domain.APIError
+// @Router /api/users/me/erasure [post]
+func (h *PrivacyHandler) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(uint)
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+	h.requestErasure(w, r, userID)
+}
+
+// GetErasure godoc
+// @Summary Get your pending erasure request
+// @Tags Privacy
+// @Produce json
+// @Success 200 {object} domain.ErasureRequest
+// @Failure 404 {object} domain.APIError
+// @Router /api/users/me/erasure [get]
+func (h *PrivacyHandler) GetErasure(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(uint)
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	erasure, err := h.privacyService.GetErasure(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, erasure)
+}
+
+// CancelErasure godoc
+// @Summary Cancel your pending erasure request
+// @Tags Privacy
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/users/me/erasure [delete]
+func (h *PrivacyHandler) CancelErasure(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(uint)
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+	h.cancelErasure(w, r, userID)
+}
+
+// RequestUserErasure godoc
+// @Summary Erase a user's account
+// @Description Schedules the erasure as if the user had asked, or carries it out at once when immediate is set. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body domain.RequestErasureRequest false "Reason and timing"
+// @Success 202 {object} domain.ErasureRequest
+// @Failure 409 {object} domain.APIError
+// @Router /api/admin/users/{id}/erasure [post]
+func (h *PrivacyHandler) RequestUserErasure(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+	h.requestErasure(w, r, userID)
+}
+
+// CancelUserErasure godoc
+// @Summary Cancel a user's pending erasure request
+// @Tags Admin
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/users/{id}/erasure [delete]
+func (h *PrivacyHandler) CancelUserErasure(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+	h.cancelErasure(w, r, userID)
+}
+
+// ListErasures godoc
+// @Summary List erasure requests
+// @Description Newest first. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Maximum entries (default 100)"
+// @Success 200 {array} domain.ErasureRequest
+// @Router /api/admin/erasures [get]
+func (h *PrivacyHandler) ListErasures(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	erasures, err := h.privacyService.ListErasures(r.Context(), limit)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": erasures,
+	})
+}
+
+func (h *PrivacyHandler) requestErasure(w http.ResponseWriter, r *http.Request, userID uint) {
+	var req domain.RequestErasureRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondWithError(w, domain.NewInvalidInputError("Invalid request body"))
+			return
+		}
+	}
+
+	erasure, err := h.privacyService.RequestErasure(r.Context(), userID, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	status := http.StatusAccepted
+	if erasure.Status == domain.ErasureCompleted {
+		status = http.StatusOK
+	}
+	utils.RespondWithJSON(w, status, erasure)
+}
+
+func (h *PrivacyHandler) cancelErasure(w http.ResponseWriter, r *http.Request, userID uint) {
+	if err := h.privacyService.CancelErasure(r.Context(), userID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Erasure cancelled",
+	})
+}
+
+func addJSONToZip(archive *zip.Writer, name string, modified time.Time, data interface{}) error {
+	entry, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func pathUserID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Invalid user ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	tokenInterface "tech-test/backend/internal/service/interfaces/token"
+	"tech-test/backend/internal/utils"
+)
+
+type SessionHandler struct {
+	tokenService tokenInterface.Service
+}
+
+func NewSessionHandler(tokenService tokenInterface.Service) *SessionHandler {
+	return &SessionHandler{tokenService: tokenService}
+}
+
+// List godoc
+// @Summary List your active sessions
+// @Description Every device you are logged in on, with where and when it was last used. The session making the request is marked current.
+// @Tags Sessions
+// @Produce json
+// @Success 200 {array} domain.Session
+// @Router /api/users/me/sessions [get]
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	sessions, err := h.tokenService.ListSessions(r.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": sessions,
+	})
+}
+
+// Revoke godoc
+// @Summary Sign out a session
+// @Description Ends the session immediately: its access and refresh tokens stop working. Revoking the current session logs you out.
+// @Tags Sessions
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/users/me/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.tokenService.RevokeSession(r.Context(), userID, mux.Vars(r)["id"]); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Session revoked",
+	})
+}
+
+// RevokeOthers godoc
+// @Summary Sign out everywhere else
+// @Description Ends every session except the one making the request.
+// @Tags Sessions
+// @Success 200 {object} map[string]string
+// @Router /api/users/me/sessions/revoke-others [post]
+func (h *SessionHandler) RevokeOthers(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+	if claims.SessionID == "" {
+		// Without a session ID this would end the caller's session as well.
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Log in again to manage your sessions",
+			nil,
+		))
+		return
+	}
+
+	if err := h.tokenService.RevokeOtherSessions(r.Context(), claims.UserID, claims.SessionID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Other sessions revoked",
+	})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/utils"
+)
+
+//go:embed templates/shared_preview.html
+var templateFS embed.FS
+
+var sharedPreviewTemplate = template.Must(template.ParseFS(templateFS, "templates/shared_preview.html"))
+
+type sharedPreviewPage struct {
+	Preview     *domain.SharePreview
+	Description string
+	Size        string
+	DownloadURL string
+}
+
+// GetSharedInfo returns the public metadata of a shared file as JSON.
+func (h *FileHandler) GetSharedInfo(w http.ResponseWriter, r *http.Request) {
+	preview, err := h.shareService.Preview(r.Context(), mux.Vars(r)["shareId"])
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, preview)
+}
+
+// GetSharedPreview renders a landing page for a shared file, including the
+// OpenGraph and Twitter card tags chat tools use to unfurl links.
+func (h *FileHandler) GetSharedPreview(w http.ResponseWriter, r *http.Request) {
+	shareID := mux.Vars(r)["shareId"]
+
+	preview, err := h.shareService.Preview(r.Context(), shareID)
+	if err != nil {
+		apiErr := domain.WrapError(err)
+		http.Error(w, apiErr.Message, apiErr.StatusCode)
+		return
+	}
+
+	downloadURL := preview.DownloadURL
+	if token := r.URL.Query().Get("r"); token != "" {
+		downloadURL += "?r=" + url.QueryEscape(token)
+	}
+
+	page := sharedPreviewPage{
+		Preview:     preview,
+		Description: describeShare(preview),
+		Size:        formatSize(preview.Size),
+		DownloadURL: downloadURL,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; style-src 'unsafe-inline'")
+	if err := sharedPreviewTemplate.Execute(w, page); err != nil {
+		h.logger.Error("Failed to render share preview",
+			zap.String("shareId", shareID),
+			zap.Error(err))
+	}
+}
+
+func describeShare(p *domain.SharePreview) string {
+	desc := formatSize(p.Size)
+	if p.PageCount != nil {
+		desc = fmt.Sprintf("%d pages · %s", *p.PageCount, desc)
+	}
+	return fmt.Sprintf("%s · shared by %s", desc, p.OwnerName)
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
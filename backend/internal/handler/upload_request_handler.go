@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/middleware"
+	uploadRequestInterface "tech-test/backend/internal/service/interfaces/uploadrequest"
+	"tech-test/backend/internal/utils"
+)
+
+type UploadRequestHandler struct {
+	uploadRequestService uploadRequestInterface.Service
+	uploadDir            string
+	config               config.FileConfig
+	logger               *zap.Logger
+}
+
+func NewUploadRequestHandler(uploadRequestService uploadRequestInterface.Service, config config.FileConfig, logger *zap.Logger) *UploadRequestHandler {
+	absUploadDir, err := filepath.Abs(config.UploadDir)
+	if err != nil {
+		logger.Warn("Error getting absolute upload path", zap.Error(err))
+		absUploadDir = config.UploadDir
+	}
+
+	return &UploadRequestHandler{
+		uploadRequestService: uploadRequestService,
+		uploadDir:            absUploadDir,
+		config:               config,
+		logger:               logger,
+	}
+}
+
+func (h *UploadRequestHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.CreateUploadRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	uploadRequest, err := h.uploadRequestService.Create(r.Context(), userID, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"uploadRequest": uploadRequest,
+		"link":          h.publicLink(uploadRequest.Token),
+	})
+}
+
+func (h *UploadRequestHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	uploadRequests, err := h.uploadRequestService.ListByUser(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data": uploadRequests,
+	})
+}
+
+func (h *UploadRequestHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid upload request ID",
+			err,
+		))
+		return
+	}
+
+	if err := h.uploadRequestService.Delete(r.Context(), uint(id)); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Upload request deleted successfully",
+	})
+}
+
+// GetPublic describes an open upload request to an unauthenticated visitor.
+func (h *UploadRequestHandler) GetPublic(w http.ResponseWriter, r *http.Request) {
+	uploadRequest, err := h.uploadRequestService.GetOpen(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, uploadRequest.ToInfo())
+}
+
+// maxSubmissionField bounds the text fields of a public submission.
+const maxSubmissionField = 1 << 10
+
+// SubmitPublic accepts a file from an unauthenticated visitor. The optional
+// password, submitter name and email are sent as form fields before "file".
+// The form is read as it arrives, and the file is only written to disk once
+// the password has been checked and its type is one the request accepts.
+func (h *UploadRequestHandler) SubmitPublic(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["token"]
+
+	uploadRequest, err := h.uploadRequestService.GetOpen(r.Context(), token)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	// Leave room for the multipart envelope and the text fields.
+	r.Body = http.MaxBytesReader(w, r.Body, uploadRequest.MaxSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("Expected a multipart form"))
+		return
+	}
+
+	fields := make(map[string]string)
+	var fileRecord *domain.File
+	for fileRecord == nil {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			utils.RespondWithError(w, domain.NewAPIError(
+				http.StatusBadRequest,
+				domain.ErrCodeInvalidInput,
+				"Invalid multipart form",
+				err,
+			))
+			return
+		}
+
+		if part.FormName() != "file" {
+			value, err := io.ReadAll(io.LimitReader(part, maxSubmissionField))
+			if err != nil {
+				utils.RespondWithError(w, domain.NewInvalidInputError("Invalid form field"))
+				return
+			}
+			fields[part.FormName()] = string(value)
+			continue
+		}
+
+		if _, err := h.uploadRequestService.Authorize(r.Context(), token, fields["password"]); err != nil {
+			utils.RespondWithError(w, domain.WrapError(err))
+			return
+		}
+		// The submitter is anonymous, so the type comes from the content
+		// rather than the header they chose to send.
+		content, mimeType, err := sniffContentType(part)
+		if err != nil {
+			utils.RespondWithError(w, domain.NewInvalidInputError("Failed to read file"))
+			return
+		}
+		if err := uploadRequest.Allows(mimeType, 0); err != nil {
+			utils.RespondWithError(w, domain.WrapError(err))
+			return
+		}
+
+		path, size, apiErr := saveUpload(content, part.FileName(), h.uploadDir, uploadRequest.MaxSize)
+		if apiErr != nil {
+			utils.RespondWithError(w, apiErr)
+			return
+		}
+		fileRecord = &domain.File{
+			Name:     part.FileName(),
+			MimeType: mimeType,
+			Size:     size,
+			Path:     path,
+		}
+	}
+	if fileRecord == nil {
+		utils.RespondWithError(w, domain.NewInvalidInputError("No file provided"))
+		return
+	}
+	fileRecord.SubmitterName = fields["name"]
+	fileRecord.SubmitterEmail = fields["email"]
+
+	if err := h.uploadRequestService.Submit(r.Context(), token, fields["password"], fileRecord); err != nil {
+		os.Remove(fileRecord.Path)
+		h.logger.Warn("Rejected external submission",
+			zap.String("token", token),
+			zap.Error(err))
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, map[string]string{
+		"message": "File submitted successfully",
+	})
+}
+
+// sniffContentType detects the media type from the first 512 bytes of r,
+// without parameters, and returns a reader that still yields all of r.
+func sniffContentType(r io.Reader) (io.Reader, string, error) {
+	buffered := bufio.NewReaderSize(r, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, "", err
+	}
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return nil, "", err
+	}
+	return buffered, mimeType, nil
+}
+
+func (h *UploadRequestHandler) publicLink(token string) string {
+	return h.config.BaseURL + "/upload-requests/" + token
+}
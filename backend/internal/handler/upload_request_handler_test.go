@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSniffContentType(t *testing.T) {
+	pdf := append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte{0}, 1024)...)
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"pdf", pdf, "application/pdf"},
+		{"plain text", []byte("hello"), "text/plain"},
+		{"html", []byte("<!DOCTYPE html><html></html>"), "text/html"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{"empty", nil, "text/plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mimeType, err := sniffContentType(bytes.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mimeType != tt.want {
+				t.Errorf("got %q, want %q", mimeType, tt.want)
+			}
+			rest, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(rest, tt.content) {
+				t.Errorf("reader returned %d bytes, want all %d", len(rest), len(tt.content))
+			}
+		})
+	}
+}
+
+func TestSniffContentTypeReadError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("%PDF"), errReader{})
+	if _, _, err := sniffContentType(r); err == nil {
+		t.Fatal("expected the read error")
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, io.ErrUnexpectedEOF }
@@ -2,22 +2,32 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"tech-test/backend/internal/domain"
+	emailVerificationInterface "tech-test/backend/internal/service/interfaces/emailverification"
+	privacyInterface "tech-test/backend/internal/service/interfaces/privacy"
 	userInterface "tech-test/backend/internal/service/interfaces/user"
 	"tech-test/backend/internal/utils"
 	"github.com/gorilla/mux"
 	"strconv"
+	"strings"
 	"tech-test/backend/internal/middleware"
 )
 
 type UserHandler struct {
-	userService userInterface.UserService
+	userService              userInterface.UserService
+	privacyService           privacyInterface.Service
+	emailVerificationService emailVerificationInterface.Service
 }
 
-func NewUserHandler(userService userInterface.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService userInterface.UserService, privacyService privacyInterface.Service, emailVerificationService emailVerificationInterface.Service) *UserHandler {
+	return &UserHandler{
+		userService:              userService,
+		privacyService:           privacyService,
+		emailVerificationService: emailVerificationService,
+	}
 }
 
 // CreateUser godoc
@@ -42,6 +52,11 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.userService.CheckPassword(user.Password, user.Email); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
 	if err := h.userService.Register(r.Context(), &user); err != nil {
 		utils.RespondWithError(w, domain.NewAPIError(
 			http.StatusBadRequest,
@@ -108,7 +123,18 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	existing, err := h.userService.GetUserByID(r.Context(), uint(userID))
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
 	if err := h.userService.UpdateUser(r.Context(), uint(userID), &user); err != nil {
+		var apiErr *domain.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			utils.RespondWithError(w, apiErr)
+			return
+		}
 		utils.RespondWithError(w, domain.NewAPIError(
 			http.StatusInternalServerError,
 			domain.ErrCodeInternal,
@@ -118,6 +144,18 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The new address is unverified until its owner follows the link; a
+	// failed email can be resent later.
+	if user.Email != "" && !strings.EqualFold(user.Email, existing.Email) {
+		updated, err := h.userService.GetUserByID(r.Context(), uint(userID))
+		if err == nil {
+			err = h.emailVerificationService.SendVerification(r.Context(), updated)
+		}
+		if err != nil {
+			log.Printf("Error sending verification email to user %d: %v", userID, err)
+		}
+	}
+
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "User updated successfully"})
 }
 
@@ -144,7 +182,25 @@ func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    utils.RespondWithJSON(w, http.StatusOK, user)
+    claims, ok := middleware.GetClaimsFromContext(r.Context())
+    if !ok || claims.Act == nil {
+        utils.RespondWithJSON(w, http.StatusOK, user)
+        return
+    }
+
+    // Clients flag the session while an administrator is acting as the user.
+    utils.RespondWithJSON(w, http.StatusOK, struct {
+        *domain.User
+        ImpersonatedBy *domain.Impersonator `json:"impersonatedBy"`
+    }{
+        User: user,
+        ImpersonatedBy: &domain.Impersonator{
+            AdminID:          claims.Act.UserID,
+            AdminEmail:       claims.Act.Email,
+            ExpiresAt:        claims.ExpiresAt.Time,
+            AllowDestructive: claims.Act.AllowDestructive,
+        },
+    })
 }
 
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
@@ -160,13 +216,13 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.userService.DeleteUser(r.Context(), uint(userID)); err != nil {
-		utils.RespondWithError(w, domain.NewAPIError(
-			http.StatusInternalServerError,
-			domain.ErrCodeInternal,
-			"Failed to delete user",
-			err,
-		))
+	// Deleting an account erases everything it owns along with it.
+	_, err = h.privacyService.RequestErasure(r.Context(), uint(userID), domain.RequestErasureRequest{
+		Reason:    "Deleted by an admin",
+		Immediate: true,
+	})
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
 		return
 	}
 
@@ -188,7 +244,7 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	log.Printf("Attempting to register user: %+v", user)
+	log.Printf("Attempting to register user")
 
 	if err := h.userService.Register(r.Context(), &user); err != nil {
 		log.Printf("Error registering user: %v", err)
@@ -241,6 +297,304 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateRole godoc
+// @Summary Change a user's role
+// @Description Promote a user to admin or demote them to member. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body domain.UpdateRoleRequest true "New role"
+// @Success 200 {object} domain.User
+// @Failure 400 {object} domain.APIError
+// @Failure 409 {object} domain.APIError "Cannot remove the last admin"
+// @Router /api/admin/users/{id}/role [put]
+func (h *UserHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid user ID",
+			err,
+		))
+		return
+	}
+
+	var req domain.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	user, err := h.userService.SetRole(r.Context(), uint(userID), req.Role)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	user.Password = ""
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
+
+// DisableUser godoc
+// @Summary Disable a user's account
+// @Description Stop the user from logging in and sign them out everywhere. Tokens they already hold, including personal access tokens, are refused until the account is enabled again. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body domain.DisableUserRequest false "Reason"
+// @Success 200 {object} domain.User
+// @Failure 404 {object} domain.APIError
+// @Failure 409 {object} domain.APIError "Cannot disable yourself or the last admin"
+// @Router /api/admin/users/{id}/disable [post]
+func (h *UserHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.DisableUserRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondWithError(w, domain.NewAPIError(
+				http.StatusBadRequest,
+				domain.ErrCodeInvalidInput,
+				"Invalid request body",
+				err,
+			))
+			return
+		}
+	}
+
+	user, err := h.userService.Disable(r.Context(), userID, req.Reason)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	user.Password = ""
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
+
+// EnableUser godoc
+// @Summary Enable a disabled account
+// @Description Let the user log in again. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.User
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/users/{id}/enable [post]
+func (h *UserHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.Enable(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	user.Password = ""
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
+
+// VerifyEmail godoc
+// @Summary Mark a user's email as verified
+// @Description Verify the user's current address without a link, for deployments that cannot send email. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.User
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/users/{id}/verify-email [post]
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.VerifyEmail(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	user.Password = ""
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
+
+// RequirePasswordChange godoc
+// @Summary Force a password change at next login
+// @Description Sign the user out everywhere. Their next password login returns a reset token instead of a session. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/users/{id}/require-password-change [post]
+func (h *UserHandler) RequirePasswordChange(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.RequirePasswordChange(r.Context(), userID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Password change required at next login"})
+}
+
+// SetTemporaryPassword godoc
+// @Summary Set a temporary password
+// @Description Replace the user's password with one they must change at their next login, and sign them out everywhere. One is generated when none is given. The password is returned once so it can be passed on. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body domain.TemporaryPasswordRequest false "Password to set"
+// @Success 200 {object} domain.TemporaryPassword
+// @Failure 400 {object} domain.APIError
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/users/{id}/temporary-password [post]
+func (h *UserHandler) SetTemporaryPassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.TemporaryPasswordRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondWithError(w, domain.NewAPIError(
+				http.StatusBadRequest,
+				domain.ErrCodeInvalidInput,
+				"Invalid request body",
+				err,
+			))
+			return
+		}
+	}
+
+	password, err := h.userService.SetTemporaryPassword(r.Context(), userID, req.Password)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, domain.TemporaryPassword{Password: password})
+}
+
+// GetAccountStatus godoc
+// @Summary Show a user's account status
+// @Description Whether the account is disabled or must change its password, and when and from where it last logged in. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.AccountStatus
+// @Failure 404 {object} domain.APIError
+// @Router /api/admin/users/{id}/account [get]
+func (h *UserHandler) GetAccountStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.userService.AccountStatus(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, status)
+}
+
+// UpdateProfile godoc
+// @Summary Update your profile
+// @Description Change your first name, surname or date of birth. Fields left out are kept.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body domain.UpdateProfileRequest true "Profile fields"
+// @Success 200 {object} domain.User
+// @Failure 400 {object} domain.APIError
+// @Router /api/users/me [patch]
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(uint)
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(r.Context(), userID, req)
+	if err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	user.Password = ""
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
+
+// ChangePassword godoc
+// @Summary Change your password
+// @Description Requires the current password. Every other session is signed out; the one making the request stays signed in.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body domain.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} domain.APIError
+// @Failure 403 {object} domain.APIError "Current password is incorrect"
+// @Router /api/users/me/password [post]
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, domain.NewAPIError(
+			http.StatusBadRequest,
+			domain.ErrCodeInvalidInput,
+			"Invalid request body",
+			err,
+		))
+		return
+	}
+
+	if err := h.userService.ChangePassword(r.Context(), claims.UserID, req.CurrentPassword, req.NewPassword, claims.SessionID); err != nil {
+		utils.RespondWithError(w, domain.WrapError(err))
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Password changed"})
+}
+
 // GetAllUsers godoc
 // @Summary Get all users
 // @Description Get a list of all users in the system
@@ -0,0 +1,218 @@
+// Package jwtkeys holds the keys this app signs and verifies its access
+// tokens with, and publishes the public ones as a JWKS so other services
+// can verify the tokens without sharing a secret.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+	"tech-test/backend/internal/config"
+)
+
+const (
+	generatedRSABits = 2048
+	minRSABits       = 2048
+)
+
+// Keyring signs new tokens with one key and accepts tokens signed by any of
+// its verification keys, so keys can be rotated without signing anyone out.
+type Keyring struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	kid        string
+	keys       map[string]verificationKey
+	issuer     string
+	audience   string
+	ephemeral  bool
+}
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// Load builds the keyring described by cfg. With no signing key and no
+// asymmetric algorithm it falls back to HS256 with cfg.Secret, in which
+// case nothing is published.
+func Load(cfg config.JWTConfig) (*Keyring, error) {
+	k := &Keyring{
+		keys:     make(map[string]verificationKey),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	if cfg.SigningKeyFile == "" && (cfg.Algorithm == "" || cfg.Algorithm == config.JWTAlgorithmHS256) {
+		if len(cfg.VerificationKeyFiles) > 0 {
+			return nil, errors.New("JWT verification keys need a signing key")
+		}
+		if cfg.Secret == "" {
+			return nil, errors.New("JWT secret is empty")
+		}
+		k.method = jwt.SigningMethodHS256
+		k.signingKey = []byte(cfg.Secret)
+		return k, nil
+	}
+
+	var signer crypto.Signer
+	var err error
+	if cfg.SigningKeyFile != "" {
+		signer, err = readPrivateKey(cfg.SigningKeyFile)
+	} else {
+		signer, err = generateKey(cfg.Algorithm)
+		k.ephemeral = true
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	k.method, err = methodFor(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Algorithm != "" && cfg.Algorithm != k.method.Alg() {
+		return nil, fmt.Errorf("JWT signing key is for %s, not %s", k.method.Alg(), cfg.Algorithm)
+	}
+	k.signingKey = signer
+	if k.kid, err = k.add(signer.Public()); err != nil {
+		return nil, err
+	}
+
+	for _, path := range cfg.VerificationKeyFiles {
+		public, err := readPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := k.add(public); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return k, nil
+}
+
+func (k *Keyring) add(public crypto.PublicKey) (string, error) {
+	method, err := methodFor(public)
+	if err != nil {
+		return "", err
+	}
+	kid, err := Thumbprint(public)
+	if err != nil {
+		return "", err
+	}
+	k.keys[kid] = verificationKey{method: method, public: public}
+	return kid, nil
+}
+
+// Sign signs claims with the current key, naming it in the kid header.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.kid != "" {
+		token.Header["kid"] = k.kid
+	}
+	return token.SignedString(k.signingKey)
+}
+
+// Keyfunc resolves the key a token was signed with. Asymmetric tokens must
+// name a known key and use that key's algorithm, so a public key can never
+// be used as an HMAC secret.
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if k.kid == "" {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return k.signingKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.public, nil
+}
+
+// Methods lists the algorithms tokens may be signed with.
+func (k *Keyring) Methods() []string {
+	seen := make(map[string]bool)
+	methods := []string{k.method.Alg()}
+	seen[k.method.Alg()] = true
+	for _, key := range k.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS returns the public verification keys, the signing key first.
+func (k *Keyring) JWKS() JSONWebKeySet {
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		if kid != k.kid {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	if k.kid != "" {
+		kids = append([]string{k.kid}, kids...)
+	}
+
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(kids))}
+	for _, kid := range kids {
+		key := k.keys[kid]
+		jwk, err := toJWK(key.public)
+		if err != nil {
+			continue
+		}
+		jwk.Kid = kid
+		jwk.Use = "sig"
+		jwk.Alg = key.method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func (k *Keyring) Issuer() string    { return k.issuer }
+func (k *Keyring) Audience() string  { return k.audience }
+func (k *Keyring) Algorithm() string { return k.method.Alg() }
+
+// KeyID is the kid of the signing key, empty for HS256.
+func (k *Keyring) KeyID() string { return k.kid }
+
+// Ephemeral reports whether the signing key was generated at startup, so
+// tokens will stop validating when the process restarts.
+func (k *Keyring) Ephemeral() bool { return k.ephemeral }
+
+func methodFor(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+}
+
+func generateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case config.JWTAlgorithmRS256:
+		return rsa.GenerateKey(rand.Reader, generatedRSABits)
+	case config.JWTAlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+	return nil, fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+}
@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// LogMailer writes outgoing mail to the application log instead of sending
+// it. It is meant for development, where links in the message (password
+// resets, share notifications) can be copied from the console.
+type LogMailer struct {
+	logger *zap.Logger
+}
+
+func NewLogMailer(logger *zap.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.logger.Info("Outgoing mail",
+		zap.String("to", sanitizeHeader(msg.To)),
+		zap.String("subject", sanitizeHeader(msg.Subject)),
+		zap.String("body", msg.Body))
+	return nil
+}
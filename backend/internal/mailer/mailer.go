@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+)
+
+// Message is a plain-text email addressed to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email. Implementations must be safe for
+// concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// sanitizeHeader strips line breaks so user-provided values cannot inject
+// additional headers.
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,176 @@
+// Package mailertest provides an in-process SMTP server for tests that send
+// mail through mailer.SMTPMailer.
+package mailertest
+
+import (
+	"bufio"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+
+	"tech-test/backend/internal/config"
+)
+
+// Message is a mail accepted by the sink.
+type Message struct {
+	From string
+	To   []string
+	// Header and Body are parsed from the DATA section.
+	Header mail.Header
+	Body   string
+}
+
+// SMTPSink accepts mail on a local port and keeps it in memory. It speaks
+// just enough SMTP for net/smtp.SendMail without authentication or TLS.
+type SMTPSink struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []Message
+	rejected map[string]bool
+}
+
+// NewSMTPSink starts a sink that is closed when the test ends.
+func NewSMTPSink(t testing.TB) *SMTPSink {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start SMTP sink: %v", err)
+	}
+	s := &SMTPSink{listener: listener, rejected: map[string]bool{}}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+// Config returns the mail settings that deliver to the sink.
+func (s *SMTPSink) Config() config.MailConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return config.MailConfig{
+		Driver: "smtp",
+		Host:   host,
+		Port:   port,
+		From:   "noreply@example.com",
+	}
+}
+
+// Reject makes the sink refuse mail for address, as a server does for an
+// unknown mailbox.
+func (s *SMTPSink) Reject(address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejected[strings.ToLower(address)] = true
+}
+
+// Messages returns the mail accepted so far.
+func (s *SMTPSink) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+func (s *SMTPSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *SMTPSink) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		w.WriteString(line + "\r\n")
+		w.Flush()
+	}
+
+	reply("220 localhost ESMTP sink")
+	var msg Message
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(verb, "EHLO"), strings.HasPrefix(verb, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(verb, "MAIL FROM:"):
+			msg = Message{From: address(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(verb, "RCPT TO:"):
+			to := address(line[len("RCPT TO:"):])
+			if s.isRejected(to) {
+				reply("550 No such user")
+				continue
+			}
+			msg.To = append(msg.To, to)
+			reply("250 OK")
+		case verb == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			if parsed, err := mail.ReadMessage(strings.NewReader(data)); err == nil {
+				msg.Header = parsed.Header
+				var body strings.Builder
+				bufio.NewReader(parsed.Body).WriteTo(&body)
+				msg.Body = body.String()
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 OK")
+		case verb == "RSET", verb == "NOOP":
+			reply("250 OK")
+		case verb == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *SMTPSink) isRejected(address string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rejected[strings.ToLower(address)]
+}
+
+// readData reads a DATA section up to the terminating dot, undoing dot
+// stuffing.
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "." {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(trimmed, "."))
+		b.WriteString("\n")
+	}
+}
+
+func address(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if i := strings.IndexByte(arg, ' '); i >= 0 {
+		arg = arg[:i]
+	}
+	return strings.Trim(arg, "<>")
+}
@@ -0,0 +1,164 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"tech-test/backend/internal/config"
+
+	"tech-test/backend/internal/mailer/mailertest"
+)
+
+func TestSMTPMailerSend(t *testing.T) {
+	sink := mailertest.NewSMTPSink(t)
+	m := NewSMTPMailer(sink.Config())
+
+	err := m.Send(context.Background(), Message{
+		To:      "alice@example.com",
+		Subject: "Hello",
+		Body:    "First line\n.\nAfter a lone dot\n",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	messages := sink.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("sink received %d messages, want 1", len(messages))
+	}
+	msg := messages[0]
+	if msg.From != "noreply@example.com" {
+		t.Errorf("envelope sender = %q", msg.From)
+	}
+	if len(msg.To) != 1 || msg.To[0] != "alice@example.com" {
+		t.Errorf("envelope recipients = %v", msg.To)
+	}
+	for header, want := range map[string]string{
+		"From":         "noreply@example.com",
+		"To":           "alice@example.com",
+		"Subject":      "Hello",
+		"Content-Type": "text/plain; charset=UTF-8",
+	} {
+		if got := msg.Header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	if msg.Header.Get("Date") == "" {
+		t.Error("Date header missing")
+	}
+	if msg.Body != "First line\n.\nAfter a lone dot\n" {
+		t.Errorf("body = %q", msg.Body)
+	}
+}
+
+func TestSMTPMailerStripsHeaderInjection(t *testing.T) {
+	sink := mailertest.NewSMTPSink(t)
+	m := NewSMTPMailer(sink.Config())
+
+	err := m.Send(context.Background(), Message{
+		To:      "alice@example.com",
+		Subject: "Hi\r\nBcc: mallory@example.com",
+		Body:    "Body",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	msg := sink.Messages()[0]
+	if bcc := msg.Header.Get("Bcc"); bcc != "" {
+		t.Errorf("injected Bcc header %q", bcc)
+	}
+	if subject := msg.Header.Get("Subject"); strings.ContainsAny(subject, "\r\n") || !strings.HasPrefix(subject, "Hi") {
+		t.Errorf("subject = %q", subject)
+	}
+	if len(msg.To) != 1 {
+		t.Errorf("envelope recipients = %v", msg.To)
+	}
+}
+
+func TestSMTPMailerRejectedRecipient(t *testing.T) {
+	sink := mailertest.NewSMTPSink(t)
+	sink.Reject("gone@example.com")
+	m := NewSMTPMailer(sink.Config())
+
+	err := m.Send(context.Background(), Message{To: "gone@example.com", Subject: "Hi", Body: "Body"})
+	if err == nil || !strings.Contains(err.Error(), "gone@example.com") {
+		t.Fatalf("got %v, want an error naming the recipient", err)
+	}
+	if got := len(sink.Messages()); got != 0 {
+		t.Errorf("sink received %d messages, want 0", got)
+	}
+}
+
+func TestSMTPMailerCanceledContext(t *testing.T) {
+	sink := mailertest.NewSMTPSink(t)
+	m := NewSMTPMailer(sink.Config())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Send(ctx, Message{To: "alice@example.com", Subject: "Hi", Body: "Body"}); err == nil {
+		t.Fatal("Send succeeded with a canceled context")
+	}
+	if got := len(sink.Messages()); got != 0 {
+		t.Errorf("sink received %d messages, want 0", got)
+	}
+}
+
+func TestSMTPMailerUnreachableServer(t *testing.T) {
+	sink := mailertest.NewSMTPSink(t)
+	cfg := sink.Config()
+	cfg.Port = "1"
+
+	if err := NewSMTPMailer(cfg).Send(context.Background(), Message{To: "alice@example.com", Subject: "Hi", Body: "Body"}); err == nil {
+		t.Fatal("Send succeeded without a server")
+	}
+}
+
+func TestSMTPMailerStalledServer(t *testing.T) {
+	// The server accepts connections but never sends its greeting.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	m := NewSMTPMailer(config.MailConfig{Host: host, Port: port, From: "noreply@example.com"})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		if err := m.Send(ctx, Message{To: "alice@example.com", Subject: "Hi", Body: "Body"}); err == nil {
+			t.Fatal("Send succeeded against a stalled server")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Send took %v after the deadline passed", elapsed)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		if err := m.Send(ctx, Message{To: "alice@example.com", Subject: "Hi", Body: "Body"}); err == nil {
+			t.Fatal("Send succeeded against a stalled server")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Send took %v after the context was canceled", elapsed)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/utils"
+)
+
+// WithActor must run after AuthMiddleware. It records the caller and the
+// role from their token as a domain.Actor so services can authorize
+// operations on their behalf. Access tokens never carry the admin role and
+// are limited to their scopes.
+func WithActor() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserIDFromContext(r.Context())
+			if !ok {
+				utils.RespondWithError(w, domain.ErrUnauthorized)
+				return
+			}
+
+			actor := domain.Actor{
+				UserID: userID,
+				Admin:  GetRoleFromContext(r.Context()) == domain.RoleAdmin,
+			}
+			if pat, ok := r.Context().Value(AccessTokenKey).(*domain.PersonalAccessToken); ok {
+				actor.Admin = false
+				actor.TokenID = pat.ID
+				actor.Scopes = pat.ScopeList()
+			}
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithActor(r.Context(), actor)))
+		})
+	}
+}
+
+// RequireSession must run after WithActor. It rejects requests made with a
+// personal access token, for endpoints outside every token scope such as
+// managing the tokens themselves.
+func RequireSession() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if domain.ActorFromContext(r.Context()).TokenID != 0 {
+				utils.RespondWithError(w, domain.ErrInsufficientScope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAdmin must run after WithActor. It rejects every request whose
+// actor is not an administrator.
+func RequireAdmin() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := domain.ActorFromContext(r.Context())
+			if actor.UserID == 0 {
+				utils.RespondWithError(w, domain.ErrUnauthorized)
+				return
+			}
+
+			if !actor.Admin {
+				utils.RespondWithError(w, domain.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
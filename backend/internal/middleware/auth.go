@@ -6,23 +6,60 @@ import (
     "log"
     "net/http"
     "strings"
+    "time"
     
-    "github.com/golang-jwt/jwt/v4"
+    "github.com/gorilla/mux"
     "tech-test/backend/internal/domain"
+    "tech-test/backend/internal/jwtkeys"
     "tech-test/backend/internal/utils"
 )
 
 type ContextKey string
 
-const UserIDKey ContextKey = "userID"
+const (
+    UserIDKey ContextKey = "userID"
+    RoleKey   ContextKey = "role"
+    ClaimsKey ContextKey = "claims"
+    // AccessTokenKey holds the *domain.PersonalAccessToken for requests
+    // authenticated with one instead of a JWT.
+    AccessTokenKey ContextKey = "accessToken"
+)
+
+// RevocationChecker reports whether an otherwise valid token has been
+// revoked, by logout, because its session was ended, or because all of the
+// user's sessions were.
+type RevocationChecker interface {
+    IsRevoked(ctx context.Context, userID uint, sessionID, jti string, issuedAt time.Time) (bool, error)
+}
+
+// AccessTokenAuthenticator resolves a personal access token.
+type AccessTokenAuthenticator interface {
+    Authenticate(ctx context.Context, raw string) (*domain.PersonalAccessToken, error)
+}
+
+// AccountStatusChecker reports whether a user's account has been disabled
+// by an admin, or no longer exists, and whether it is still an admin's.
+type AccountStatusChecker interface {
+    IsDisabled(ctx context.Context, userID uint) (bool, error)
+    IsAdmin(ctx context.Context, userID uint) (bool, error)
+}
+
+// AuthMiddleware accepts either a JWT from /api/login, verified against
+// keys, or a personal access token as the bearer credential. Either is
+// refused once the user's account is disabled, and an impersonation token
+// also once the administrator behind it could no longer sign in as
+// themselves or has stopped being an admin.
+func AuthMiddleware(keys *jwtkeys.Keyring, revocations RevocationChecker, accessTokens AccessTokenAuthenticator, accounts AccountStatusChecker) mux.MiddlewareFunc {
+    return func(next http.Handler) http.Handler {
+        return authenticate(keys, revocations, accessTokens, accounts, next)
+    }
+}
 
-func AuthMiddleware(next http.Handler) http.Handler {
+func authenticate(keys *jwtkeys.Keyring, revocations RevocationChecker, accessTokens AccessTokenAuthenticator, accounts AccountStatusChecker, next http.Handler) http.Handler {
     return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
         log.Printf("Processing request: %s %s", r.Method, r.URL.Path)
         
         authHeader := r.Header.Get("Authorization")
-        log.Printf("Auth header: %s", authHeader)
-        
         if authHeader == "" {
             log.Printf("No auth header found")
             utils.RespondWithError(w, domain.NewAPIError(
@@ -36,7 +73,7 @@ func AuthMiddleware(next http.Handler) http.Handler {
 
         tokenParts := strings.Split(authHeader, " ")
         if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-            log.Printf("Invalid auth header format: %s", authHeader)
+            log.Printf("Invalid auth header format")
             utils.RespondWithError(w, domain.NewAPIError(
                 http.StatusUnauthorized,
                 domain.ErrCodeAuthentication,
@@ -47,7 +84,32 @@ func AuthMiddleware(next http.Handler) http.Handler {
         }
 
         token := tokenParts[1]
-        claims, err := utils.ValidateToken(token, utils.JWTSecret, jwt.SigningMethodHS256)
+        if strings.HasPrefix(token, domain.AccessTokenPrefix) {
+            pat, err := accessTokens.Authenticate(r.Context(), token)
+            if err != nil {
+                log.Printf("Access token authentication failed: %v", err)
+                utils.RespondWithError(w, domain.NewAPIError(
+                    http.StatusUnauthorized,
+                    domain.ErrCodeAuthentication,
+                    "Invalid token",
+                    err,
+                ))
+                return
+            }
+
+            if rejectDisabled(w, r, accounts, pat.UserID) {
+                return
+            }
+
+            log.Printf("Access token %d validated for user ID: %d", pat.ID, pat.UserID)
+            ctx := context.WithValue(r.Context(), UserIDKey, pat.UserID)
+            ctx = context.WithValue(ctx, RoleKey, domain.RoleMember)
+            ctx = context.WithValue(ctx, AccessTokenKey, pat)
+            next.ServeHTTP(w, r.WithContext(ctx))
+            return
+        }
+
+        claims, err := utils.ValidateToken(token, keys)
         if err != nil {
             log.Printf("Token validation failed: %v", err)
             utils.RespondWithError(w, domain.NewAPIError(
@@ -59,13 +121,108 @@ func AuthMiddleware(next http.Handler) http.Handler {
             return
         }
 
+        if claims.ID == "" || claims.IssuedAt == nil || claims.ExpiresAt == nil {
+            log.Printf("Token for user ID %d has no ID or timestamps", claims.UserID)
+            utils.RespondWithError(w, domain.NewAPIError(
+                http.StatusUnauthorized,
+                domain.ErrCodeAuthentication,
+                "Invalid token",
+                nil,
+            ))
+            return
+        }
+
+        revoked, err := revocations.IsRevoked(r.Context(), claims.UserID, claims.SessionID, claims.ID, claims.IssuedAt.Time)
+        if err != nil {
+            utils.RespondWithError(w, domain.WrapError(err))
+            return
+        }
+        if revoked {
+            log.Printf("Rejected revoked token for user ID: %d", claims.UserID)
+            utils.RespondWithError(w, domain.ErrTokenRevoked)
+            return
+        }
+
+        if rejectDisabled(w, r, accounts, claims.UserID) {
+            return
+        }
+        if claims.Act != nil && rejectImpersonator(w, r, revocations, accounts, claims) {
+            return
+        }
+
         log.Printf("Token validated successfully for user ID: %d", claims.UserID)
         ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+        ctx = context.WithValue(ctx, RoleKey, claims.Role)
+        ctx = context.WithValue(ctx, ClaimsKey, claims)
         next.ServeHTTP(w, r.WithContext(ctx))
     })
 }
 
+// rejectDisabled responds with an error and returns true when userID may not
+// make requests.
+func rejectDisabled(w http.ResponseWriter, r *http.Request, accounts AccountStatusChecker, userID uint) bool {
+    disabled, err := accounts.IsDisabled(r.Context(), userID)
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return true
+    }
+    if disabled {
+        log.Printf("Rejected token for disabled user ID: %d", userID)
+        utils.RespondWithError(w, domain.ErrAccountDisabled)
+        return true
+    }
+    return false
+}
+
+// rejectImpersonator responds with an error and returns true when the
+// administrator named by an impersonation token has since been signed out
+// everywhere, disabled or demoted, any of which ends their impersonations.
+func rejectImpersonator(w http.ResponseWriter, r *http.Request, revocations RevocationChecker, accounts AccountStatusChecker, claims *utils.Claims) bool {
+    adminID := claims.Act.UserID
+    revoked, err := revocations.IsRevoked(r.Context(), adminID, "", claims.ID, claims.IssuedAt.Time)
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return true
+    }
+    if revoked {
+        log.Printf("Rejected impersonation token of revoked admin ID: %d", adminID)
+        utils.RespondWithError(w, domain.ErrTokenRevoked)
+        return true
+    }
+
+    if rejectDisabled(w, r, accounts, adminID) {
+        return true
+    }
+
+    admin, err := accounts.IsAdmin(r.Context(), adminID)
+    if err != nil {
+        utils.RespondWithError(w, domain.WrapError(err))
+        return true
+    }
+    if !admin {
+        log.Printf("Rejected impersonation token of former admin ID: %d", adminID)
+        utils.RespondWithError(w, domain.ErrTokenRevoked)
+        return true
+    }
+    return false
+}
+
 func GetUserIDFromContext(ctx context.Context) (uint, bool) {
     userID, ok := ctx.Value(UserIDKey).(uint)
     return userID, ok
 }
+
+func GetClaimsFromContext(ctx context.Context) (*utils.Claims, bool) {
+    claims, ok := ctx.Value(ClaimsKey).(*utils.Claims)
+    return claims, ok
+}
+
+// GetRoleFromContext returns the role carried by the caller's token. Tokens
+// issued before roles existed carry none and are treated as members.
+func GetRoleFromContext(ctx context.Context) domain.Role {
+    role, _ := ctx.Value(RoleKey).(domain.Role)
+    if role == "" {
+        return domain.RoleMember
+    }
+    return role
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/jwtkeys"
+	"tech-test/backend/internal/utils"
+)
+
+const (
+	adminID uint = 1
+	userID  uint = 2
+)
+
+// accounts holds the status of every user, keyed by ID.
+type accounts struct {
+	disabled map[uint]bool
+	admins   map[uint]bool
+}
+
+func (a *accounts) IsDisabled(ctx context.Context, id uint) (bool, error) {
+	return a.disabled[id], nil
+}
+
+func (a *accounts) IsAdmin(ctx context.Context, id uint) (bool, error) {
+	return a.admins[id], nil
+}
+
+// revocations lists users signed out of every session.
+type revocations map[uint]bool
+
+func (r revocations) IsRevoked(ctx context.Context, id uint, sessionID, jti string, issuedAt time.Time) (bool, error) {
+	return r[id], nil
+}
+
+func TestAuthMiddlewareChecksImpersonator(t *testing.T) {
+	keys, err := jwtkeys.Load(config.JWTConfig{Secret: "test-secret", Issuer: "test", Audience: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := utils.GenerateImpersonationToken(keys, userID, domain.RoleMember, "", utils.ActorClaim{UserID: adminID}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		accounts *accounts
+		revoked  revocations
+		want     int
+	}{
+		{"admin in good standing", &accounts{admins: map[uint]bool{adminID: true}}, nil, http.StatusOK},
+		{"admin signed out everywhere", &accounts{admins: map[uint]bool{adminID: true}}, revocations{adminID: true}, http.StatusUnauthorized},
+		{"admin disabled", &accounts{admins: map[uint]bool{adminID: true}, disabled: map[uint]bool{adminID: true}}, nil, http.StatusForbidden},
+		{"admin demoted", &accounts{}, nil, http.StatusUnauthorized},
+		{"subject disabled", &accounts{admins: map[uint]bool{adminID: true}, disabled: map[uint]bool{userID: true}}, nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := AuthMiddleware(keys, tt.revoked, nil, tt.accounts)(ok)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/files/my", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -29,8 +30,6 @@ func ValidateRegister(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 		
-		log.Printf("Received registration request: %s", string(body))
-		
 		r.Body = io.NopCloser(bytes.NewBuffer(body))
 		
 		var req RegisterRequest
@@ -47,10 +46,6 @@ func ValidateRegister(next http.HandlerFunc) http.HandlerFunc {
 
 		errors := make(map[string]string)
 
-		if !validatePassword(req.Password) {
-			errors["password"] = "Password must be at least 8 characters and contain uppercase, lowercase, number, and special character"
-		}
-
 		if !validateEmail(req.Email) {
 			errors["email"] = "Invalid email format"
 		}
@@ -73,14 +68,122 @@ func ValidateRegister(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-func validatePassword(password string) bool {
-	hasMinLen := len(password) >= 8
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-	hasNumber := regexp.MustCompile(`[0-9]`).MatchString(password)
-	hasSpecial := regexp.MustCompile(`[!@#$%^&*(),.?":{}|<>]`).MatchString(password)
-	
-	return hasMinLen && hasUpper && hasLower && hasNumber && hasSpecial
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ValidateResetPassword checks a password reset carries a token and a
+// password. The password policy is applied by the user service, which knows
+// whose password it is.
+func ValidateResetPassword(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			return
+		}
+
+		var req ResetPasswordRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if req.Token == "" || req.Password == "" {
+			http.Error(w, "Token and password are required", http.StatusBadRequest)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewBuffer(body))
+		next.ServeHTTP(w, r)
+	}
+}
+
+type UpdateProfileRequest struct {
+	FirstName *string `json:"firstName"`
+	Surname   *string `json:"surname"`
+	DOB       *string `json:"dob"`
+}
+
+// ValidateUpdateProfile applies the registration rules to the fields a
+// profile update sets.
+func ValidateUpdateProfile(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			return
+		}
+
+		var req UpdateProfileRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if req.FirstName == nil && req.Surname == nil && req.DOB == nil {
+			http.Error(w, "No fields to update", http.StatusBadRequest)
+			return
+		}
+
+		errors := make(map[string]string)
+
+		if req.FirstName != nil && strings.TrimSpace(*req.FirstName) == "" {
+			errors["firstName"] = "First name is required"
+		}
+
+		if req.Surname != nil && strings.TrimSpace(*req.Surname) == "" {
+			errors["surname"] = "Surname is required"
+		}
+
+		if req.DOB != nil && !validateDOB(*req.DOB) {
+			errors["dob"] = "Invalid date of birth or user must be at least 13 years old"
+		}
+
+		if len(errors) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": errors,
+			})
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewBuffer(body))
+		next.ServeHTTP(w, r)
+	}
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// ValidateChangePassword checks both passwords are present. The password
+// policy is applied to the new one by the user service.
+func ValidateChangePassword(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			return
+		}
+
+		var req ChangePasswordRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if req.CurrentPassword == "" || req.NewPassword == "" {
+			http.Error(w, "Current and new password are required", http.StatusBadRequest)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewBuffer(body))
+		next.ServeHTTP(w, r)
+	}
 }
 
 func validateEmail(email string) bool {
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/utils"
+)
+
+// EmailVerificationChecker reports whether a user may act on behalf of
+// their email address.
+type EmailVerificationChecker interface {
+	IsEmailVerified(ctx context.Context, userID uint) (bool, error)
+}
+
+// RequireVerifiedEmail must run after WithActor. It rejects callers who have
+// not confirmed their email address, for endpoints that upload files or
+// send links to other people.
+func RequireVerifiedEmail(checker EmailVerificationChecker) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := domain.ActorFromContext(r.Context())
+			if actor.UserID == 0 {
+				utils.RespondWithError(w, domain.ErrUnauthorized)
+				return
+			}
+
+			verified, err := checker.IsEmailVerified(r.Context(), actor.UserID)
+			if err != nil {
+				utils.RespondWithError(w, domain.WrapError(err))
+				return
+			}
+			if !verified {
+				utils.RespondWithError(w, domain.ErrEmailNotVerified)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// signingMethods are the ID token algorithms accepted. Symmetric and "none"
+// algorithms are never accepted.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// IDTokenClaims are the validated claims of an ID token.
+type IDTokenClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string      `json:"nonce"`
+	AuthorizedParty string      `json:"azp"`
+	Email           string      `json:"email"`
+	EmailVerified   interface{} `json:"email_verified"`
+	GivenName       string      `json:"given_name"`
+	FamilyName      string      `json:"family_name"`
+	Name            string      `json:"name"`
+}
+
+// VerifyIDToken checks the token's signature against the provider's keys,
+// its issuer, audience, lifetime and nonce.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*IDTokenClaims, error) {
+	var claims idTokenClaims
+	parser := jwt.NewParser(jwt.WithValidMethods(signingMethods), jwt.WithoutClaimsValidation())
+	_, err := parser.ParseWithClaims(raw, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	now := time.Now()
+	switch {
+	case !claims.VerifyIssuer(p.cfg.Issuer, true):
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, claims.Issuer)
+	case !claims.VerifyAudience(p.cfg.ClientID, true):
+		return nil, fmt.Errorf("%w: not issued for this client", ErrInvalidIDToken)
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID:
+		return nil, fmt.Errorf("%w: unexpected authorized party", ErrInvalidIDToken)
+	case !claims.VerifyExpiresAt(now.Add(-clockSkew), true):
+		return nil, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	case !claims.VerifyIssuedAt(now.Add(clockSkew), false):
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	case subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1:
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return &IDTokenClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Name:          claims.Name,
+	}, nil
+}
+
+// isTrue accepts email_verified as a JSON boolean or, as some providers
+// send it, the string "true".
+func isTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+type keySet map[string]interface{}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// key returns the verification key with the given ID, refetching the JWKS
+// when the provider may have rotated its keys.
+func (p *Provider) key(ctx context.Context, kid string) (interface{}, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok && time.Since(p.keysFetched) < metadataTTL {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) >= minKeyRefresh {
+		if err := p.fetchKeys(ctx, metadata.JWKSURI); err != nil {
+			return nil, err
+		}
+	}
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds a key by ID. A token without a kid is accepted only when
+// the provider publishes a single key.
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return err
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	status, err := p.doJSON(req, &set)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", status)
+	}
+
+	keys := keySet{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip key types we cannot use rather than failing the set.
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("JWKS has no usable signing keys")
+	}
+
+	p.keys = keys
+	p.keysFetched = time.Now()
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
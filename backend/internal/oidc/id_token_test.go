@@ -0,0 +1,270 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"tech-test/backend/internal/oidc/oidctest"
+)
+
+func TestVerifyIDToken(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	p := NewProvider(idp.Config("test"), nil)
+	otherKey := oidctest.GenerateKey(t)
+
+	tests := []struct {
+		name  string
+		token func(claims jwt.MapClaims) string
+		nonce string
+		// wantErr is empty for tokens that must be accepted.
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			token: func(c jwt.MapClaims) string { return idp.Sign(t, c) },
+		},
+		{
+			name: "wrong issuer",
+			token: func(c jwt.MapClaims) string {
+				c["iss"] = "https://evil.example.com"
+				return idp.Sign(t, c)
+			},
+			wantErr: "unexpected issuer",
+		},
+		{
+			name: "wrong audience",
+			token: func(c jwt.MapClaims) string {
+				c["aud"] = "another-client"
+				return idp.Sign(t, c)
+			},
+			wantErr: "not issued for this client",
+		},
+		{
+			name: "several audiences without azp",
+			token: func(c jwt.MapClaims) string {
+				c["aud"] = []string{oidctest.ClientID, "another-client"}
+				return idp.Sign(t, c)
+			},
+			wantErr: "unexpected authorized party",
+		},
+		{
+			name: "several audiences with another azp",
+			token: func(c jwt.MapClaims) string {
+				c["aud"] = []string{oidctest.ClientID, "another-client"}
+				c["azp"] = "another-client"
+				return idp.Sign(t, c)
+			},
+			wantErr: "unexpected authorized party",
+		},
+		{
+			name: "several audiences with our azp",
+			token: func(c jwt.MapClaims) string {
+				c["aud"] = []string{oidctest.ClientID, "another-client"}
+				c["azp"] = oidctest.ClientID
+				return idp.Sign(t, c)
+			},
+		},
+		{
+			name:    "nonce mismatch",
+			token:   func(c jwt.MapClaims) string { return idp.Sign(t, c) },
+			nonce:   "another-nonce",
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "missing nonce",
+			token: func(c jwt.MapClaims) string {
+				delete(c, "nonce")
+				return idp.Sign(t, c)
+			},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "expired",
+			token: func(c jwt.MapClaims) string {
+				c["exp"] = time.Now().Add(-clockSkew - time.Minute).Unix()
+				return idp.Sign(t, c)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "expired within clock skew",
+			token: func(c jwt.MapClaims) string {
+				c["exp"] = time.Now().Add(-clockSkew / 2).Unix()
+				return idp.Sign(t, c)
+			},
+		},
+		{
+			name: "missing expiry",
+			token: func(c jwt.MapClaims) string {
+				delete(c, "exp")
+				return idp.Sign(t, c)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "issued in the future",
+			token: func(c jwt.MapClaims) string {
+				c["iat"] = time.Now().Add(clockSkew + time.Minute).Unix()
+				return idp.Sign(t, c)
+			},
+			wantErr: "issued in the future",
+		},
+		{
+			name: "missing subject",
+			token: func(c jwt.MapClaims) string {
+				delete(c, "sub")
+				return idp.Sign(t, c)
+			},
+			wantErr: "missing subject",
+		},
+		{
+			name:    "unknown kid",
+			token:   func(c jwt.MapClaims) string { return oidctest.SignWith(t, otherKey, "rotated-away", c) },
+			wantErr: "unknown signing key",
+		},
+		{
+			name:    "provider kid with another key",
+			token:   func(c jwt.MapClaims) string { return oidctest.SignWith(t, otherKey, oidctest.KeyID, c) },
+			wantErr: "verification error",
+		},
+		{
+			name:  "no kid with a single provider key",
+			token: func(c jwt.MapClaims) string { return oidctest.SignWith(t, otherKey, "", c) },
+			// The provider's only key is used, which did not sign it.
+			wantErr: "verification error",
+		},
+		{
+			name: "symmetric algorithm",
+			token: func(c jwt.MapClaims) string {
+				signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(oidctest.ClientID))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: "signing method HS256 is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = "nonce-1"
+			}
+			raw := tt.token(idp.Claims("alice", "nonce-1"))
+
+			claims, err := p.VerifyIDToken(context.Background(), raw, nonce)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if claims.Subject != "alice" || claims.Email != "alice@example.com" || !claims.EmailVerified {
+					t.Errorf("unexpected claims %+v", claims)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("got %v, want ErrInvalidIDToken", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %q, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenEmailVerified(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	p := NewProvider(idp.Config("test"), nil)
+
+	for value, want := range map[interface{}]bool{
+		true:    true,
+		"true":  true,
+		false:   false,
+		"false": false,
+		"yes":   false,
+	} {
+		c := idp.Claims("alice", "n")
+		c["email_verified"] = value
+		claims, err := p.VerifyIDToken(context.Background(), idp.Sign(t, c), "n")
+		if err != nil {
+			t.Fatalf("email_verified %v: %v", value, err)
+		}
+		if claims.EmailVerified != want {
+			t.Errorf("email_verified %#v: got %v, want %v", value, claims.EmailVerified, want)
+		}
+	}
+}
+
+func TestAuthCodeURLAndExchange(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	p := NewProvider(idp.Config("test"), nil)
+	ctx := context.Background()
+	redirectURI := "https://files.example.com/api/auth/oidc/test/callback"
+
+	verifier, err := RandomString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := p.AuthCodeURL(ctx, redirectURI, "state-1", "nonce-1", CodeChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", authURL, err)
+	}
+	query := parsed.Query()
+	for key, want := range map[string]string{
+		"response_type":         "code",
+		"client_id":             oidctest.ClientID,
+		"redirect_uri":          redirectURI,
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge_method": "S256",
+		"scope":                 "openid email profile",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if !strings.HasPrefix(authURL, idp.Issuer()+"/authorize?") {
+		t.Errorf("authorization URL %q does not use the discovered endpoint", authURL)
+	}
+
+	idToken := idp.Sign(t, idp.Claims("alice", "nonce-1"))
+	code := idp.Authorize(query.Get("code_challenge"), idToken)
+
+	if _, err := p.Exchange(ctx, redirectURI, code, "wrong-verifier"); err == nil {
+		t.Fatal("Exchange accepted the wrong PKCE verifier")
+	}
+
+	code = idp.Authorize(query.Get("code_challenge"), idToken)
+	got, err := p.Exchange(ctx, redirectURI, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if got != idToken {
+		t.Error("Exchange returned a different ID token")
+	}
+	if _, err := p.Exchange(ctx, redirectURI, code, verifier); err == nil {
+		t.Error("Exchange redeemed a code twice")
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	cfg := idp.Config("test")
+	cfg.Issuer = strings.Replace(cfg.Issuer, "127.0.0.1", "localhost", 1)
+
+	_, err := NewProvider(cfg, nil).AuthCodeURL(context.Background(), "https://files.example.com/cb", "s", "n", "c")
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("got %v, want an issuer mismatch", err)
+	}
+}
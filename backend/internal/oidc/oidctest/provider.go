@@ -0,0 +1,194 @@
+// Package oidctest provides a mock OpenID Connect provider for tests: a
+// local server with discovery, JWKS and token endpoints, and helpers to
+// sign ID tokens with its key.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"tech-test/backend/internal/config"
+)
+
+const (
+	ClientID = "test-client"
+	KeyID    = "test-key"
+)
+
+// Provider is a running mock identity provider.
+type Provider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// grant is an authorization code waiting to be redeemed.
+type grant struct {
+	challenge string
+	idToken   string
+}
+
+// NewProvider starts a provider that is shut down when the test ends.
+func NewProvider(t testing.TB) *Provider {
+	t.Helper()
+
+	p := &Provider{
+		key:    GenerateKey(t),
+		grants: map[string]grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// GenerateKey returns a new RSA signing key, for tokens the provider did
+// not sign.
+func GenerateKey(t testing.TB) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func (p *Provider) Issuer() string {
+	return p.server.URL
+}
+
+// Config returns the settings for a relying party registered as ClientID.
+func (p *Provider) Config(name string) config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Name:        name,
+		DisplayName: "Test IdP",
+		Issuer:      p.Issuer(),
+		ClientID:    ClientID,
+		Scopes:      []string{"openid", "email", "profile"},
+	}
+}
+
+// Claims returns valid ID token claims for subject and nonce, which tests
+// can adjust before signing.
+func (p *Provider) Claims(subject, nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            p.Issuer(),
+		"aud":            ClientID,
+		"sub":            subject,
+		"nonce":          nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"email":          subject + "@example.com",
+		"email_verified": true,
+	}
+}
+
+// Sign signs claims with the provider's key.
+func (p *Provider) Sign(t testing.TB, claims jwt.MapClaims) string {
+	t.Helper()
+	return SignWith(t, p.key, KeyID, claims)
+}
+
+// SignWith signs claims with key, naming kid in the header unless it is
+// empty.
+func SignWith(t testing.TB, key crypto.Signer, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign ID token: %v", err)
+	}
+	return signed
+}
+
+// Authorize stands in for the user signing in: it returns a code that the
+// token endpoint redeems for idToken, given the verifier for challenge.
+func (p *Provider) Authorize(challenge, idToken string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	code := base64.RawURLEncoding.EncodeToString(b)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.grants[code] = grant{challenge: challenge, idToken: idToken}
+	return code
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.Issuer(),
+		"authorization_endpoint": p.Issuer() + "/authorize",
+		"token_endpoint":         p.Issuer() + "/token",
+		"jwks_uri":               p.Issuer() + "/jwks",
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	public := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("client_id") != ClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	g, ok := p.grants[r.PostForm.Get("code")]
+	delete(p.grants, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     g.idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
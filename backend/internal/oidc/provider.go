@@ -0,0 +1,188 @@
+// Package oidc is a minimal OpenID Connect relying party: discovery, the
+// authorization code flow with PKCE, and ID token validation against the
+// provider's JWKS.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"tech-test/backend/internal/config"
+)
+
+const (
+	// metadataTTL is how long discovery documents and keys are cached.
+	metadataTTL = time.Hour
+	// minKeyRefresh limits refetching the JWKS when a token names an
+	// unknown key, so forged kids cannot hammer the provider.
+	minKeyRefresh = time.Minute
+	// clockSkew is tolerated between this server and the provider.
+	clockSkew = time.Minute
+	// maxResponseSize bounds what is read from the provider.
+	maxResponseSize = 1 << 20
+)
+
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// Metadata is the subset of the discovery document this package uses.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider talks to one OpenID Connect identity provider. It is safe for
+// concurrent use.
+type Provider struct {
+	cfg    config.OIDCProviderConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	metadata    *Metadata
+	fetchedAt   time.Time
+	keys        keySet
+	keysFetched time.Time
+}
+
+func NewProvider(cfg config.OIDCProviderConfig, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *Provider) DisplayName() string {
+	return p.cfg.DisplayName
+}
+
+// AuthCodeURL returns where to send the user to sign in.
+func (p *Provider) AuthCodeURL(ctx context.Context, redirectURI, state, nonce, codeChallenge string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the raw ID token.
+func (p *Provider) Exchange(ctx context.Context, redirectURI, code, codeVerifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {codeVerifier},
+	}
+	if p.cfg.ClientSecret == "" {
+		// Public clients identify themselves in the body.
+		form.Set("client_id", p.cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.doJSON(req, &body)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("token request failed with status %d: %s %s", status, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+	return body.IDToken, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil && time.Since(p.fetchedAt) < metadataTTL {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var metadata Metadata
+	status, err := p.doJSON(req, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("discovery failed with status %d", status)
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", metadata.Issuer, p.cfg.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing endpoints")
+	}
+
+	p.metadata = &metadata
+	p.fetchedAt = time.Now()
+	return p.metadata, nil
+}
+
+func (p *Provider) doJSON(req *http.Request, out interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(data, out); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("invalid JSON response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,129 @@
+// Package passwordhash hashes passwords with argon2id and verifies both its
+// own hashes and the bcrypt hashes stored before it.
+//
+// Hashes use the PHC string format, which records the algorithm, its
+// version and its parameters alongside the salt:
+//
+//	$argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>
+//
+// so parameters can be changed without breaking existing hashes. Verify
+// reports when a hash was made with anything other than the current
+// settings, and callers holding the plain password rehash it then.
+package passwordhash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	saltLength = 16
+	keyLength  = 32
+)
+
+// Params are the argon2id cost settings. Memory is in KiB.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultParams follow the OWASP minimum for argon2id, which takes tens of
+// milliseconds on a small container.
+var DefaultParams = Params{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+}
+
+var current = DefaultParams
+
+// Configure sets the parameters new hashes are made with. It is meant to be
+// called once at startup, before any password is hashed.
+func Configure(p Params) error {
+	if p.Memory < 8*uint32(p.Parallelism) || p.Iterations < 1 || p.Parallelism < 1 {
+		return fmt.Errorf("invalid argon2id parameters m=%d,t=%d,p=%d", p.Memory, p.Iterations, p.Parallelism)
+	}
+	current = p
+	return nil
+}
+
+// Hash returns the argon2id hash of password with the current parameters.
+func Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, current.Iterations, current.Memory, current.Parallelism, keyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		current.Memory, current.Iterations, current.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify reports whether password matches hash, and if so whether hash
+// should be replaced with a fresh one because it uses bcrypt or outdated
+// parameters. Malformed hashes never match.
+func Verify(password, hash string) (match, rehash bool) {
+	if isBcrypt(hash) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil, true
+	}
+
+	p, version, salt, key, err := decode(hash)
+	if err != nil {
+		return false, false
+	}
+	other := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false
+	}
+	return true, version != argon2.Version || p != current || len(salt) != saltLength || len(key) != keyLength
+}
+
+// IsHash reports whether s looks like a hash this package can verify, as
+// opposed to a plain-text password.
+func IsHash(s string) bool {
+	if isBcrypt(s) {
+		return true
+	}
+	_, _, _, _, err := decode(s)
+	return err == nil
+}
+
+func isBcrypt(hash string) bool {
+	_, err := bcrypt.Cost([]byte(hash))
+	return err == nil
+}
+
+func decode(hash string) (p Params, version int, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return p, 0, nil, nil, errors.New("not an argon2id hash")
+	}
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, 0, nil, nil, err
+	}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, 0, nil, nil, err
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, 0, nil, nil, err
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return p, 0, nil, nil, err
+	}
+	if p.Iterations < 1 || p.Parallelism < 1 || len(key) == 0 {
+		return p, 0, nil, nil, errors.New("invalid argon2id parameters")
+	}
+	return p, version, salt, key, nil
+}
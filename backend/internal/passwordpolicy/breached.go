@@ -0,0 +1,149 @@
+package passwordpolicy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// maxLineLength bounds a line of the breached-password list. Lines are
+	// a SHA-1 hash and an optional count, well under this.
+	maxLineLength = 128
+	// minPrefixLength is the shortest hash prefix the list may hold. A
+	// shorter one would refuse a large share of all passwords, which is
+	// more likely a damaged file than what was meant.
+	minPrefixLength = 10
+)
+
+// BreachedList is a file of SHA-1 hashes of known breached passwords, one
+// per line in ascending order, as published by Have I Been Pwned. Each line
+// holds the hash in hex, or just a prefix of it to keep the file small,
+// optionally followed by ":" and a count, which is ignored. The file is
+// searched in place, so it can be far larger than memory.
+type BreachedList struct {
+	path string
+}
+
+// OpenBreachedList checks that the list at path can be read.
+func OpenBreachedList(path string) (*BreachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+	return &BreachedList{path: path}, nil
+}
+
+// Contains reports whether password's SHA-1 hash, or a listed prefix of it,
+// is in the list.
+func (l *BreachedList) Contains(password string) (bool, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	target := sha1Hex(password)
+
+	// Find the first line at or after each offset, and narrow down to the
+	// first one that does not sort before target.
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		entry, ok, err := entryAt(f, mid)
+		if err != nil {
+			return false, err
+		}
+		if !ok || compare(entry, target) >= 0 {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	entry, ok, err := entryAt(f, lo)
+	if err != nil || !ok {
+		return false, err
+	}
+	if compare(entry, target) != 0 {
+		return false, nil
+	}
+	if len(entry) < minPrefixLength {
+		return false, fmt.Errorf("breached password list has a hash prefix %q shorter than %d characters", entry, minPrefixLength)
+	}
+	return true, nil
+}
+
+var errLineTooLong = fmt.Errorf("breached password list has a line over %d bytes", maxLineLength)
+
+// entryAt returns the hash on the first line starting at or after offset.
+// ok is false when there is no such line.
+func entryAt(f *os.File, offset int64) (entry string, ok bool, err error) {
+	start := offset
+	if start > 0 {
+		// Read from the byte before, so a line starting exactly at offset
+		// is recognised by the newline ending the previous one.
+		start--
+	}
+
+	// Enough for the rest of one line and the whole of the next, with
+	// their newlines.
+	buf := make([]byte, 2*(maxLineLength+1))
+	n, err := f.ReadAt(buf, start)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", false, err
+	}
+	// A short read means the end of the file is in buf.
+	atEOF := n < len(buf)
+	buf = buf[:n]
+
+	if offset > 0 {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 && atEOF {
+			return "", false, nil
+		}
+		if i < 0 || i > maxLineLength {
+			return "", false, errLineTooLong
+		}
+		buf = buf[i+1:]
+	}
+	if len(buf) == 0 {
+		return "", false, nil
+	}
+
+	if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+		buf = buf[:i]
+	} else if !atEOF {
+		return "", false, errLineTooLong
+	}
+	line := strings.TrimSpace(string(buf))
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.ToUpper(line), true, nil
+}
+
+// compare orders a listed hash or hash prefix against a full hash.
+func compare(entry, target string) int {
+	if entry == "" {
+		// A blank line never matches.
+		return -1
+	}
+	if len(entry) < len(target) {
+		target = target[:len(entry)]
+	}
+	return strings.Compare(entry, target)
+}
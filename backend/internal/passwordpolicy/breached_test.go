@@ -0,0 +1,146 @@
+package passwordpolicy
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeList(t *testing.T, content string) *BreachedList {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := OpenBreachedList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+// passwords returns n distinct passwords and their hashes in list order.
+func passwords(n int) ([]string, []string) {
+	byHash := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		p := fmt.Sprintf("password-%d", i)
+		byHash[sha1Hex(p)] = p
+	}
+	hashes := make([]string, 0, n)
+	for h := range byHash {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+	listed := make([]string, n)
+	for i, h := range hashes {
+		listed[i] = byHash[h]
+	}
+	return listed, hashes
+}
+
+func TestBreachedListContains(t *testing.T) {
+	listed, hashes := passwords(500)
+	rng := rand.New(rand.NewSource(1))
+
+	var lines []string
+	for _, h := range hashes {
+		// Vary the line length so that offsets land all over lines.
+		line := h
+		if rng.Intn(2) == 0 {
+			line = strings.ToLower(line)
+		}
+		if rng.Intn(3) > 0 {
+			line += fmt.Sprintf(":%d", rng.Intn(100000))
+		}
+		lines = append(lines, line)
+	}
+
+	layouts := map[string]string{
+		"trailing newline": strings.Join(lines, "\n") + "\n",
+		"no final newline": strings.Join(lines, "\n"),
+		"crlf":             strings.Join(lines, "\r\n") + "\r\n",
+	}
+	for name, content := range layouts {
+		t.Run(name, func(t *testing.T) {
+			list := writeList(t, content)
+			for _, p := range listed {
+				found, err := list.Contains(p)
+				if err != nil || !found {
+					t.Fatalf("Contains(%q) = %v, %v, want true", p, found, err)
+				}
+			}
+			// Passwords sorting before, between and after every entry.
+			for i := 0; i < 500; i++ {
+				p := fmt.Sprintf("unlisted-%d", i)
+				found, err := list.Contains(p)
+				if err != nil || found {
+					t.Fatalf("Contains(%q) = %v, %v, want false", p, found, err)
+				}
+			}
+		})
+	}
+}
+
+func TestBreachedListEdges(t *testing.T) {
+	listed, hashes := passwords(3)
+	below, above := "", ""
+	for i := 0; below == "" || above == ""; i++ {
+		p := fmt.Sprintf("edge-%d", i)
+		switch h := sha1Hex(p); {
+		case h < hashes[0]:
+			below = p
+		case h > hashes[2]:
+			above = p
+		}
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		password string
+		want     bool
+	}{
+		{"empty file", "", listed[0], false},
+		{"only entry", hashes[1], listed[1], true},
+		{"first entry", strings.Join(hashes, "\n"), listed[0], true},
+		{"last entry without newline", strings.Join(hashes, "\n"), listed[2], true},
+		{"before every entry", strings.Join(hashes, "\n") + "\n", below, false},
+		{"after every entry", strings.Join(hashes, "\n") + "\n", above, false},
+		{"after every entry without newline", strings.Join(hashes, "\n"), above, false},
+		{"prefix", hashes[0] + "\n" + hashes[1][:minPrefixLength] + "\n" + hashes[2] + "\n", listed[1], true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := writeList(t, tt.content).Contains(tt.password)
+			if err != nil {
+				t.Fatalf("Contains: %v", err)
+			}
+			if found != tt.want {
+				t.Errorf("Contains = %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreachedListRejectsDamagedFiles(t *testing.T) {
+	listed, hashes := passwords(3)
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"short prefix", hashes[0] + "\n" + hashes[1][:minPrefixLength-1] + "\n" + hashes[2] + "\n"},
+		{"long line", hashes[0] + "\n" + hashes[1] + ":" + strings.Repeat("9", maxLineLength) + "\n" + hashes[2] + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := writeList(t, tt.content).Contains(listed[1]); err == nil {
+				t.Error("Contains succeeded on a damaged list")
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package passwordpolicy decides whether a password is acceptable for an
+// account.
+package passwordpolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+)
+
+// Policy holds the rules passwords are checked against.
+type Policy struct {
+	minLength     int
+	maxLength     int
+	requireUpper  bool
+	requireLower  bool
+	requireDigit  bool
+	requireSymbol bool
+	disallowEmail bool
+	breached      *BreachedList
+}
+
+// New builds the policy described by cfg, opening the breached-password
+// list when one is configured.
+func New(cfg config.PasswordPolicyConfig) (*Policy, error) {
+	if cfg.MinLength > cfg.MaxLength {
+		return nil, fmt.Errorf("PASSWORD_MIN_LENGTH %d is above PASSWORD_MAX_LENGTH %d", cfg.MinLength, cfg.MaxLength)
+	}
+
+	p := &Policy{
+		minLength:     cfg.MinLength,
+		maxLength:     cfg.MaxLength,
+		requireUpper:  cfg.RequireUpper,
+		requireLower:  cfg.RequireLower,
+		requireDigit:  cfg.RequireDigit,
+		requireSymbol: cfg.RequireSymbol,
+		disallowEmail: cfg.DisallowEmail,
+	}
+	if cfg.BreachedListFile != "" {
+		breached, err := OpenBreachedList(cfg.BreachedListFile)
+		if err != nil {
+			return nil, err
+		}
+		p.breached = breached
+	}
+	return p, nil
+}
+
+// Check returns an invalid input error explaining what is wrong with
+// password, or nil if it is acceptable. email is the account's address and
+// may be empty.
+func (p *Policy) Check(password, email string) error {
+	if !p.meetsRequirements(password) {
+		return domain.NewInvalidInputError(p.Describe())
+	}
+
+	if p.disallowEmail && containsEmail(password, email) {
+		return domain.NewInvalidInputError("Password must not contain your email address")
+	}
+
+	if p.breached != nil {
+		found, err := p.breached.Contains(password)
+		if err != nil {
+			return domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to check password", err)
+		}
+		if found {
+			return domain.NewInvalidInputError("This password has appeared in a data breach. Choose a different one")
+		}
+	}
+	return nil
+}
+
+// Describe states the length and character rules, for error messages and
+// forms.
+func (p *Policy) Describe() string {
+	var classes []string
+	if p.requireUpper {
+		classes = append(classes, "uppercase")
+	}
+	if p.requireLower {
+		classes = append(classes, "lowercase")
+	}
+	if p.requireDigit {
+		classes = append(classes, "number")
+	}
+	if p.requireSymbol {
+		classes = append(classes, "special character")
+	}
+
+	desc := fmt.Sprintf("Password must be %d to %d characters", p.minLength, p.maxLength)
+	switch len(classes) {
+	case 0:
+		return desc
+	case 1:
+		return desc + " and contain a " + classes[0]
+	default:
+		return desc + " and contain " + strings.Join(classes[:len(classes)-1], ", ") + " and " + classes[len(classes)-1]
+	}
+}
+
+func (p *Policy) meetsRequirements(password string) bool {
+	length := utf8.RuneCountInString(password)
+	if length < p.minLength || length > p.maxLength {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	return (upper || !p.requireUpper) &&
+		(lower || !p.requireLower) &&
+		(digit || !p.requireDigit) &&
+		(symbol || !p.requireSymbol)
+}
+
+// containsEmail reports whether password contains the local part of email,
+// ignoring case. Very short local parts are too likely to match by chance.
+func containsEmail(password, email string) bool {
+	local, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if len(local) < 3 {
+		return false
+	}
+	return strings.Contains(strings.ToLower(password), local)
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type AbuseReportRepository interface {
+	Create(ctx context.Context, report *domain.AbuseReport) error
+	GetByID(ctx context.Context, id uint) (*domain.AbuseReport, error)
+	// List returns reports with the given status, or all reports when status is empty.
+	List(ctx context.Context, status string) ([]domain.AbuseReport, error)
+	Update(ctx context.Context, report *domain.AbuseReport) error
+}
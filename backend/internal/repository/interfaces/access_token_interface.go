@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+	"tech-test/backend/internal/domain"
+)
+
+type AccessTokenRepository interface {
+	Create(ctx context.Context, token *domain.PersonalAccessToken) error
+	GetByID(ctx context.Context, id uint) (*domain.PersonalAccessToken, error)
+	GetByHash(ctx context.Context, hash string) (*domain.PersonalAccessToken, error)
+	ListByUser(ctx context.Context, userID uint) ([]domain.PersonalAccessToken, error)
+	Delete(ctx context.Context, id uint) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+type AuditRepository interface {
+	Record(ctx context.Context, event *domain.AuditEvent) error
+	// List returns matching events, newest first.
+	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type CollectionRepository interface {
+	Create(ctx context.Context, collection *domain.Collection) error
+	GetByID(ctx context.Context, id uint) (*domain.Collection, error)
+	GetByShareID(ctx context.Context, shareID string) (*domain.Collection, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.Collection, error)
+	Update(ctx context.Context, collection *domain.Collection) error
+	UpdateShareableID(ctx context.Context, id uint, shareableID string) error
+	Delete(ctx context.Context, id uint) error
+}
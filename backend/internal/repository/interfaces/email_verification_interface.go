@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+	"time"
+)
+
+type EmailVerificationRepository interface {
+	// Create replaces any token the user already holds, so only the most
+	// recently sent link works.
+	Create(ctx context.Context, token *domain.EmailVerificationToken) error
+	// LatestCreatedAt returns when the user's current token was issued, or
+	// nil when they hold none.
+	LatestCreatedAt(ctx context.Context, userID uint) (*time.Time, error)
+	// Consume deletes the unexpired token with this hash and returns it.
+	// Any other token fails with domain.ErrInvalidVerificationToken.
+	Consume(ctx context.Context, hash string) (*domain.EmailVerificationToken, error)
+}
@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"tech-test/backend/internal/domain"
+)
+
+type ErasureRepository interface {
+	Create(ctx context.Context, req *domain.ErasureRequest) error
+	// GetPending returns the user's pending request, or
+	// domain.ErrErasureNotFound.
+	GetPending(ctx context.Context, userID uint) (*domain.ErasureRequest, error)
+	// ListDue returns pending requests scheduled at or before now, oldest
+	// first.
+	ListDue(ctx context.Context, now time.Time) ([]domain.ErasureRequest, error)
+	// List returns the most recent requests, newest first.
+	List(ctx context.Context, limit int) ([]domain.ErasureRequest, error)
+	// SetStatus moves a pending request to status at at.
+	SetStatus(ctx context.Context, id uint, status domain.ErasureStatus, at time.Time) error
+
+	// HandOverFiles makes ownerID the uploader of the files userID
+	// uploaded to the organization.
+	HandOverFiles(ctx context.Context, userID, organizationID, ownerID uint) error
+
+	// ListOrganizationFiles returns every file in the organization,
+	// whoever uploaded it.
+	ListOrganizationFiles(ctx context.Context, organizationID uint) ([]domain.File, error)
+	// PurgeOrganization deletes, in one transaction, the organization with
+	// its files and everything shared from them, its memberships and its
+	// invitations.
+	PurgeOrganization(ctx context.Context, organizationID uint) error
+
+	// PurgeUserData deletes, in one transaction, the rows the user owns in
+	// every table apart from users: their personal files and everything
+	// shared from them, collections, upload requests, sessions, tokens, two-factor
+	// credentials, sign-in identities, organization memberships and login
+	// history. The audit trail, which holds only IDs, is kept. email is the
+	// user's normalized address, which login history is keyed by.
+	PurgeUserData(ctx context.Context, userID uint, email string) error
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 	"tech-test/backend/internal/domain"
 )
 
@@ -9,10 +10,15 @@ type FileRepository interface {
     Create(ctx context.Context, file *domain.File) error
     GetByID(ctx context.Context, id uint) (*domain.File, error)
     GetByUserID(ctx context.Context, userID uint) ([]domain.File, error)
-    GetUserFilesPaginated(ctx context.Context, userID uint, page, pageSize int) ([]domain.File, int64, error)
+    GetFilesPaginated(ctx context.Context, scope domain.FileScope, page, pageSize int) ([]domain.File, int64, error)
     List(ctx context.Context) ([]domain.File, error)
     Delete(ctx context.Context, id uint) error
-    SearchFiles(ctx context.Context, userID uint, searchTerm string) ([]domain.File, error)
+    SearchFiles(ctx context.Context, scope domain.FileScope, searchTerm string) ([]domain.File, error)
+    GetStorageUsage(ctx context.Context, scope domain.FileScope) (*domain.StorageUsage, error)
     UpdateShareableID(ctx context.Context, fileID uint, shareableID string) error
+    UpdateShareExpiry(ctx context.Context, fileID uint, expiresAt *time.Time) error
+    SetQuarantined(ctx context.Context, fileID uint, quarantined bool) error
+    // SetShareDisabled also removes the public link when disabling sharing.
+    SetShareDisabled(ctx context.Context, fileID uint, disabled bool) error
     GetFileByShareID(ctx context.Context, shareID string) (*domain.File, error)
 }
\ No newline at end of file
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"tech-test/backend/internal/domain"
+)
+
+type ImpersonationRepository interface {
+	Create(ctx context.Context, impersonation *domain.Impersonation) error
+	GetByID(ctx context.Context, id string) (*domain.Impersonation, error)
+	// End marks the impersonation ended at at, unless it already was.
+	End(ctx context.Context, id string, at time.Time) error
+	// List returns the most recent impersonations, newest first.
+	List(ctx context.Context, limit int) ([]domain.Impersonation, error)
+}
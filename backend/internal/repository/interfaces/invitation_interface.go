@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"tech-test/backend/internal/domain"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) error
+	// List returns the most recent invitations, newest first.
+	List(ctx context.Context, limit int) ([]domain.Invitation, error)
+	Delete(ctx context.Context, id uint) error
+
+	// ClaimCode marks the invitation with codeHash accepted at at, provided
+	// it is pending, unexpired and either open to anyone or addressed to
+	// email. Otherwise it returns domain.ErrInvitationInvalid.
+	ClaimCode(ctx context.Context, codeHash, email string, at time.Time) (*domain.Invitation, error)
+	// ClaimForEmail claims the oldest pending, unexpired invitation
+	// addressed to email, or returns domain.ErrInvitationInvalid.
+	ClaimForEmail(ctx context.Context, email string, at time.Time) (*domain.Invitation, error)
+	// Release makes a claimed invitation pending again, after the account
+	// it was claimed for could not be created.
+	Release(ctx context.Context, id uint) error
+	SetAcceptedBy(ctx context.Context, id, userID uint) error
+}
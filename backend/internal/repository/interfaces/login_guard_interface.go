@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+type LoginGuardRepository interface {
+	// GetThrottles returns the throttles that exist for the given keys.
+	GetThrottles(ctx context.Context, keys []string) ([]domain.LoginThrottle, error)
+	// UpdateThrottle loads the throttle for key, or a zero one, applies
+	// update and saves the result in one transaction.
+	UpdateThrottle(ctx context.Context, key string, update func(*domain.LoginThrottle)) error
+	DeleteThrottle(ctx context.Context, key string) error
+
+	RecordAttempt(ctx context.Context, attempt *domain.LoginAttempt) error
+	ListAttempts(ctx context.Context, filter domain.LoginAttemptFilter) ([]domain.LoginAttempt, error)
+
+	RecordLockout(ctx context.Context, event *domain.LockoutEvent) error
+	ListLockouts(ctx context.Context, limit int) ([]domain.LockoutEvent, error)
+}
@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type MFARepository interface {
+	// GetTOTP returns nil without an error when the user has not enrolled.
+	GetTOTP(ctx context.Context, userID uint) (*domain.TOTPCredential, error)
+	SaveTOTP(ctx context.Context, credential *domain.TOTPCredential) error
+	// DeleteTOTP removes the enrollment along with its recovery codes.
+	DeleteTOTP(ctx context.Context, userID uint) error
+	// AdvanceTOTPStep records step as used, reporting false if it or a
+	// later step was already used.
+	AdvanceTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
+
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error
+	// UseRecoveryCode marks a matching unused code as used, reporting
+	// whether one was found.
+	UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+
+	CreateChallenge(ctx context.Context, challenge *domain.MFAChallenge) error
+	GetChallenge(ctx context.Context, hash string) (*domain.MFAChallenge, error)
+	RecordChallengeAttempt(ctx context.Context, id uint) error
+	DeleteChallenge(ctx context.Context, id uint) error
+}
+
+type SettingRepository interface {
+	// Get returns "" without an error when the setting was never set.
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string) error
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"tech-test/backend/internal/domain"
+)
+
+type OIDCRepository interface {
+	CreateState(ctx context.Context, state *domain.OIDCLoginState) error
+	// ConsumeState deletes and returns the unexpired state with this hash,
+	// or fails with domain.ErrInvalidOIDCState.
+	ConsumeState(ctx context.Context, hash string) (*domain.OIDCLoginState, error)
+
+	// GetIdentity returns nil without an error when the subject is unknown.
+	GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error
+	TouchIdentity(ctx context.Context, id uint, at time.Time) error
+
+	CreateTicket(ctx context.Context, ticket *domain.OIDCLoginTicket) error
+	// ConsumeTicket deletes and returns the unexpired ticket with this
+	// hash, or fails with domain.ErrInvalidOIDCTicket.
+	ConsumeTicket(ctx context.Context, hash string) (*domain.OIDCLoginTicket, error)
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"tech-test/backend/internal/domain"
+)
+
+type OrganizationRepository interface {
+	// Create stores org with owner as its first member.
+	Create(ctx context.Context, org *domain.Organization, owner *domain.OrganizationMember) error
+	GetByID(ctx context.Context, id uint) (*domain.Organization, error)
+	Update(ctx context.Context, org *domain.Organization) error
+	// Delete removes the organization and its memberships. It fails with a
+	// conflict while the organization still has files.
+	Delete(ctx context.Context, id uint) error
+	// ListForUser returns the organizations userID belongs to, with Role
+	// set to theirs.
+	ListForUser(ctx context.Context, userID uint) ([]domain.Organization, error)
+
+	GetMember(ctx context.Context, orgID, userID uint) (*domain.OrganizationMember, error)
+	ListMembers(ctx context.Context, orgID uint) ([]domain.OrganizationMember, error)
+	AddMember(ctx context.Context, member *domain.OrganizationMember) error
+	UpdateMemberRole(ctx context.Context, orgID, userID uint, role domain.OrgRole) error
+	RemoveMember(ctx context.Context, orgID, userID uint) error
+
+	// SaveInvitation stores invitation, replacing any earlier one for the
+	// same organization and email.
+	SaveInvitation(ctx context.Context, invitation *domain.OrganizationInvitation) error
+	GetInvitation(ctx context.Context, id uint) (*domain.OrganizationInvitation, error)
+	ListInvitations(ctx context.Context, orgID uint) ([]domain.OrganizationInvitation, error)
+	// ListInvitationsForEmail returns the unexpired invitations sent to
+	// email, with OrganizationName set.
+	ListInvitationsForEmail(ctx context.Context, email string, now time.Time) ([]domain.OrganizationInvitation, error)
+	DeleteInvitation(ctx context.Context, id uint) error
+	// AcceptInvitation deletes the invitation and adds member in one step,
+	// so an invitation is only used once.
+	AcceptInvitation(ctx context.Context, id uint, member *domain.OrganizationMember) error
+}
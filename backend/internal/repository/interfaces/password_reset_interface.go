@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"tech-test/backend/internal/domain"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) error
+	// LatestCreatedAt returns when the user's newest token was issued, or
+	// nil when they hold none.
+	LatestCreatedAt(ctx context.Context, userID uint) (*time.Time, error)
+	// Consume marks the unused, unexpired token with this hash as used and
+	// returns it. Any other token fails with domain.ErrInvalidResetToken.
+	Consume(ctx context.Context, hash string) (*domain.PasswordResetToken, error)
+	// Release makes a consumed token usable again, for when the new
+	// password was refused.
+	Release(ctx context.Context, id uint) error
+	// DeleteByUser drops every reset token the user still holds.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
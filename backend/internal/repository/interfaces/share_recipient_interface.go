@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type ShareRecipientRepository interface {
+	Create(ctx context.Context, recipient *domain.ShareRecipient) error
+	Update(ctx context.Context, recipient *domain.ShareRecipient) error
+	GetByFileID(ctx context.Context, fileID uint) ([]domain.ShareRecipient, error)
+	RecordAccess(ctx context.Context, fileID uint, token string) error
+}
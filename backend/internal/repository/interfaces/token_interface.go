@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+	"tech-test/backend/internal/domain"
+)
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	// RotateRefreshToken stores next and marks old as replaced by it. It
+	// fails with domain.ErrRefreshTokenReused if old was already revoked.
+	RotateRefreshToken(ctx context.Context, old, next *domain.RefreshToken) error
+
+	CreateSession(ctx context.Context, session *domain.Session) error
+	GetSession(ctx context.Context, id string) (*domain.Session, error)
+	// ListActiveSessions returns the user's unrevoked, unexpired sessions,
+	// most recently seen first.
+	ListActiveSessions(ctx context.Context, userID uint) ([]domain.Session, error)
+	// TouchSession records activity on a session. A non-zero expiresAt or
+	// a non-empty client field replaces the stored one.
+	TouchSession(ctx context.Context, id string, at, expiresAt time.Time, client domain.SessionClient) error
+	// RevokeSession revokes the session and its refresh tokens.
+	RevokeSession(ctx context.Context, id string) error
+	// RevokeUserSessions revokes every session and refresh token the user
+	// has, except the session with ID keep.
+	RevokeUserSessions(ctx context.Context, userID uint, keep string) error
+
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	SetUserRevokedAt(ctx context.Context, userID uint, at time.Time) error
+	GetUserRevokedAt(ctx context.Context, userID uint) (*time.Time, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type UploadRequestRepository interface {
+	Create(ctx context.Context, req *domain.UploadRequest) error
+	GetByID(ctx context.Context, id uint) (*domain.UploadRequest, error)
+	GetByToken(ctx context.Context, token string) (*domain.UploadRequest, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.UploadRequest, error)
+	Delete(ctx context.Context, id uint) error
+}
@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"tech-test/backend/internal/domain"
+	"time"
 )
 
 type UserRepository interface {
@@ -13,4 +14,15 @@ type UserRepository interface {
 	Delete(ctx context.Context, id uint) error
 	GetAll(ctx context.Context) ([]domain.User, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	// SetPassword stores a new password hash and whether it must be changed
+	// at the next login.
+	SetPassword(ctx context.Context, id uint, hash string, changeRequired bool) error
+	SetPasswordChangeRequired(ctx context.Context, id uint, required bool) error
+	// SetDisabled disables the account at disabledAt, or enables it when
+	// disabledAt is nil.
+	SetDisabled(ctx context.Context, id uint, disabledAt *time.Time, reason string) error
+	// SetEmailVerified marks the email verified at verifiedAt, or unverified
+	// when verifiedAt is nil.
+	SetEmailVerified(ctx context.Context, id uint, verifiedAt *time.Time) error
+	RecordLogin(ctx context.Context, id uint, at time.Time, ip string) error
 } 
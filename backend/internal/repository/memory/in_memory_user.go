@@ -4,12 +4,15 @@ package memory
 import (
     "context"
     "sync"
+    "time"
     "tech-test/backend/internal/domain"
     "tech-test/backend/internal/repository/interfaces"
 )
 
 var _ interfaces.UserRepository = (*userRepository)(nil)
 
+// userRepository keeps its own copies of users, so callers can change what
+// they are given without touching the store.
 type userRepository struct {
     users  map[uint]*domain.User
     mutex  sync.RWMutex
@@ -33,9 +36,22 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
         }
     }
 
-    user.ID = r.nextID
-    r.users[r.nextID] = user
-    r.nextID++
+    // Keep a preset ID (e.g. from a seed file) so references to it stay valid.
+    if user.ID == 0 {
+        user.ID = r.nextID
+    } else if _, taken := r.users[user.ID]; taken {
+        return domain.ErrConflict
+    }
+    now := time.Now().UTC()
+    if user.CreatedAt.IsZero() {
+        user.CreatedAt = now
+    }
+    user.UpdatedAt = now
+    stored := *user
+    r.users[user.ID] = &stored
+    if user.ID >= r.nextID {
+        r.nextID = user.ID + 1
+    }
     return nil
 }
 
@@ -47,7 +63,8 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*domain.User, er
     if !exists {
         return nil, domain.ErrUserNotFound
     }
-    return user, nil
+    found := *user
+    return &found, nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
@@ -56,22 +73,54 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 
     for _, user := range r.users {
         if user.Email == email {
-            return user, nil
+            found := *user
+            return &found, nil
         }
     }
     return nil, domain.ErrUserNotFound
 }
 
+// Update applies the non-zero fields of user, like the SQLite store does, so
+// a partial update leaves the rest of the record alone.
 func (r *userRepository) Update(ctx context.Context, id uint, user *domain.User) error {
     r.mutex.Lock()
     defer r.mutex.Unlock()
 
-    if _, exists := r.users[id]; !exists {
+    existing, exists := r.users[id]
+    if !exists {
         return domain.ErrUserNotFound
     }
 
-    user.ID = id  // Ensure ID remains the same
-    r.users[id] = user
+    updated := *existing
+    if user.Email != "" && user.Email != existing.Email {
+        for otherID, other := range r.users {
+            if otherID != id && other.Email == user.Email {
+                return domain.ErrDuplicateEmail
+            }
+        }
+        updated.Email = user.Email
+    }
+    if user.Password != "" {
+        updated.Password = user.Password
+    }
+    if user.FirstName != "" {
+        updated.FirstName = user.FirstName
+    }
+    if user.Surname != "" {
+        updated.Surname = user.Surname
+    }
+    if !user.DOB.IsZero() {
+        updated.DOB = user.DOB
+    }
+    if user.Role != "" {
+        updated.Role = user.Role
+    }
+    if user.EmailVerifiedAt != nil {
+        updated.EmailVerifiedAt = user.EmailVerifiedAt
+    }
+    updated.UpdatedAt = time.Now().UTC()
+
+    r.users[id] = &updated
     return nil
 }
 
@@ -104,9 +153,63 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain
 
     for _, user := range r.users {
         if user.Email == email {
-            return user, nil
+            found := *user
+            return &found, nil
         }
     }
     return nil, domain.ErrUserNotFound
 }
 
+
+func (r *userRepository) SetPassword(ctx context.Context, id uint, hash string, changeRequired bool) error {
+    return r.modify(id, true, func(user *domain.User) {
+        user.Password = hash
+        user.PasswordChangeRequired = changeRequired
+    })
+}
+
+func (r *userRepository) SetPasswordChangeRequired(ctx context.Context, id uint, required bool) error {
+    return r.modify(id, true, func(user *domain.User) {
+        user.PasswordChangeRequired = required
+    })
+}
+
+func (r *userRepository) SetDisabled(ctx context.Context, id uint, disabledAt *time.Time, reason string) error {
+    return r.modify(id, true, func(user *domain.User) {
+        user.DisabledAt = disabledAt
+        user.DisabledReason = reason
+    })
+}
+
+func (r *userRepository) SetEmailVerified(ctx context.Context, id uint, verifiedAt *time.Time) error {
+    return r.modify(id, true, func(user *domain.User) {
+        user.EmailVerifiedAt = verifiedAt
+    })
+}
+
+func (r *userRepository) RecordLogin(ctx context.Context, id uint, at time.Time, ip string) error {
+    return r.modify(id, false, func(user *domain.User) {
+        user.LastLoginAt = &at
+        user.LastLoginIP = ip
+    })
+}
+
+// modify changes the stored user in place, bumping UpdatedAt if touch is
+// set.
+func (r *userRepository) modify(id uint, touch bool, change func(*domain.User)) error {
+    r.mutex.Lock()
+    defer r.mutex.Unlock()
+
+    existing, exists := r.users[id]
+    if !exists {
+        return domain.ErrUserNotFound
+    }
+
+    updated := *existing
+    change(&updated)
+    if touch {
+        updated.UpdatedAt = time.Now().UTC()
+    }
+    r.users[id] = &updated
+    return nil
+}
@@ -0,0 +1,71 @@
+// Package seed loads initial data into repositories at startup.
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/passwordhash"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/utils"
+)
+
+// ImportUsers reads a JSON array of users from path and creates those whose
+// email is not yet known. IDs are kept so existing file rows still point at
+// their owners. Passwords may be argon2id or bcrypt hashes, as exported from
+// the memory store, or plain text, which is hashed. It returns how many were created.
+func ImportUsers(ctx context.Context, repo interfaces.UserRepository, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read user seed file: %w", err)
+	}
+
+	var users []domain.User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return 0, fmt.Errorf("failed to parse user seed file: %w", err)
+	}
+
+	created := 0
+	for i := range users {
+		user := &users[i]
+		if user.Email == "" || user.Password == "" {
+			return created, fmt.Errorf("seed user %d: email and password are required", i)
+		}
+
+		if _, err := repo.GetByEmail(ctx, user.Email); err == nil {
+			continue
+		} else if !errors.Is(err, domain.ErrUserNotFound) {
+			return created, err
+		}
+
+		if !passwordhash.IsHash(user.Password) {
+			hashed, err := utils.HashPassword(user.Password)
+			if err != nil {
+				return created, fmt.Errorf("seed user %s: %w", user.Email, err)
+			}
+			user.Password = hashed
+		}
+		if user.Role == "" {
+			user.Role = domain.RoleMember
+		}
+		if user.EmailVerifiedAt == nil {
+			// Imported accounts were created by the operator, not self-registered.
+			now := time.Now().UTC()
+			user.EmailVerifiedAt = &now
+		}
+		if !user.Role.Valid() {
+			return created, fmt.Errorf("seed user %s: unknown role %q", user.Email, user.Role)
+		}
+
+		if err := repo.Create(ctx, user); err != nil {
+			return created, fmt.Errorf("seed user %s: %w", user.Email, err)
+		}
+		created++
+	}
+	return created, nil
+}
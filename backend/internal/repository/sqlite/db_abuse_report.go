@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type abuseReportRepository struct {
+	db *gorm.DB
+}
+
+func NewAbuseReportRepository(db *gorm.DB) interfaces.AbuseReportRepository {
+	return &abuseReportRepository{db: db}
+}
+
+func (r *abuseReportRepository) Create(ctx context.Context, report *domain.AbuseReport) error {
+	if err := r.db.WithContext(ctx).Create(report).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create abuse report",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *abuseReportRepository) GetByID(ctx context.Context, id uint) (*domain.AbuseReport, error) {
+	var report domain.AbuseReport
+	if err := r.db.WithContext(ctx).First(&report, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFoundError("Abuse report")
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get abuse report",
+			err,
+		)
+	}
+	return &report, nil
+}
+
+func (r *abuseReportRepository) List(ctx context.Context, status string) ([]domain.AbuseReport, error) {
+	var reports []domain.AbuseReport
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&reports).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list abuse reports",
+			err,
+		)
+	}
+	return reports, nil
+}
+
+func (r *abuseReportRepository) Update(ctx context.Context, report *domain.AbuseReport) error {
+	if err := r.db.WithContext(ctx).Save(report).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update abuse report",
+			err,
+		)
+	}
+	return nil
+}
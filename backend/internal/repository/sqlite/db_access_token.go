@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type accessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAccessTokenRepository(db *gorm.DB) interfaces.AccessTokenRepository {
+	return &accessTokenRepository{db: db}
+}
+
+func (r *accessTokenRepository) Create(ctx context.Context, token *domain.PersonalAccessToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create access token",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *accessTokenRepository) GetByID(ctx context.Context, id uint) (*domain.PersonalAccessToken, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *accessTokenRepository) GetByHash(ctx context.Context, hash string) (*domain.PersonalAccessToken, error) {
+	return r.first(ctx, "token_hash = ?", hash)
+}
+
+func (r *accessTokenRepository) first(ctx context.Context, query string, arg interface{}) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	if err := r.db.WithContext(ctx).Where(query, arg).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("Access token")
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get access token",
+			err,
+		)
+	}
+	return &token, nil
+}
+
+func (r *accessTokenRepository) ListByUser(ctx context.Context, userID uint) ([]domain.PersonalAccessToken, error) {
+	var tokens []domain.PersonalAccessToken
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list access tokens",
+			err,
+		)
+	}
+	return tokens, nil
+}
+
+func (r *accessTokenRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.PersonalAccessToken{}, id)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to delete access token",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("Access token")
+	}
+	return nil
+}
+
+func (r *accessTokenRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update access token",
+			err,
+		)
+	}
+	return nil
+}
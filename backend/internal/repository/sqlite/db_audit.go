@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) interfaces.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Record(ctx context.Context, event *domain.AuditEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to record audit event",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	var events []domain.AuditEvent
+	query := r.db.WithContext(ctx).Order("created_at DESC").Limit(filter.Limit)
+	if filter.UserID != 0 {
+		query = query.Where("actor_id = ? OR subject_id = ?", filter.UserID, filter.UserID)
+	}
+	if filter.ImpersonationID != "" {
+		query = query.Where("impersonation_id = ?", filter.ImpersonationID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if err := query.Find(&events).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list audit events",
+			err,
+		)
+	}
+	return events, nil
+}
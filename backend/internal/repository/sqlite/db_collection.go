@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type collectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) interfaces.CollectionRepository {
+	return &collectionRepository{db: db}
+}
+
+func (r *collectionRepository) Create(ctx context.Context, collection *domain.Collection) error {
+	if err := r.db.WithContext(ctx).Omit("Files.*").Create(collection).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create collection",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *collectionRepository) GetByID(ctx context.Context, id uint) (*domain.Collection, error) {
+	var collection domain.Collection
+	if err := r.db.WithContext(ctx).Preload("Files").First(&collection, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFoundError("Collection")
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get collection",
+			err,
+		)
+	}
+	return &collection, nil
+}
+
+func (r *collectionRepository) GetByShareID(ctx context.Context, shareID string) (*domain.Collection, error) {
+	var collection domain.Collection
+	if err := r.db.WithContext(ctx).Preload("Files").Where("shareable_id = ?", shareID).First(&collection).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFoundError("Collection")
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get collection by share ID",
+			err,
+		)
+	}
+	return &collection, nil
+}
+
+func (r *collectionRepository) GetByUserID(ctx context.Context, userID uint) ([]domain.Collection, error) {
+	var collections []domain.Collection
+	if err := r.db.WithContext(ctx).Preload("Files").Where("user_id = ?", userID).Order("created_at DESC").Find(&collections).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list collections",
+			err,
+		)
+	}
+	return collections, nil
+}
+
+// Update saves the collection name and replaces its file membership.
+func (r *collectionRepository) Update(ctx context.Context, collection *domain.Collection) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(collection).Update("name", collection.Name).Error; err != nil {
+			return err
+		}
+		return tx.Model(collection).Association("Files").Replace(collection.Files)
+	})
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update collection",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *collectionRepository) UpdateShareableID(ctx context.Context, id uint, shareableID string) error {
+	result := r.db.WithContext(ctx).Model(&domain.Collection{}).
+		Where("id = ?", id).
+		Update("shareable_id", shareableID)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update shareable ID",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("Collection")
+	}
+	return nil
+}
+
+func (r *collectionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM collection_files WHERE collection_id = ?", id).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to delete collection",
+				err,
+			)
+		}
+		result := tx.Delete(&domain.Collection{}, id)
+		if result.Error != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to delete collection",
+				result.Error,
+			)
+		}
+		if result.RowsAffected == 0 {
+			return domain.NewNotFoundError("Collection")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) interfaces.EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (r *emailVerificationRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? OR expires_at < ?", token.UserID, time.Now().UTC()).
+			Delete(&domain.EmailVerificationToken{}).Error
+		if err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to replace email verification tokens",
+				err,
+			)
+		}
+		if err := tx.Create(token).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create email verification token",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *emailVerificationRepository) LatestCreatedAt(ctx context.Context, userID uint) (*time.Time, error) {
+	var token domain.EmailVerificationToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get email verification token",
+			err,
+		)
+	}
+	return &token.CreatedAt, nil
+}
+
+func (r *emailVerificationRepository) Consume(ctx context.Context, hash string) (*domain.EmailVerificationToken, error) {
+	var token domain.EmailVerificationToken
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ? AND expires_at > ?", hash, time.Now().UTC()).First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrInvalidVerificationToken
+			}
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to get email verification token",
+				err,
+			)
+		}
+
+		// Deleting is the consumption; a concurrent second use deletes nothing.
+		result := tx.Where("id = ?", token.ID).Delete(&domain.EmailVerificationToken{})
+		if result.Error != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to consume email verification token",
+				result.Error,
+			)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrInvalidVerificationToken
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
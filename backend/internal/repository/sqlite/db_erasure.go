@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type erasureRepository struct {
+	db *gorm.DB
+}
+
+func NewErasureRepository(db *gorm.DB) interfaces.ErasureRepository {
+	return &erasureRepository{db: db}
+}
+
+func (r *erasureRepository) Create(ctx context.Context, req *domain.ErasureRequest) error {
+	if err := r.db.WithContext(ctx).Create(req).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create erasure request",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *erasureRepository) GetPending(ctx context.Context, userID uint) (*domain.ErasureRequest, error) {
+	var req domain.ErasureRequest
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, domain.ErasurePending).
+		First(&req).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrErasureNotFound
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get erasure request",
+			err,
+		)
+	}
+	return &req, nil
+}
+
+func (r *erasureRepository) ListDue(ctx context.Context, now time.Time) ([]domain.ErasureRequest, error) {
+	var reqs []domain.ErasureRequest
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_for <= ?", domain.ErasurePending, now).
+		Order("scheduled_for ASC").
+		Find(&reqs).Error
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list due erasure requests",
+			err,
+		)
+	}
+	return reqs, nil
+}
+
+func (r *erasureRepository) List(ctx context.Context, limit int) ([]domain.ErasureRequest, error) {
+	var reqs []domain.ErasureRequest
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&reqs).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list erasure requests",
+			err,
+		)
+	}
+	return reqs, nil
+}
+
+func (r *erasureRepository) SetStatus(ctx context.Context, id uint, status domain.ErasureStatus, at time.Time) error {
+	updates := map[string]interface{}{"status": status}
+	switch status {
+	case domain.ErasureCancelled:
+		updates["cancelled_at"] = at
+	case domain.ErasureCompleted:
+		updates["completed_at"] = at
+	}
+
+	result := r.db.WithContext(ctx).Model(&domain.ErasureRequest{}).
+		Where("id = ? AND status = ?", id, domain.ErasurePending).
+		Updates(updates)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update erasure request",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrErasureNotFound
+	}
+	return nil
+}
+
+// userOwned are the tables purged by user_id alone, after the rows that
+// refer to the user's files and collections. Files are not among them, as
+// those uploaded to an organization belong to it.
+var userOwned = []interface{}{
+	&domain.Collection{},
+	&domain.UploadRequest{},
+	&domain.RefreshToken{},
+	&domain.Session{},
+	&domain.PersonalAccessToken{},
+	&domain.TOTPCredential{},
+	&domain.RecoveryCode{},
+	&domain.MFAChallenge{},
+	&domain.PasswordResetToken{},
+	&domain.EmailVerificationToken{},
+	&domain.UserIdentity{},
+	&domain.OIDCLoginTicket{},
+	&domain.OrganizationMember{},
+}
+
+func (r *erasureRepository) HandOverFiles(ctx context.Context, userID, organizationID, ownerID uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.File{}).
+		Where("user_id = ? AND organization_id = ?", userID, organizationID).
+		Update("user_id", ownerID).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to hand over organization files",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *erasureRepository) ListOrganizationFiles(ctx context.Context, organizationID uint) ([]domain.File, error) {
+	var files []domain.File
+	if err := r.db.WithContext(ctx).Where("organization_id = ?", organizationID).Find(&files).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list organization files",
+			err,
+		)
+	}
+	return files, nil
+}
+
+func (r *erasureRepository) PurgeOrganization(ctx context.Context, organizationID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		orgFiles := tx.Model(&domain.File{}).Select("id").Where("organization_id = ?", organizationID)
+
+		if err := tx.Where("file_id IN (?)", orgFiles).Delete(&domain.ShareRecipient{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM collection_files WHERE file_id IN (?)", orgFiles).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&domain.File{}, &domain.OrganizationMember{}, &domain.OrganizationInvitation{}} {
+			if err := tx.Where("organization_id = ?", organizationID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&domain.Organization{}, organizationID).Error
+	})
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to purge organization",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *erasureRepository) PurgeUserData(ctx context.Context, userID uint, email string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ownFiles := tx.Model(&domain.File{}).Select("id").Where("user_id = ? AND organization_id IS NULL", userID)
+		ownCollections := tx.Model(&domain.Collection{}).Select("id").Where("user_id = ?", userID)
+
+		if err := tx.Where("file_id IN (?) OR shared_by = ?", ownFiles, userID).Delete(&domain.ShareRecipient{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM collection_files WHERE collection_id IN (?) OR file_id IN (?)", ownCollections, ownFiles).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND organization_id IS NULL", userID).Delete(&domain.File{}).Error; err != nil {
+			return err
+		}
+		for _, model := range userOwned {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("user_id = ? OR email = ?", userID, email).Delete(&domain.LoginAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("scope = ? AND subject = ?", domain.LockoutScopeAccount, email).Delete(&domain.LockoutEvent{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("key = ?", "email:"+email).Delete(&domain.LoginThrottle{}).Error
+	})
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to purge user data",
+			err,
+		)
+	}
+	return nil
+}
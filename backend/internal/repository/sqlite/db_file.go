@@ -7,6 +7,7 @@ import (
     "tech-test/backend/internal/domain"
     "tech-test/backend/internal/repository/interfaces"
     "log"
+    "time"
 )
 
 type fileRepository struct {
@@ -54,17 +55,17 @@ func (r *fileRepository) GetByUserID(ctx context.Context, userID uint) ([]domain
     return files, nil
 }
 
-func (r *fileRepository) GetUserFilesPaginated(ctx context.Context, userID uint, page, pageSize int) ([]domain.File, int64, error) {
+func (r *fileRepository) GetFilesPaginated(ctx context.Context, scope domain.FileScope, page, pageSize int) ([]domain.File, int64, error) {
     var files []domain.File
     var total int64
 
-    if err := r.db.Model(&domain.File{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+    if err := r.scoped(scope).Model(&domain.File{}).Count(&total).Error; err != nil {
         return nil, 0, err
     }
 
     offset := (page - 1) * pageSize
 
-    if err := r.db.Where("user_id = ?", userID).
+    if err := r.scoped(scope).
         Offset(offset).
         Limit(pageSize).
         Find(&files).Error; err != nil {
@@ -83,15 +84,20 @@ func (r *fileRepository) List(ctx context.Context) ([]domain.File, error) {
 }
 
 func (r *fileRepository) Delete(ctx context.Context, id uint) error {
-    return r.db.Delete(&domain.File{}, id).Error
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec("DELETE FROM collection_files WHERE file_id = ?", id).Error; err != nil {
+            return err
+        }
+        return tx.Delete(&domain.File{}, id).Error
+    })
 }
 
-func (r *fileRepository) SearchFiles(ctx context.Context, userID uint, searchTerm string) ([]domain.File, error) {
+func (r *fileRepository) SearchFiles(ctx context.Context, scope domain.FileScope, searchTerm string) ([]domain.File, error) {
     var files []domain.File
     
     log.Printf("Searching for term: %s", searchTerm)
     
-    query := r.db.Where("user_id = ?", userID)
+    query := r.scoped(scope)
     
     if searchTerm != "" {
         searchPattern := "%" + searchTerm + "%"
@@ -111,6 +117,31 @@ func (r *fileRepository) SearchFiles(ctx context.Context, userID uint, searchTer
     return files, nil
 }
 
+func (r *fileRepository) GetStorageUsage(ctx context.Context, scope domain.FileScope) (*domain.StorageUsage, error) {
+    var usage domain.StorageUsage
+    err := r.scoped(scope).Model(&domain.File{}).
+        Select("COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes").
+        Scan(&usage).Error
+    if err != nil {
+        return nil, domain.NewAPIError(
+            500,
+            domain.ErrCodeInternal,
+            "Failed to get storage usage",
+            err,
+        )
+    }
+    return &usage, nil
+}
+
+// scoped restricts a query to the files in scope. A user's personal files
+// leave out the ones they uploaded to an organization.
+func (r *fileRepository) scoped(scope domain.FileScope) *gorm.DB {
+    if scope.OrganizationID != 0 {
+        return r.db.Where("organization_id = ?", scope.OrganizationID)
+    }
+    return r.db.Where("user_id = ? AND organization_id IS NULL", scope.UserID)
+}
+
 func (r *fileRepository) UpdateShareableID(ctx context.Context, fileID uint, shareableID string) error {
     result := r.db.Model(&domain.File{}).
         Where("id = ?", fileID).
@@ -132,6 +163,74 @@ func (r *fileRepository) UpdateShareableID(ctx context.Context, fileID uint, sha
     return nil
 }
 
+func (r *fileRepository) UpdateShareExpiry(ctx context.Context, fileID uint, expiresAt *time.Time) error {
+    result := r.db.Model(&domain.File{}).
+        Where("id = ?", fileID).
+        Update("share_expires_at", expiresAt)
+
+    if result.Error != nil {
+        return domain.NewAPIError(
+            500,
+            domain.ErrCodeInternal,
+            "Failed to update share expiry",
+            result.Error,
+        )
+    }
+
+    if result.RowsAffected == 0 {
+        return domain.ErrFileNotFound
+    }
+
+    return nil
+}
+
+func (r *fileRepository) SetQuarantined(ctx context.Context, fileID uint, quarantined bool) error {
+    result := r.db.Model(&domain.File{}).
+        Where("id = ?", fileID).
+        Update("quarantined", quarantined)
+
+    if result.Error != nil {
+        return domain.NewAPIError(
+            500,
+            domain.ErrCodeInternal,
+            "Failed to update quarantine state",
+            result.Error,
+        )
+    }
+
+    if result.RowsAffected == 0 {
+        return domain.ErrFileNotFound
+    }
+
+    return nil
+}
+
+func (r *fileRepository) SetShareDisabled(ctx context.Context, fileID uint, disabled bool) error {
+    updates := map[string]interface{}{"share_disabled": disabled}
+    if disabled {
+        updates["shareable_id"] = ""
+    }
+
+    result := r.db.Model(&domain.File{}).
+        Where("id = ?", fileID).
+        Updates(updates)
+
+    if result.Error != nil {
+        return domain.NewAPIError(
+            500,
+            domain.ErrCodeInternal,
+            "Failed to update share state",
+            result.Error,
+        )
+    }
+
+    if result.RowsAffected == 0 {
+        return domain.ErrFileNotFound
+    }
+
+    return nil
+}
+
 func (r *fileRepository) GetFileByShareID(ctx context.Context, shareID string) (*domain.File, error) {
     var file domain.File
     result := r.db.Where("shareable_id = ?", shareID).First(&file)
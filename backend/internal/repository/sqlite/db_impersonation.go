@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepository(db *gorm.DB) interfaces.ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+func (r *impersonationRepository) Create(ctx context.Context, impersonation *domain.Impersonation) error {
+	if err := r.db.WithContext(ctx).Create(impersonation).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create impersonation session",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *impersonationRepository) GetByID(ctx context.Context, id string) (*domain.Impersonation, error) {
+	var impersonation domain.Impersonation
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&impersonation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrImpersonationNotFound
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get impersonation session",
+			err,
+		)
+	}
+	return &impersonation, nil
+}
+
+func (r *impersonationRepository) End(ctx context.Context, id string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.Impersonation{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", at).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to end impersonation session",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *impersonationRepository) List(ctx context.Context, limit int) ([]domain.Impersonation, error) {
+	var impersonations []domain.Impersonation
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&impersonations).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list impersonation sessions",
+			err,
+		)
+	}
+	return impersonations, nil
+}
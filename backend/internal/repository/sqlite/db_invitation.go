@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) interfaces.InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	if err := r.db.WithContext(ctx).Create(invitation).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create invitation",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *invitationRepository) List(ctx context.Context, limit int) ([]domain.Invitation, error) {
+	var invitations []domain.Invitation
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&invitations).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list invitations",
+			err,
+		)
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Invitation{}, id)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to delete invitation",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (r *invitationRepository) ClaimCode(ctx context.Context, codeHash, email string, at time.Time) (*domain.Invitation, error) {
+	return r.claim(ctx, at, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("code_hash = ? AND (email = '' OR email = ?)", codeHash, email)
+	})
+}
+
+func (r *invitationRepository) ClaimForEmail(ctx context.Context, email string, at time.Time) (*domain.Invitation, error) {
+	return r.claim(ctx, at, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("email = ?", email)
+	})
+}
+
+// claim accepts the oldest pending, unexpired invitation matching scope. The
+// update only succeeds while the invitation is still pending, so two
+// sign-ups racing for the same one cannot both have it.
+func (r *invitationRepository) claim(ctx context.Context, at time.Time, scope func(*gorm.DB) *gorm.DB) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := scope(tx).
+			Where("accepted_at IS NULL AND expires_at > ?", at).
+			Order("created_at").
+			First(&invitation).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrInvitationInvalid
+			}
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to get invitation",
+				err,
+			)
+		}
+
+		result := tx.Model(&domain.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", at)
+		if result.Error != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to accept invitation",
+				result.Error,
+			)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrInvitationInvalid
+		}
+		invitation.AcceptedAt = &at
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) Release(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.Invitation{}).
+		Where("id = ? AND accepted_by IS NULL", id).
+		Update("accepted_at", nil).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to release invitation",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *invitationRepository) SetAcceptedBy(ctx context.Context, id, userID uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.Invitation{}).
+		Where("id = ?", id).
+		Update("accepted_by", userID).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to record invitation acceptance",
+			err,
+		)
+	}
+	return nil
+}
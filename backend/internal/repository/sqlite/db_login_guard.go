@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type loginGuardRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginGuardRepository(db *gorm.DB) interfaces.LoginGuardRepository {
+	return &loginGuardRepository{db: db}
+}
+
+func (r *loginGuardRepository) GetThrottles(ctx context.Context, keys []string) ([]domain.LoginThrottle, error) {
+	var throttles []domain.LoginThrottle
+	if err := r.db.WithContext(ctx).Where("key IN ?", keys).Find(&throttles).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get login throttles",
+			err,
+		)
+	}
+	return throttles, nil
+}
+
+func (r *loginGuardRepository) UpdateThrottle(ctx context.Context, key string, update func(*domain.LoginThrottle)) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var throttle domain.LoginThrottle
+		err := tx.Where("key = ?", key).First(&throttle).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to get login throttle",
+				err,
+			)
+		}
+		throttle.Key = key
+
+		update(&throttle)
+		if err := tx.Save(&throttle).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to save login throttle",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *loginGuardRepository) DeleteThrottle(ctx context.Context, key string) error {
+	if err := r.db.WithContext(ctx).Where("key = ?", key).Delete(&domain.LoginThrottle{}).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to clear login throttle",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *loginGuardRepository) RecordAttempt(ctx context.Context, attempt *domain.LoginAttempt) error {
+	if err := r.db.WithContext(ctx).Create(attempt).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to record login attempt",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *loginGuardRepository) ListAttempts(ctx context.Context, filter domain.LoginAttemptFilter) ([]domain.LoginAttempt, error) {
+	var attempts []domain.LoginAttempt
+	query := r.db.WithContext(ctx).Order("created_at DESC").Limit(filter.Limit)
+	if filter.Email != "" {
+		query = query.Where("email = ?", filter.Email)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if err := query.Find(&attempts).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list login attempts",
+			err,
+		)
+	}
+	return attempts, nil
+}
+
+func (r *loginGuardRepository) RecordLockout(ctx context.Context, event *domain.LockoutEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to record lockout",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *loginGuardRepository) ListLockouts(ctx context.Context, limit int) ([]domain.LockoutEvent, error) {
+	var events []domain.LockoutEvent
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list lockouts",
+			err,
+		)
+	}
+	return events, nil
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) interfaces.MFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) GetTOTP(ctx context.Context, userID uint) (*domain.TOTPCredential, error) {
+	var credential domain.TOTPCredential
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&credential).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get TOTP enrollment",
+			err,
+		)
+	}
+	return &credential, nil
+}
+
+func (r *mfaRepository) SaveTOTP(ctx context.Context, credential *domain.TOTPCredential) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled", "last_step", "updated_at"}),
+	}).Create(credential).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to save TOTP enrollment",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *mfaRepository) DeleteTOTP(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to delete recovery codes",
+				err,
+			)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.TOTPCredential{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to delete TOTP enrollment",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *mfaRepository) AdvanceTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.TOTPCredential{}).
+		Where("user_id = ? AND last_step < ?", userID, step).
+		Update("last_step", step)
+	if result.Error != nil {
+		return false, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to record TOTP use",
+			result.Error,
+		)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to delete recovery codes",
+				err,
+			)
+		}
+
+		codes := make([]domain.RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = domain.RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		if err := tx.Create(&codes).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create recovery codes",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to use recovery code",
+			result.Error,
+		)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *mfaRepository) CountRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to count recovery codes",
+			err,
+		)
+	}
+	return count, nil
+}
+
+// CreateChallenge also prunes challenges that have expired.
+func (r *mfaRepository) CreateChallenge(ctx context.Context, challenge *domain.MFAChallenge) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now().UTC()).Delete(&domain.MFAChallenge{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to prune login challenges",
+				err,
+			)
+		}
+		if err := tx.Create(challenge).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create login challenge",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *mfaRepository) GetChallenge(ctx context.Context, hash string) (*domain.MFAChallenge, error) {
+	var challenge domain.MFAChallenge
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&challenge).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvalidMFAChallenge
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get login challenge",
+			err,
+		)
+	}
+	return &challenge, nil
+}
+
+func (r *mfaRepository) RecordChallengeAttempt(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.MFAChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update login challenge",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *mfaRepository) DeleteChallenge(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&domain.MFAChallenge{}, id).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to delete login challenge",
+			err,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCRepository(db *gorm.DB) interfaces.OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+// CreateState also prunes states that have expired.
+func (r *oidcRepository) CreateState(ctx context.Context, state *domain.OIDCLoginState) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now().UTC()).Delete(&domain.OIDCLoginState{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to prune sign-in states",
+				err,
+			)
+		}
+		if err := tx.Create(state).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create sign-in state",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *oidcRepository) ConsumeState(ctx context.Context, hash string) (*domain.OIDCLoginState, error) {
+	var state domain.OIDCLoginState
+	if err := r.consume(ctx, hash, &state, domain.ErrInvalidOIDCState); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *oidcRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get linked identity",
+			err,
+		)
+	}
+	return &identity, nil
+}
+
+func (r *oidcRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	if err := r.db.WithContext(ctx).Create(identity).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to link identity",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *oidcRepository) TouchIdentity(ctx context.Context, id uint, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.UserIdentity{}).
+		Where("id = ?", id).
+		Update("last_login_at", at).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update linked identity",
+			err,
+		)
+	}
+	return nil
+}
+
+// CreateTicket also prunes tickets that have expired.
+func (r *oidcRepository) CreateTicket(ctx context.Context, ticket *domain.OIDCLoginTicket) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now().UTC()).Delete(&domain.OIDCLoginTicket{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to prune sign-in tickets",
+				err,
+			)
+		}
+		if err := tx.Create(ticket).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create sign-in ticket",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *oidcRepository) ConsumeTicket(ctx context.Context, hash string) (*domain.OIDCLoginTicket, error) {
+	var ticket domain.OIDCLoginTicket
+	if err := r.consume(ctx, hash, &ticket, domain.ErrInvalidOIDCTicket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// consume loads the unexpired single-use row whose hash column matches and
+// deletes it. The delete decides a race between two concurrent uses.
+func (r *oidcRepository) consume(ctx context.Context, hash string, dest interface{}, invalid error) error {
+	column := "token_hash"
+	if _, ok := dest.(*domain.OIDCLoginState); ok {
+		column = "state_hash"
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(column+" = ? AND expires_at > ?", hash, time.Now().UTC()).First(dest).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid
+		}
+		if err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to load sign-in attempt",
+				err,
+			)
+		}
+
+		result := tx.Where(column+" = ?", hash).Delete(dest)
+		if result.Error != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to consume sign-in attempt",
+				result.Error,
+			)
+		}
+		if result.RowsAffected == 0 {
+			return invalid
+		}
+		return nil
+	})
+}
@@ -0,0 +1,357 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) interfaces.OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *domain.Organization, owner *domain.OrganizationMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create organization",
+				err,
+			)
+		}
+
+		owner.OrganizationID = org.ID
+		if err := tx.Create(owner).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to add organization owner",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id uint) (*domain.Organization, error) {
+	var org domain.Organization
+	if err := r.db.WithContext(ctx).First(&org, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("Organization")
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get organization",
+			err,
+		)
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	result := r.db.WithContext(ctx).Model(org).Select("Name", "StorageQuota").Updates(org)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update organization",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("Organization")
+	}
+	return nil
+}
+
+func (r *organizationRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var files int64
+		if err := tx.Model(&domain.File{}).Where("organization_id = ?", id).Count(&files).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to count organization files",
+				err,
+			)
+		}
+		if files > 0 {
+			return domain.NewAPIError(
+				409,
+				domain.ErrCodeConflict,
+				"Delete the organization's files first",
+				nil,
+			)
+		}
+
+		if err := tx.Where("organization_id = ?", id).Delete(&domain.OrganizationMember{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to remove organization members",
+				err,
+			)
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&domain.OrganizationInvitation{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to remove organization invitations",
+				err,
+			)
+		}
+
+		result := tx.Delete(&domain.Organization{}, id)
+		if result.Error != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to delete organization",
+				result.Error,
+			)
+		}
+		if result.RowsAffected == 0 {
+			return domain.NewNotFoundError("Organization")
+		}
+		return nil
+	})
+}
+
+func (r *organizationRepository) ListForUser(ctx context.Context, userID uint) ([]domain.Organization, error) {
+	var rows []struct {
+		domain.Organization
+		MemberRole domain.OrgRole
+	}
+	err := r.db.WithContext(ctx).
+		Table("organizations").
+		Select("organizations.*, organization_members.role AS member_role").
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list organizations",
+			err,
+		)
+	}
+
+	orgs := make([]domain.Organization, len(rows))
+	for i, row := range rows {
+		orgs[i] = row.Organization
+		orgs[i].Role = row.MemberRole
+	}
+	return orgs, nil
+}
+
+func (r *organizationRepository) GetMember(ctx context.Context, orgID, userID uint) (*domain.OrganizationMember, error) {
+	var member domain.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrMemberNotFound
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get organization member",
+			err,
+		)
+	}
+	return &member, nil
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID uint) ([]domain.OrganizationMember, error) {
+	var members []domain.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at").
+		Find(&members).Error
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list organization members",
+			err,
+		)
+	}
+	return members, nil
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, member *domain.OrganizationMember) error {
+	result := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", member.OrganizationID, member.UserID).
+		FirstOrCreate(member)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to add organization member",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewAPIError(
+			409,
+			domain.ErrCodeConflict,
+			"User is already a member",
+			nil,
+		)
+	}
+	return nil
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uint, role domain.OrgRole) error {
+	result := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update member role",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&domain.OrganizationMember{})
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to remove organization member",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) SaveInvitation(ctx context.Context, invitation *domain.OrganizationInvitation) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by", "expires_at", "created_at"}),
+	}).Create(invitation).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to save invitation",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *organizationRepository) GetInvitation(ctx context.Context, id uint) (*domain.OrganizationInvitation, error) {
+	var invitation domain.OrganizationInvitation
+	if err := r.db.WithContext(ctx).First(&invitation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrOrgInvitationNotFound
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get invitation",
+			err,
+		)
+	}
+	return &invitation, nil
+}
+
+func (r *organizationRepository) ListInvitations(ctx context.Context, orgID uint) ([]domain.OrganizationInvitation, error) {
+	var invitations []domain.OrganizationInvitation
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list invitations",
+			err,
+		)
+	}
+	return invitations, nil
+}
+
+func (r *organizationRepository) ListInvitationsForEmail(ctx context.Context, email string, now time.Time) ([]domain.OrganizationInvitation, error) {
+	var rows []struct {
+		domain.OrganizationInvitation
+		Name string
+	}
+	err := r.db.WithContext(ctx).
+		Table("organization_invitations").
+		Select("organization_invitations.*, organizations.name AS name").
+		Joins("JOIN organizations ON organizations.id = organization_invitations.organization_id").
+		Where("organization_invitations.email = ? AND organization_invitations.expires_at > ?", email, now).
+		Order("organization_invitations.created_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list invitations",
+			err,
+		)
+	}
+
+	invitations := make([]domain.OrganizationInvitation, len(rows))
+	for i, row := range rows {
+		invitations[i] = row.OrganizationInvitation
+		invitations[i].OrganizationName = row.Name
+	}
+	return invitations, nil
+}
+
+func (r *organizationRepository) DeleteInvitation(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.OrganizationInvitation{}, id)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to delete invitation",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrOrgInvitationNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) AcceptInvitation(ctx context.Context, id uint, member *domain.OrganizationMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := &organizationRepository{db: tx}
+		if err := repo.DeleteInvitation(ctx, id); err != nil {
+			return err
+		}
+		return repo.AddMember(ctx, member)
+	})
+}
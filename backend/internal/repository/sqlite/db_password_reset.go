@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) interfaces.PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create also prunes tokens that have expired.
+func (r *passwordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now().UTC()).Delete(&domain.PasswordResetToken{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to prune password reset tokens",
+				err,
+			)
+		}
+		if err := tx.Create(token).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create password reset token",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *passwordResetRepository) LatestCreatedAt(ctx context.Context, userID uint) (*time.Time, error) {
+	var token domain.PasswordResetToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get password reset token",
+			err,
+		)
+	}
+	return &token.CreatedAt, nil
+}
+
+func (r *passwordResetRepository) Consume(ctx context.Context, hash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrInvalidResetToken
+			}
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to get password reset token",
+				err,
+			)
+		}
+
+		// The used_at condition makes a concurrent second use lose the race.
+		result := tx.Model(&domain.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to consume password reset token",
+				result.Error,
+			)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrInvalidResetToken
+		}
+		token.UsedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *passwordResetRepository) Release(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.PasswordResetToken{}).
+		Where("id = ?", id).
+		Update("used_at", nil).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to release password reset token",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.PasswordResetToken{}).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to delete password reset tokens",
+			err,
+		)
+	}
+	return nil
+}
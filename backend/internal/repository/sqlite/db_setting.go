@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type settingRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingRepository(db *gorm.DB) interfaces.SettingRepository {
+	return &settingRepository{db: db}
+}
+
+func (r *settingRepository) Get(ctx context.Context, key string) (string, error) {
+	var setting domain.Setting
+	err := r.db.WithContext(ctx).Where("key = ?", key).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get setting",
+			err,
+		)
+	}
+	return setting.Value, nil
+}
+
+func (r *settingRepository) Set(ctx context.Context, key, value string) error {
+	setting := &domain.Setting{Key: key, Value: value}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(setting).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to save setting",
+			err,
+		)
+	}
+	return nil
+}
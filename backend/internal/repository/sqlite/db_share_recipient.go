@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type shareRecipientRepository struct {
+	db *gorm.DB
+}
+
+func NewShareRecipientRepository(db *gorm.DB) interfaces.ShareRecipientRepository {
+	return &shareRecipientRepository{db: db}
+}
+
+func (r *shareRecipientRepository) Create(ctx context.Context, recipient *domain.ShareRecipient) error {
+	if err := r.db.WithContext(ctx).Create(recipient).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create share recipient",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *shareRecipientRepository) Update(ctx context.Context, recipient *domain.ShareRecipient) error {
+	if err := r.db.WithContext(ctx).Save(recipient).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update share recipient",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *shareRecipientRepository) GetByFileID(ctx context.Context, fileID uint) ([]domain.ShareRecipient, error) {
+	var recipients []domain.ShareRecipient
+	if err := r.db.WithContext(ctx).Where("file_id = ?", fileID).Order("created_at DESC").Find(&recipients).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list share recipients",
+			err,
+		)
+	}
+	return recipients, nil
+}
+
+func (r *shareRecipientRepository) RecordAccess(ctx context.Context, fileID uint, token string) error {
+	result := r.db.WithContext(ctx).Model(&domain.ShareRecipient{}).
+		Where("file_id = ? AND token = ?", fileID, token).
+		Updates(map[string]interface{}{
+			"access_count":     gorm.Expr("access_count + 1"),
+			"last_accessed_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to record share access",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("Share recipient")
+	}
+	return nil
+}
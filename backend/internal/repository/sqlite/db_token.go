@@ -0,0 +1,281 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) interfaces.TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create refresh token",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *tokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get refresh token",
+			err,
+		)
+	}
+	return &token, nil
+}
+
+func (r *tokenRepository) RotateRefreshToken(ctx context.Context, old, next *domain.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to create refresh token",
+				err,
+			)
+		}
+
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":  time.Now().UTC(),
+				"replaced_by": next.ID,
+			})
+		if result.Error != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to rotate refresh token",
+				result.Error,
+			)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrRefreshTokenReused
+		}
+		return nil
+	})
+}
+
+func (r *tokenRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create session",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *tokenRepository) GetSession(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get session",
+			err,
+		)
+	}
+	return &session, nil
+}
+
+func (r *tokenRepository) ListActiveSessions(ctx context.Context, userID uint) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now().UTC()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list sessions",
+			err,
+		)
+	}
+	return sessions, nil
+}
+
+func (r *tokenRepository) TouchSession(ctx context.Context, id string, at, expiresAt time.Time, client domain.SessionClient) error {
+	updates := map[string]interface{}{"last_seen_at": at}
+	if !expiresAt.IsZero() {
+		updates["expires_at"] = expiresAt
+	}
+	if client.IP != "" {
+		updates["ip"] = client.IP
+	}
+	if client.UserAgent != "" {
+		updates["user_agent"] = client.UserAgent
+	}
+
+	err := r.db.WithContext(ctx).Model(&domain.Session{}).Where("id = ?", id).Updates(updates).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to update session",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeSession(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		err := tx.Model(&domain.Session{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error
+		if err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to revoke session",
+				err,
+			)
+		}
+
+		err = tx.Model(&domain.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error
+		if err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to revoke refresh tokens",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *tokenRepository) RevokeUserSessions(ctx context.Context, userID uint, keep string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		err := tx.Model(&domain.Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keep).
+			Update("revoked_at", now).Error
+		if err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to revoke sessions",
+				err,
+			)
+		}
+
+		err = tx.Model(&domain.RefreshToken{}).
+			Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", userID, keep).
+			Update("revoked_at", now).Error
+		if err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to revoke refresh tokens",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+// RevokeAccessToken also prunes entries whose tokens have expired anyway.
+func (r *tokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now().UTC()).Delete(&domain.RevokedToken{}).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to prune revoked tokens",
+				err,
+			)
+		}
+
+		revoked := &domain.RevokedToken{JTI: jti, ExpiresAt: expiresAt}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(revoked).Error; err != nil {
+			return domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to revoke token",
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to check token revocation",
+			err,
+		)
+	}
+	return count > 0, nil
+}
+
+func (r *tokenRepository) SetUserRevokedAt(ctx context.Context, userID uint, at time.Time) error {
+	revocation := &domain.UserRevocation{UserID: userID, RevokedAt: at}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at"}),
+	}).Create(revocation).Error
+	if err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to revoke user tokens",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *tokenRepository) GetUserRevokedAt(ctx context.Context, userID uint) (*time.Time, error) {
+	var revocation domain.UserRevocation
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&revocation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to check token revocation",
+			err,
+		)
+	}
+	return &revocation.RevokedAt, nil
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+)
+
+type uploadRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewUploadRequestRepository(db *gorm.DB) interfaces.UploadRequestRepository {
+	return &uploadRequestRepository{db: db}
+}
+
+func (r *uploadRequestRepository) Create(ctx context.Context, req *domain.UploadRequest) error {
+	if err := r.db.WithContext(ctx).Create(req).Error; err != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create upload request",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *uploadRequestRepository) GetByID(ctx context.Context, id uint) (*domain.UploadRequest, error) {
+	var req domain.UploadRequest
+	if err := r.db.WithContext(ctx).First(&req, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFoundError("Upload request")
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get upload request",
+			err,
+		)
+	}
+	return &req, nil
+}
+
+func (r *uploadRequestRepository) GetByToken(ctx context.Context, token string) (*domain.UploadRequest, error) {
+	var req domain.UploadRequest
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&req).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFoundError("Upload request")
+		}
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to get upload request",
+			err,
+		)
+	}
+	return &req, nil
+}
+
+func (r *uploadRequestRepository) GetByUserID(ctx context.Context, userID uint) ([]domain.UploadRequest, error) {
+	var reqs []domain.UploadRequest
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&reqs).Error; err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to list upload requests",
+			err,
+		)
+	}
+	return reqs, nil
+}
+
+func (r *uploadRequestRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.UploadRequest{}, id)
+	if result.Error != nil {
+		return domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to delete upload request",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("Upload request")
+	}
+	return nil
+}
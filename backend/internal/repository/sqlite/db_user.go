@@ -3,6 +3,8 @@ package sqlite
 
 import (
     "context"
+    "strings"
+    "time"
     "gorm.io/gorm"
     "tech-test/backend/internal/domain"
     "tech-test/backend/internal/repository/interfaces"
@@ -20,6 +22,9 @@ func NewUserRepository(db *gorm.DB) interfaces.UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
     if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+        if strings.Contains(err.Error(), "UNIQUE constraint failed: users.email") {
+            return domain.ErrDuplicateEmail
+        }
         return domain.NewAPIError(
             500,
             domain.ErrCodeInternal,
@@ -122,3 +127,68 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain
     }
     return &user, nil
 }
+
+func (r *userRepository) SetPassword(ctx context.Context, id uint, hash string, changeRequired bool) error {
+    return r.updateColumns(ctx, id, map[string]interface{}{
+        "password":                 hash,
+        "password_change_required": changeRequired,
+    }, "Failed to update password")
+}
+
+func (r *userRepository) SetPasswordChangeRequired(ctx context.Context, id uint, required bool) error {
+    return r.updateColumns(ctx, id, map[string]interface{}{
+        "password_change_required": required,
+    }, "Failed to update user")
+}
+
+func (r *userRepository) SetDisabled(ctx context.Context, id uint, disabledAt *time.Time, reason string) error {
+    return r.updateColumns(ctx, id, map[string]interface{}{
+        "disabled_at":     disabledAt,
+        "disabled_reason": reason,
+    }, "Failed to update user")
+}
+
+func (r *userRepository) SetEmailVerified(ctx context.Context, id uint, verifiedAt *time.Time) error {
+    return r.updateColumns(ctx, id, map[string]interface{}{
+        "email_verified_at": verifiedAt,
+    }, "Failed to update user")
+}
+
+func (r *userRepository) RecordLogin(ctx context.Context, id uint, at time.Time, ip string) error {
+    // Written without touching updated_at, which tracks profile changes.
+    result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).
+        UpdateColumns(map[string]interface{}{
+            "last_login_at": at,
+            "last_login_ip": ip,
+        })
+    if result.Error != nil {
+        return domain.NewAPIError(
+            500,
+            domain.ErrCodeInternal,
+            "Failed to record login",
+            result.Error,
+        )
+    }
+    if result.RowsAffected == 0 {
+        return domain.ErrUserNotFound
+    }
+    return nil
+}
+
+// updateColumns sets columns on the user, including zero values, which
+// Update skips.
+func (r *userRepository) updateColumns(ctx context.Context, id uint, columns map[string]interface{}, message string) error {
+    result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(columns)
+    if result.Error != nil {
+        return domain.NewAPIError(
+            500,
+            domain.ErrCodeInternal,
+            message,
+            result.Error,
+        )
+    }
+    if result.RowsAffected == 0 {
+        return domain.ErrUserNotFound
+    }
+    return nil
+}
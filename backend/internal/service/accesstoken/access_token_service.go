@@ -0,0 +1,172 @@
+package accesstoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	accessTokenInterface "tech-test/backend/internal/service/interfaces/accesstoken"
+	authzInterface "tech-test/backend/internal/service/interfaces/authz"
+)
+
+const (
+	maxNameLength = 100
+	// lastUsedResolution limits how often LastUsedAt is written for a token
+	// that is used in quick succession.
+	lastUsedResolution = time.Minute
+)
+
+type service struct {
+	repo       interfaces.AccessTokenRepository
+	userRepo   interfaces.UserRepository
+	authorizer authzInterface.Authorizer
+	logger     *zap.Logger
+}
+
+func NewService(
+	repo interfaces.AccessTokenRepository,
+	userRepo interfaces.UserRepository,
+	authorizer authzInterface.Authorizer,
+	logger *zap.Logger,
+) accessTokenInterface.Service {
+	return &service{
+		repo:       repo,
+		userRepo:   userRepo,
+		authorizer: authorizer,
+		logger:     logger,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uint, req domain.CreateAccessTokenRequest) (*domain.CreatedAccessToken, error) {
+	s.logger.Debug("Creating access token",
+		zap.Uint("userID", userID),
+		zap.String("name", req.Name))
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, domain.NewInvalidInputError("name is required and must be at most 100 characters")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, domain.NewInvalidInputError("at least one scope is required")
+	}
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[domain.Scope]bool)
+	for _, scope := range req.Scopes {
+		if !scope.Valid() {
+			return nil, domain.NewInvalidInputError("unknown scope: " + string(scope))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, string(scope))
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, domain.NewInvalidInputError("expiresAt must be in the future")
+	}
+
+	raw, err := generateToken()
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to generate access token",
+			err,
+		)
+	}
+
+	token := &domain.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:len(domain.AccessTokenPrefix)+6],
+		TokenHash: hashToken(raw),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAccessToken{
+		AccessTokenInfo: token.ToInfo(),
+		Token:           raw,
+	}, nil
+}
+
+func (s *service) ListByUser(ctx context.Context, userID uint) ([]domain.AccessTokenInfo, error) {
+	s.logger.Debug("Listing access tokens", zap.Uint("userID", userID))
+
+	tokens, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]domain.AccessTokenInfo, len(tokens))
+	for i := range tokens {
+		infos[i] = tokens[i].ToInfo()
+	}
+	return infos, nil
+}
+
+func (s *service) Revoke(ctx context.Context, id uint) error {
+	s.logger.Debug("Revoking access token", zap.Uint("id", id))
+
+	token, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionDelete, token); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) Authenticate(ctx context.Context, raw string) (*domain.PersonalAccessToken, error) {
+	token, err := s.repo.GetByHash(ctx, hashToken(raw))
+	if err != nil {
+		var apiErr *domain.APIError
+		if errors.As(err, &apiErr) && apiErr.Code == domain.ErrCodeNotFound {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if token.IsExpired() {
+		return nil, domain.ErrUnauthorized
+	}
+	if _, err := s.userRepo.GetByID(ctx, token.UserID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			s.logger.Warn("Failed to record access token use",
+				zap.Uint("tokenID", token.ID),
+				zap.Error(err))
+		}
+		token.LastUsedAt = &now
+	}
+	return token, nil
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return domain.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
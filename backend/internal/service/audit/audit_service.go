@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	auditInterface "tech-test/backend/internal/service/interfaces/audit"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+type service struct {
+	repo   interfaces.AuditRepository
+	logger *zap.Logger
+}
+
+func NewService(repo interfaces.AuditRepository, logger *zap.Logger) auditInterface.Service {
+	return &service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *service) Record(ctx context.Context, event *domain.AuditEvent) {
+	if err := s.repo.Record(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", string(event.Action)),
+			zap.Uint("actorID", event.ActorID),
+			zap.Uint("subjectID", event.SubjectID),
+			zap.Error(err))
+	}
+}
+
+func (s *service) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = defaultListLimit
+	case filter.Limit > maxListLimit:
+		filter.Limit = maxListLimit
+	}
+	return s.repo.List(ctx, filter)
+}
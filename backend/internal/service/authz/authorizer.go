@@ -0,0 +1,156 @@
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	authzInterface "tech-test/backend/internal/service/interfaces/authz"
+)
+
+// adminActions are what administrators may do with resources they do not own.
+var adminActions = map[domain.Action]bool{
+	domain.ActionRead:     true,
+	domain.ActionDownload: true,
+	domain.ActionDelete:   true,
+	domain.ActionListAll:  true,
+}
+
+// memberActions are what organization members may do with any of the
+// organization's resources. Owners and admins may do everything, and
+// members may also change or delete what they uploaded themselves.
+var memberActions = map[domain.Action]bool{
+	domain.ActionRead:     true,
+	domain.ActionDownload: true,
+	domain.ActionCreate:   true,
+	domain.ActionShare:    true,
+}
+
+// actionScopes are the access token scopes each action requires.
+var actionScopes = map[domain.Action]domain.Scope{
+	domain.ActionRead:     domain.ScopeFilesRead,
+	domain.ActionDownload: domain.ScopeFilesRead,
+	domain.ActionListAll:  domain.ScopeFilesRead,
+	domain.ActionCreate:   domain.ScopeFilesWrite,
+	domain.ActionUpdate:   domain.ScopeFilesWrite,
+	domain.ActionDelete:   domain.ScopeFilesWrite,
+	domain.ActionShare:    domain.ScopeShare,
+}
+
+type authorizer struct {
+	orgs   interfaces.OrganizationRepository
+	logger *zap.Logger
+}
+
+func NewAuthorizer(orgs interfaces.OrganizationRepository, logger *zap.Logger) authzInterface.Authorizer {
+	return &authorizer{
+		orgs:   orgs,
+		logger: logger,
+	}
+}
+
+func (a *authorizer) Can(ctx context.Context, actor domain.Actor, action domain.Action, resource domain.Resource) error {
+	if actor.UserID == 0 {
+		return domain.ErrUnauthorized
+	}
+
+	if err := a.check(ctx, actor, action, resource); err != nil {
+		a.logger.Debug("Authorization denied",
+			zap.Uint("userID", actor.UserID),
+			zap.String("action", string(action)),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (a *authorizer) check(ctx context.Context, actor domain.Actor, action domain.Action, resource domain.Resource) error {
+	if !actor.HasScope(actionScopes[action]) {
+		return domain.ErrInsufficientScope
+	}
+
+	if resource == nil {
+		if action == domain.ActionListAll && actor.Admin {
+			return nil
+		}
+		return domain.ErrForbidden
+	}
+
+	if file, ok := resource.(*domain.File); ok {
+		if file.Quarantined && (action == domain.ActionDownload || action == domain.ActionShare) {
+			return domain.ErrFileQuarantined
+		}
+		if file.ShareDisabled && action == domain.ActionShare {
+			return domain.ErrShareDisabled
+		}
+	}
+
+	if org, ok := resource.(domain.OrganizationResource); ok && org.Organization() != 0 {
+		return a.checkOrganization(ctx, actor, action, resource, org.Organization())
+	}
+
+	if resource.OwnerID() == actor.UserID {
+		return nil
+	}
+
+	if actor.Admin {
+		if adminActions[action] {
+			return nil
+		}
+		return domain.ErrForbidden
+	}
+
+	return notFound(resource)
+}
+
+// checkOrganization decides access to an organization's resources by the
+// actor's role in it. Uploaders who have left the organization lose access
+// to what they uploaded.
+func (a *authorizer) checkOrganization(ctx context.Context, actor domain.Actor, action domain.Action, resource domain.Resource, orgID uint) error {
+	member, err := a.orgs.GetMember(ctx, orgID, actor.UserID)
+	if err != nil && !errors.Is(err, domain.ErrMemberNotFound) {
+		return err
+	}
+
+	if member != nil {
+		if member.Role.CanManage() || memberActions[action] || resource.OwnerID() == actor.UserID {
+			return nil
+		}
+		return domain.ErrForbidden
+	}
+
+	if actor.Admin {
+		if adminActions[action] {
+			return nil
+		}
+		return domain.ErrForbidden
+	}
+
+	// A file being uploaded does not exist yet; what the actor cannot see is
+	// the organization.
+	if action == domain.ActionCreate {
+		return notFound(domain.InOrganization(orgID))
+	}
+	return notFound(resource)
+}
+
+// notFound mirrors the error the repositories return for a missing resource,
+// so a denied lookup is indistinguishable from a nonexistent one.
+func notFound(resource domain.Resource) error {
+	switch resource.(type) {
+	case *domain.File:
+		return domain.ErrFileNotFound
+	case *domain.Collection:
+		return domain.NewNotFoundError("Collection")
+	case *domain.UploadRequest:
+		return domain.NewNotFoundError("Upload request")
+	case *domain.PersonalAccessToken:
+		return domain.NewNotFoundError("Access token")
+	case domain.InOrganization:
+		return domain.NewNotFoundError("Organization")
+	default:
+		return domain.ErrNotFound
+	}
+}
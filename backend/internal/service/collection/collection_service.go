@@ -0,0 +1,168 @@
+package collection
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	authzInterface "tech-test/backend/internal/service/interfaces/authz"
+	collectionInterface "tech-test/backend/internal/service/interfaces/collection"
+)
+
+type service struct {
+	repo       interfaces.CollectionRepository
+	fileRepo   interfaces.FileRepository
+	authorizer authzInterface.Authorizer
+	logger     *zap.Logger
+}
+
+func NewService(repo interfaces.CollectionRepository, fileRepo interfaces.FileRepository, authorizer authzInterface.Authorizer, logger *zap.Logger) collectionInterface.Service {
+	return &service{
+		repo:       repo,
+		fileRepo:   fileRepo,
+		authorizer: authorizer,
+		logger:     logger,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uint, req domain.CollectionRequest) (*domain.Collection, error) {
+	s.logger.Debug("Creating collection",
+		zap.Uint("userID", userID),
+		zap.String("name", req.Name))
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, domain.NewInvalidInputError("name is required")
+	}
+
+	files, err := s.shareableFiles(ctx, req.FileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &domain.Collection{
+		UserID: userID,
+		Name:   name,
+		Files:  files,
+	}
+	if err := s.repo.Create(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uint) (*domain.Collection, error) {
+	s.logger.Debug("Getting collection by ID", zap.Uint("id", id))
+	return s.load(ctx, id, domain.ActionRead)
+}
+
+func (s *service) ListByUser(ctx context.Context, userID uint) ([]domain.Collection, error) {
+	s.logger.Debug("Listing collections", zap.Uint("userID", userID))
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+func (s *service) Update(ctx context.Context, id uint, req domain.CollectionRequest) (*domain.Collection, error) {
+	s.logger.Debug("Updating collection", zap.Uint("id", id))
+
+	collection, err := s.load(ctx, id, domain.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		collection.Name = name
+	}
+
+	files, err := s.shareableFiles(ctx, req.FileIDs)
+	if err != nil {
+		return nil, err
+	}
+	collection.Files = files
+
+	if err := s.repo.Update(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uint) error {
+	s.logger.Debug("Deleting collection", zap.Uint("id", id))
+
+	if _, err := s.load(ctx, id, domain.ActionDelete); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) Share(ctx context.Context, id uint) (string, error) {
+	s.logger.Debug("Sharing collection", zap.Uint("id", id))
+
+	if _, err := s.load(ctx, id, domain.ActionShare); err != nil {
+		return "", err
+	}
+
+	shareableID := uuid.New().String()
+	if err := s.repo.UpdateShareableID(ctx, id, shareableID); err != nil {
+		return "", err
+	}
+	return shareableID, nil
+}
+
+func (s *service) GetByShareID(ctx context.Context, shareID string) (*domain.Collection, error) {
+	s.logger.Debug("Getting collection by share ID", zap.String("shareId", shareID))
+	if shareID == "" {
+		return nil, domain.NewNotFoundError("Collection")
+	}
+
+	collection, err := s.repo.GetByShareID(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quarantined files and files whose sharing was disabled stay in the
+	// collection but are never served publicly.
+	visible := collection.Files[:0]
+	for _, f := range collection.Files {
+		if !f.Quarantined && !f.ShareDisabled {
+			visible = append(visible, f)
+		}
+	}
+	collection.Files = visible
+	return collection, nil
+}
+
+func (s *service) load(ctx context.Context, id uint, action domain.Action) (*domain.Collection, error) {
+	collection, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), action, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// shareableFiles loads the given files, rejecting any the actor may not share.
+func (s *service) shareableFiles(ctx context.Context, fileIDs []uint) ([]domain.File, error) {
+	files := make([]domain.File, 0, len(fileIDs))
+	seen := make(map[uint]bool, len(fileIDs))
+	for _, id := range fileIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		file, err := s.fileRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionShare, file); err != nil {
+			return nil, err
+		}
+		files = append(files, *file)
+	}
+	return files, nil
+}
@@ -0,0 +1,202 @@
+package emailverification
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/repository/interfaces"
+	emailVerificationInterface "tech-test/backend/internal/service/interfaces/emailverification"
+	userInterface "tech-test/backend/internal/service/interfaces/user"
+)
+
+// resendInterval limits how often a user can ask for another link.
+const resendInterval = time.Minute
+
+type service struct {
+	repo      interfaces.EmailVerificationRepository
+	userRepo  interfaces.UserRepository
+	users     userInterface.UserWriter
+	mailer    mailer.Mailer
+	verifyURL string
+	ttl       time.Duration
+	required  bool
+	logger    *zap.Logger
+}
+
+// NewService creates the email verification service. verifyURL is the
+// frontend page that receives the token as a "token" query parameter. When
+// required is false every user counts as verified, though links are still
+// sent. mailer may be nil, in which case no links can be sent. users
+// promotes bootstrap admins once they prove their address.
+func NewService(
+	repo interfaces.EmailVerificationRepository,
+	userRepo interfaces.UserRepository,
+	users userInterface.UserWriter,
+	mailer mailer.Mailer,
+	verifyURL string,
+	ttl time.Duration,
+	required bool,
+	logger *zap.Logger,
+) emailVerificationInterface.Service {
+	return &service{
+		repo:      repo,
+		userRepo:  userRepo,
+		users:     users,
+		mailer:    mailer,
+		verifyURL: verifyURL,
+		ttl:       ttl,
+		required:  required,
+		logger:    logger,
+	}
+}
+
+func (s *service) SendVerification(ctx context.Context, user *domain.User) error {
+	if user.IsEmailVerified() {
+		return nil
+	}
+	if s.mailer == nil {
+		return domain.NewAPIError(
+			503,
+			domain.ErrCodeInternal,
+			"Email delivery is not configured",
+			nil,
+		)
+	}
+
+	raw, err := randomToken()
+	if err != nil {
+		return domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to generate verification token", err)
+	}
+	token := &domain.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().UTC().Add(s.ttl),
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nPlease confirm that this is your email address by opening this link within %s:\n\n%s\n\n"+
+				"Until you do, you will not be able to upload or share files.\n",
+			user.FirstName, s.ttl, s.link(raw)),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return domain.NewAPIError(
+			502,
+			domain.ErrCodeInternal,
+			"Failed to send verification email",
+			err,
+		)
+	}
+
+	s.logger.Info("Sent email verification", zap.Uint("userID", user.ID))
+	return nil
+}
+
+func (s *service) Resend(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return domain.NewAPIError(
+			http.StatusConflict,
+			domain.ErrCodeConflict,
+			"Email address is already verified",
+			nil,
+		)
+	}
+
+	last, err := s.repo.LatestCreatedAt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if last != nil && time.Since(*last) < resendInterval {
+		return domain.NewAPIError(
+			http.StatusTooManyRequests,
+			domain.ErrCodeInvalidInput,
+			"A verification email was sent recently, please wait a minute before asking again",
+			nil,
+		)
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+func (s *service) Verify(ctx context.Context, token string) error {
+	if token == "" {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	consumed, err := s.repo.Consume(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, consumed.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrInvalidVerificationToken
+		}
+		return err
+	}
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.Update(ctx, user.ID, user); err != nil {
+		return err
+	}
+
+	s.logger.Info("Email address verified", zap.Uint("userID", user.ID))
+	if err := s.users.PromoteBootstrapAdmin(ctx, user.ID); err != nil {
+		s.logger.Error("Failed to promote bootstrap admin", zap.Uint("userID", user.ID), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *service) IsEmailVerified(ctx context.Context, userID uint) (bool, error) {
+	if !s.required {
+		return true, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.IsEmailVerified(), nil
+}
+
+func (s *service) link(token string) string {
+	return s.verifyURL + "?token=" + url.QueryEscape(token)
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
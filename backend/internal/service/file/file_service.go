@@ -3,65 +3,123 @@ package file
 import (
 	"context"
 	"strconv"
+	"time"
 	"tech-test/backend/internal/domain"
 	"tech-test/backend/internal/repository/interfaces"
+	authzInterface "tech-test/backend/internal/service/interfaces/authz"
 	fileInterface "tech-test/backend/internal/service/interfaces/file"
+	"tech-test/backend/internal/utils"
 	"go.uber.org/zap"
 )
 
 type service struct {
-	repo      interfaces.FileRepository
-	logger    *zap.Logger
-	uploadDir string
+	repo       interfaces.FileRepository
+	orgs       interfaces.OrganizationRepository
+	authorizer authzInterface.Authorizer
+	logger     *zap.Logger
+	uploadDir  string
 }
 
-func NewService(repo interfaces.FileRepository, logger *zap.Logger, uploadDir string) fileInterface.Service {
+func NewService(repo interfaces.FileRepository, orgs interfaces.OrganizationRepository, authorizer authzInterface.Authorizer, logger *zap.Logger, uploadDir string) fileInterface.Service {
 	return &service{
-		repo:      repo,
-		logger:    logger,
-		uploadDir: uploadDir,
+		repo:       repo,
+		orgs:       orgs,
+		authorizer: authorizer,
+		logger:     logger,
+		uploadDir:  uploadDir,
 	}
 }
 
 func (s *service) GetByID(ctx context.Context, id uint) (*domain.File, error) {
 	s.logger.Debug("Getting file by ID", zap.Uint("id", id))
-	return s.repo.GetByID(ctx, id)
+	return s.load(ctx, id, domain.ActionRead)
 }
 
+func (s *service) GetForDownload(ctx context.Context, id uint) (*domain.File, error) {
+	s.logger.Debug("Getting file for download", zap.Uint("id", id))
+	return s.load(ctx, id, domain.ActionDownload)
+}
+
+// GetByShareID is not subject to authorization: holding the share ID is
+// what grants access.
 func (s *service) GetByShareID(ctx context.Context, shareID string) (*domain.File, error) {
 	s.logger.Debug("Getting file by share ID", zap.String("shareId", shareID))
-	return s.repo.GetFileByShareID(ctx, shareID)
+
+	file, err := s.repo.GetFileByShareID(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if file.IsShareExpired() {
+		return nil, domain.ErrLinkExpired
+	}
+	if file.Quarantined {
+		return nil, domain.ErrFileQuarantined
+	}
+	if file.ShareDisabled {
+		return nil, domain.ErrShareDisabled
+	}
+	return file, nil
 }
 
 func (s *service) List(ctx context.Context) ([]domain.File, error) {
 	s.logger.Debug("Listing all files")
+
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionListAll, nil); err != nil {
+		return nil, err
+	}
 	return s.repo.List(ctx)
 }
 
-func (s *service) GetUserFilesPaginated(ctx context.Context, userID uint, page, pageSize int) ([]domain.File, int64, error) {
-	s.logger.Debug("Getting paginated user files",
-		zap.Uint("userID", userID),
+func (s *service) GetFilesPaginated(ctx context.Context, scope domain.FileScope, page, pageSize int) ([]domain.File, int64, error) {
+	s.logger.Debug("Getting paginated files",
+		zap.Uint("userID", scope.UserID),
+		zap.Uint("organizationID", scope.OrganizationID),
 		zap.Int("page", page),
 		zap.Int("pageSize", pageSize))
-	return s.repo.GetUserFilesPaginated(ctx, userID, page, pageSize)
+
+	if err := s.authorizeListing(ctx, scope); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetFilesPaginated(ctx, scope, page, pageSize)
 }
 
-func (s *service) SearchFiles(ctx context.Context, userID uint, searchTerm string) ([]domain.File, error) {
+func (s *service) SearchFiles(ctx context.Context, scope domain.FileScope, searchTerm string) ([]domain.File, error) {
 	s.logger.Debug("Searching files",
-		zap.Uint("userID", userID),
+		zap.Uint("userID", scope.UserID),
+		zap.Uint("organizationID", scope.OrganizationID),
 		zap.String("searchTerm", searchTerm))
-	return s.repo.SearchFiles(ctx, userID, searchTerm)
+
+	if err := s.authorizeListing(ctx, scope); err != nil {
+		return nil, err
+	}
+	return s.repo.SearchFiles(ctx, scope, searchTerm)
 }
 
 func (s *service) Upload(ctx context.Context, file *domain.File) error {
 	s.logger.Debug("Uploading file",
 		zap.String("name", file.Name),
 		zap.Int64("size", file.Size))
+
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionCreate, file); err != nil {
+		return err
+	}
+	if file.OrganizationID != nil {
+		if err := s.checkQuota(ctx, *file.OrganizationID, file.Size); err != nil {
+			return err
+		}
+	}
+	if file.MimeType == "application/pdf" {
+		file.PageCount = utils.PDFPageCount(file.Path)
+	}
 	return s.repo.Create(ctx, file)
 }
 
 func (s *service) Delete(ctx context.Context, id uint) error {
 	s.logger.Debug("Deleting file", zap.Uint("id", id))
+
+	if _, err := s.load(ctx, id, domain.ActionDelete); err != nil {
+		return err
+	}
 	return s.repo.Delete(ctx, id)
 }
 
@@ -83,5 +141,58 @@ func (s *service) UpdateShareableID(ctx context.Context, fileID string, shareabl
 		)
 	}
 
+	if _, err := s.load(ctx, uint(id), domain.ActionShare); err != nil {
+		return err
+	}
 	return s.repo.UpdateShareableID(ctx, uint(id), shareableID)
 }
+
+func (s *service) UpdateShareExpiry(ctx context.Context, fileID uint, expiresAt *time.Time) error {
+	s.logger.Debug("Updating share expiry", zap.Uint("fileID", fileID))
+
+	if _, err := s.load(ctx, fileID, domain.ActionShare); err != nil {
+		return err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return domain.NewInvalidInputError("expiresAt must be in the future")
+	}
+	return s.repo.UpdateShareExpiry(ctx, fileID, expiresAt)
+}
+
+// load fetches a file and checks the actor in ctx may perform action on it.
+func (s *service) load(ctx context.Context, id uint, action domain.Action) (*domain.File, error) {
+	file, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), action, file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// authorizeListing lets users list their own files and their
+// organizations', and admins anyone's.
+func (s *service) authorizeListing(ctx context.Context, scope domain.FileScope) error {
+	return s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionRead, scope.Resource())
+}
+
+// checkQuota rejects an upload of size bytes that would take an
+// organization past its storage quota.
+func (s *service) checkQuota(ctx context.Context, orgID uint, size int64) error {
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if org.StorageQuota <= 0 {
+		return nil
+	}
+	usage, err := s.repo.GetStorageUsage(ctx, domain.OrganizationFiles(orgID))
+	if err != nil {
+		return err
+	}
+	if usage.Bytes+size > org.StorageQuota {
+		return domain.ErrStorageQuotaExceeded
+	}
+	return nil
+}
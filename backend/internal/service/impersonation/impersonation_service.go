@@ -0,0 +1,161 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	auditInterface "tech-test/backend/internal/service/interfaces/audit"
+	impersonationInterface "tech-test/backend/internal/service/interfaces/impersonation"
+	tokenInterface "tech-test/backend/internal/service/interfaces/token"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+	maxReasonLength  = 500
+)
+
+type service struct {
+	repo     interfaces.ImpersonationRepository
+	userRepo interfaces.UserRepository
+	tokens   tokenInterface.Service
+	audit    auditInterface.Service
+	cfg      config.ImpersonationConfig
+	logger   *zap.Logger
+}
+
+func NewService(repo interfaces.ImpersonationRepository, userRepo interfaces.UserRepository, tokens tokenInterface.Service, audit auditInterface.Service, cfg config.ImpersonationConfig, logger *zap.Logger) impersonationInterface.Service {
+	return &service{
+		repo:     repo,
+		userRepo: userRepo,
+		tokens:   tokens,
+		audit:    audit,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+func (s *service) Start(ctx context.Context, adminID, userID uint, req domain.StartImpersonationRequest, client domain.SessionClient) (*domain.ImpersonationToken, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, domain.NewInvalidInputError("reason is required")
+	}
+	if len(reason) > maxReasonLength {
+		return nil, domain.NewInvalidInputError("reason must be at most 500 characters")
+	}
+	if req.DurationMinutes < 0 {
+		return nil, domain.NewInvalidInputError("durationMinutes must be positive")
+	}
+	if adminID == userID {
+		return nil, domain.NewInvalidInputError("You cannot impersonate yourself")
+	}
+
+	admin, err := s.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin() {
+		return nil, domain.NewAPIError(
+			403,
+			domain.ErrCodeAuthorization,
+			"Administrators cannot be impersonated",
+			nil,
+		)
+	}
+
+	duration := s.cfg.DefaultDuration
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration > s.cfg.MaxDuration {
+		duration = s.cfg.MaxDuration
+	}
+
+	now := time.Now().UTC()
+	impersonation := &domain.Impersonation{
+		ID:               uuid.New().String(),
+		AdminID:          admin.ID,
+		UserID:           user.ID,
+		Reason:           reason,
+		AllowDestructive: req.AllowDestructive,
+		ExpiresAt:        now.Add(duration),
+	}
+	if err := s.repo.Create(ctx, impersonation); err != nil {
+		return nil, err
+	}
+
+	token, err := s.tokens.IssueImpersonation(ctx, impersonation, admin, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("Impersonation started",
+		zap.String("impersonationID", impersonation.ID),
+		zap.Uint("adminID", admin.ID),
+		zap.Uint("userID", user.ID),
+		zap.Bool("allowDestructive", impersonation.AllowDestructive),
+		zap.Duration("duration", duration))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:          domain.AuditImpersonationStarted,
+		ActorID:         admin.ID,
+		SubjectID:       user.ID,
+		ImpersonationID: impersonation.ID,
+		IP:              client.IP,
+		Details:         reason,
+	})
+
+	return &domain.ImpersonationToken{
+		AccessToken:   token,
+		ExpiresIn:     int64(duration.Seconds()),
+		Impersonation: impersonation,
+	}, nil
+}
+
+func (s *service) End(ctx context.Context, id string, endedBy uint) error {
+	impersonation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokens.RevokeSession(ctx, impersonation.UserID, impersonation.ID); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+		return err
+	}
+	if impersonation.EndedAt != nil {
+		return nil
+	}
+	if err := s.repo.End(ctx, id, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	s.logger.Info("Impersonation ended",
+		zap.String("impersonationID", id),
+		zap.Uint("endedBy", endedBy))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:          domain.AuditImpersonationEnded,
+		ActorID:         endedBy,
+		SubjectID:       impersonation.UserID,
+		ImpersonationID: id,
+	})
+	return nil
+}
+
+func (s *service) List(ctx context.Context, limit int) ([]domain.Impersonation, error) {
+	switch {
+	case limit <= 0:
+		limit = defaultListLimit
+	case limit > maxListLimit:
+		limit = maxListLimit
+	}
+	return s.repo.List(ctx, limit)
+}
@@ -0,0 +1,18 @@
+package accesstoken
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uint, req domain.CreateAccessTokenRequest) (*domain.CreatedAccessToken, error)
+
+	ListByUser(ctx context.Context, userID uint) ([]domain.AccessTokenInfo, error)
+
+	Revoke(ctx context.Context, id uint) error
+
+	// Authenticate resolves a raw token presented by a client, recording
+	// that it was used.
+	Authenticate(ctx context.Context, raw string) (*domain.PersonalAccessToken, error)
+}
@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+// Service keeps the audit trail of sensitive administrative activity.
+type Service interface {
+	// Record writes event to the audit trail. It is best-effort: a failure
+	// is logged rather than returned, so that it never undoes or fails the
+	// action being recorded.
+	Record(ctx context.Context, event *domain.AuditEvent)
+	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error)
+}
@@ -0,0 +1,15 @@
+package authz
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Authorizer interface {
+	// Can returns nil when actor may perform action on resource. Resources
+	// the actor may not even see yield the same not-found error a missing
+	// resource would, so their existence is not leaked; visible resources
+	// the actor may not act on yield domain.ErrForbidden. resource is nil for actions that are not tied to
+	// a single resource, such as ActionListAll.
+	Can(ctx context.Context, actor domain.Actor, action domain.Action, resource domain.Resource) error
+}
@@ -0,0 +1,22 @@
+package collection
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uint, req domain.CollectionRequest) (*domain.Collection, error)
+
+	GetByID(ctx context.Context, id uint) (*domain.Collection, error)
+
+	ListByUser(ctx context.Context, userID uint) ([]domain.Collection, error)
+
+	Update(ctx context.Context, id uint, req domain.CollectionRequest) (*domain.Collection, error)
+
+	Delete(ctx context.Context, id uint) error
+
+	Share(ctx context.Context, id uint) (string, error)
+
+	GetByShareID(ctx context.Context, shareID string) (*domain.Collection, error)
+}
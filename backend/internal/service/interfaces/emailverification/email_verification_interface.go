@@ -0,0 +1,22 @@
+package emailverification
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	// SendVerification emails user a link that confirms their address.
+	// Already verified users are skipped.
+	SendVerification(ctx context.Context, user *domain.User) error
+
+	// Resend issues a new link to the user, invalidating the previous one.
+	Resend(ctx context.Context, userID uint) error
+
+	// Verify consumes an emailed token and marks its user as verified.
+	Verify(ctx context.Context, token string) error
+
+	// IsEmailVerified reports whether the user has confirmed their address.
+	// It is always true when verification is not required.
+	IsEmailVerified(ctx context.Context, userID uint) (bool, error)
+}
@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"time"
 	"tech-test/backend/internal/domain"
 )
 
@@ -12,20 +13,30 @@ type Service interface {
 
 type FileReader interface {
 	GetByID(ctx context.Context, id uint) (*domain.File, error)
+
+	// GetForDownload is GetByID for callers about to serve the file contents.
+	GetForDownload(ctx context.Context, id uint) (*domain.File, error)
 	
 	GetByShareID(ctx context.Context, shareID string) (*domain.File, error)
 	
 	List(ctx context.Context) ([]domain.File, error)
 	
-	GetUserFilesPaginated(ctx context.Context, userID uint, page, pageSize int) ([]domain.File, int64, error)
+	// GetFilesPaginated lists a user's personal files or an organization's.
+	GetFilesPaginated(ctx context.Context, scope domain.FileScope, page, pageSize int) ([]domain.File, int64, error)
 	
-	SearchFiles(ctx context.Context, userID uint, searchTerm string) ([]domain.File, error)
+	SearchFiles(ctx context.Context, scope domain.FileScope, searchTerm string) ([]domain.File, error)
 }
 
 type FileWriter interface {
+	// Upload records a stored file. Files uploaded to an organization count
+	// against its storage quota.
 	Upload(ctx context.Context, file *domain.File) error
 	
 	Delete(ctx context.Context, id uint) error
 	
 	UpdateShareableID(ctx context.Context, fileID string, shareableID string) error
+
+	// UpdateShareExpiry sets when the file's public link stops working. A nil
+	// expiresAt makes the link permanent.
+	UpdateShareExpiry(ctx context.Context, fileID uint, expiresAt *time.Time) error
 } 
\ No newline at end of file
@@ -0,0 +1,16 @@
+package impersonation
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	// Start lets adminID act as userID for a limited time. Administrators
+	// cannot be impersonated.
+	Start(ctx context.Context, adminID, userID uint, req domain.StartImpersonationRequest, client domain.SessionClient) (*domain.ImpersonationToken, error)
+	// End stops an impersonation early; its token stops working at once.
+	End(ctx context.Context, id string, endedBy uint) error
+	List(ctx context.Context, limit int) ([]domain.Impersonation, error)
+}
@@ -0,0 +1,33 @@
+package invitation
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+// Service enforces the registration policy and manages the invitations
+// that get people past it.
+type Service interface {
+	Provisioner
+
+	Policy() domain.RegistrationPolicy
+
+	// Register creates a password account if the registration policy
+	// admits the user, or code names an invitation for them. An invitation
+	// sets the user's role and may add them to an organization.
+	Register(ctx context.Context, user *domain.User, code string) error
+
+	// Create issues an invitation, emailing it when it is addressed to
+	// someone and mail is configured. Admin only.
+	Create(ctx context.Context, req domain.CreateInvitationRequest) (*domain.CreatedInvitation, error)
+	List(ctx context.Context, limit int) ([]domain.Invitation, error)
+	Revoke(ctx context.Context, id uint) error
+}
+
+// Provisioner creates accounts for people an identity provider has vouched
+// for. As their address is verified, an invitation sent to it admits them
+// without the code.
+type Provisioner interface {
+	Provision(ctx context.Context, user *domain.User) error
+}
@@ -0,0 +1,26 @@
+package loginguard
+
+import (
+	"context"
+	"time"
+
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	// Check fails with domain.ErrTooManyLoginAttempts, and how long to
+	// wait, when the account or address is locked out or must slow down.
+	Check(ctx context.Context, email, ip string) (time.Duration, error)
+
+	RecordFailure(ctx context.Context, email, ip, reason string) error
+
+	// RecordSuccess clears the account's failures. The address keeps its
+	// count, so a valid login cannot reset an address that is guessing.
+	RecordSuccess(ctx context.Context, email, ip string, userID uint) error
+
+	ListAttempts(ctx context.Context, filter domain.LoginAttemptFilter) ([]domain.LoginAttempt, error)
+	ListLockouts(ctx context.Context, limit int) ([]domain.LockoutEvent, error)
+
+	// Clear lifts the lockout and delays on an account, an address or both.
+	Clear(ctx context.Context, req domain.ClearLockoutRequest) error
+}
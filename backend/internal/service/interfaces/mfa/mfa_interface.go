@@ -0,0 +1,46 @@
+package mfa
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	Status(ctx context.Context, userID uint) (*domain.MFAStatus, error)
+
+	// BeginEnrollment creates a pending authenticator for the user. It only
+	// takes effect once ConfirmEnrollment succeeds.
+	BeginEnrollment(ctx context.Context, userID uint) (*domain.TOTPEnrollment, error)
+
+	// ConfirmEnrollment enables 2FA and returns fresh recovery codes.
+	ConfirmEnrollment(ctx context.Context, userID uint, code string) ([]string, error)
+
+	// Disable turns 2FA off, given a current TOTP or recovery code.
+	Disable(ctx context.Context, userID uint, code string) error
+
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error)
+
+	// Reset removes a user's 2FA enrollment, for admins helping a user who
+	// lost their authenticator and recovery codes.
+	Reset(ctx context.Context, userID uint) error
+
+	IsRequired(ctx context.Context) (bool, error)
+
+	SetRequired(ctx context.Context, required bool) error
+
+	// BeginLogin returns a challenge when user must pass a second factor,
+	// or nil when the password alone is enough.
+	BeginLogin(ctx context.Context, user *domain.User) (*domain.LoginChallenge, error)
+
+	// EnrollWithChallenge lets a user who must enroll before logging in do
+	// so using their login challenge.
+	EnrollWithChallenge(ctx context.Context, challenge string) (*domain.TOTPEnrollment, error)
+
+	// ChallengeUser returns the user a live login challenge belongs to, so
+	// their login attempts can be throttled before a code is checked.
+	ChallengeUser(ctx context.Context, challenge string) (*domain.User, error)
+
+	// CompleteLogin checks the second factor for a challenge. Recovery codes
+	// are returned when the login also completed enrollment.
+	CompleteLogin(ctx context.Context, req domain.MFALoginRequest) (*domain.User, []string, error)
+}
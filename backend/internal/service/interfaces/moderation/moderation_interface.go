@@ -0,0 +1,21 @@
+package moderation
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	// Report files an abuse report against a public share link.
+	Report(ctx context.Context, shareID, reporterIP string, req domain.AbuseReportRequest) (*domain.AbuseReport, error)
+
+	ListReports(ctx context.Context, status string) ([]domain.AbuseReport, error)
+
+	// Resolve closes a report, applying the chosen moderation action and
+	// notifying the file owner when their file was affected.
+	Resolve(ctx context.Context, adminID, reportID uint, req domain.ResolveReportRequest) (*domain.AbuseReport, error)
+
+	// ReleaseFile lifts a quarantine placed on a file and lets it be shared
+	// again.
+	ReleaseFile(ctx context.Context, fileID uint) error
+}
@@ -0,0 +1,43 @@
+package organization
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+// Service manages organizations on behalf of the actor in ctx.
+type Service interface {
+	// Create makes a new organization with the caller as its owner.
+	Create(ctx context.Context, req domain.CreateOrganizationRequest) (*domain.Organization, error)
+	// List returns the organizations the caller belongs to.
+	List(ctx context.Context) ([]domain.Organization, error)
+	Get(ctx context.Context, id uint) (*domain.Organization, error)
+	GetStorageUsage(ctx context.Context, id uint) (*domain.StorageUsage, error)
+	Update(ctx context.Context, id uint, req domain.UpdateOrganizationRequest) (*domain.Organization, error)
+	// Delete is reserved to owners and fails while the organization still
+	// has files.
+	Delete(ctx context.Context, id uint) error
+
+	ListMembers(ctx context.Context, id uint) ([]domain.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, id, userID uint, role domain.OrgRole) error
+	// RemoveMember lets admins remove members and anyone leave. An
+	// organization always keeps at least one owner.
+	RemoveMember(ctx context.Context, id, userID uint) error
+
+	// InviteMember asks the holder of an email address to join. It succeeds
+	// alike whether or not the address has an account, and makes nobody a
+	// member until they accept. Owners and admins only.
+	InviteMember(ctx context.Context, id uint, req domain.InviteMemberRequest) (*domain.OrganizationInvitation, error)
+	ListInvitations(ctx context.Context, id uint) ([]domain.OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, id, invitationID uint) error
+
+	// ListMyInvitations, AcceptInvitation and DeclineInvitation act on the
+	// invitations sent to the caller's address, once they have verified it.
+	ListMyInvitations(ctx context.Context) ([]domain.OrganizationInvitation, error)
+	AcceptInvitation(ctx context.Context, invitationID uint) (*domain.Organization, error)
+	DeclineInvitation(ctx context.Context, invitationID uint) error
+
+	// SetStorageQuota is for site administrators.
+	SetStorageQuota(ctx context.Context, id uint, quota int64) error
+}
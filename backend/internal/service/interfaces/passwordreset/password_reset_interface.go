@@ -0,0 +1,23 @@
+package passwordreset
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	// RequestReset emails a reset link when an account exists for email,
+	// at most once a minute per account. The lookup and delivery happen in
+	// the background and it succeeds either way, so the endpoint cannot be
+	// used to discover which addresses are registered.
+	RequestReset(ctx context.Context, email string) error
+
+	// BeginForcedChange returns a reset token, without emailing it, for a
+	// user who logged in but must choose a new password first.
+	BeginForcedChange(ctx context.Context, user *domain.User) (*domain.PasswordChangeChallenge, error)
+
+	// ResetPassword consumes a token from RequestReset or BeginForcedChange, sets the new password and
+	// ends every session the user has.
+	ResetPassword(ctx context.Context, token, password string) error
+}
@@ -0,0 +1,27 @@
+package privacy
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+// Service answers data subject requests: exporting what is stored about a
+// user and erasing it.
+type Service interface {
+	// Export gathers everything stored about the user apart from the
+	// contents of their files, which are listed in it.
+	Export(ctx context.Context, userID uint) (*domain.UserExport, error)
+
+	// RequestErasure schedules the user's account for erasure once the
+	// grace period has passed. Users may only ask for their own; admins may
+	// ask for anyone's and have it carried out immediately.
+	RequestErasure(ctx context.Context, userID uint, req domain.RequestErasureRequest) (*domain.ErasureRequest, error)
+	GetErasure(ctx context.Context, userID uint) (*domain.ErasureRequest, error)
+	CancelErasure(ctx context.Context, userID uint) error
+	ListErasures(ctx context.Context, limit int) ([]domain.ErasureRequest, error)
+
+	// ProcessDue carries out every erasure whose grace period has passed.
+	// One that fails stays pending and is retried next time.
+	ProcessDue(ctx context.Context) error
+}
@@ -0,0 +1,25 @@
+package share
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	// Notify emails a personalized link for the shared file to every
+	// recipient. Delivery failures are recorded per recipient rather than
+	// failing the whole call.
+	Notify(ctx context.Context, file *domain.File, req domain.ShareRequest) ([]domain.ShareRecipient, error)
+
+	// CheckRecipients reports whether Notify would accept req for file, so
+	// callers can refuse a share before changing the link.
+	CheckRecipients(ctx context.Context, file *domain.File, req domain.ShareRequest) error
+
+	ListRecipients(ctx context.Context, fileID uint) ([]domain.ShareRecipient, error)
+
+	RecordAccess(ctx context.Context, fileID uint, token string) error
+
+	// Preview returns the public metadata of a shared file without exposing
+	// its contents.
+	Preview(ctx context.Context, shareID string) (*domain.SharePreview, error)
+}
@@ -0,0 +1,23 @@
+package sso
+
+import (
+	"context"
+
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	Providers() []domain.OIDCProviderInfo
+
+	// BeginLogin starts an authorization code flow with the named provider.
+	// It returns where to send the user and the state the callback must
+	// present.
+	BeginLogin(ctx context.Context, provider string) (authURL, state string, err error)
+
+	// CompleteLogin redeems the provider's authorization code, finds or
+	// provisions the user and returns a one-time login ticket.
+	CompleteLogin(ctx context.Context, provider, state, code string) (string, error)
+
+	// ExchangeTicket consumes a login ticket and returns its user.
+	ExchangeTicket(ctx context.Context, ticket string) (*domain.User, error)
+}
@@ -0,0 +1,42 @@
+package token
+
+import (
+	"context"
+	"time"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	Revoker
+
+	// Issue starts a new session for user on the given device.
+	Issue(ctx context.Context, user *domain.User, client domain.SessionClient) (*domain.TokenPair, error)
+
+	// IssueImpersonation starts the session impersonation runs in and returns
+	// an access token for user that names admin as the actor. It lasts until
+	// the impersonation expires and has no refresh token.
+	IssueImpersonation(ctx context.Context, impersonation *domain.Impersonation, admin, user *domain.User, client domain.SessionClient) (string, error)
+
+	// Refresh exchanges a refresh token for a new pair. The presented token
+	// is consumed; presenting it again revokes its whole session.
+	Refresh(ctx context.Context, refreshToken string, client domain.SessionClient) (*domain.TokenPair, error)
+
+	// Logout revokes the given access token and its session. Tokens issued
+	// before sessions existed carry no session ID, in which case the session
+	// the refresh token belongs to is revoked, if one is given.
+	Logout(ctx context.Context, userID uint, sessionID, jti string, expiresAt time.Time, refreshToken string) error
+
+	IsRevoked(ctx context.Context, userID uint, sessionID, jti string, issuedAt time.Time) (bool, error)
+
+	// ListSessions returns the user's active sessions, marking currentID as
+	// the current one.
+	ListSessions(ctx context.Context, userID uint, currentID string) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+}
+
+// Revoker ends a user's sessions, e.g. after a password change.
+type Revoker interface {
+	RevokeUser(ctx context.Context, userID uint) error
+	// RevokeOtherSessions signs the user out everywhere except keep.
+	RevokeOtherSessions(ctx context.Context, userID uint, keep string) error
+}
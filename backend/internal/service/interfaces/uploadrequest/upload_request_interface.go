@@ -0,0 +1,25 @@
+package uploadrequest
+
+import (
+	"context"
+	"tech-test/backend/internal/domain"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uint, req domain.CreateUploadRequestRequest) (*domain.UploadRequest, error)
+
+	ListByUser(ctx context.Context, userID uint) ([]domain.UploadRequest, error)
+
+	Delete(ctx context.Context, id uint) error
+
+	// GetOpen returns the request behind a public token, failing if it has expired.
+	GetOpen(ctx context.Context, token string) (*domain.UploadRequest, error)
+
+	// Authorize returns the open request behind token if password unlocks
+	// it, so a submission can be refused before anything is stored.
+	Authorize(ctx context.Context, token, password string) (*domain.UploadRequest, error)
+
+	// Submit checks the password and policy of an open request and records the
+	// file in the creator's account as an external submission.
+	Submit(ctx context.Context, token, password string, file *domain.File) error
+}
@@ -9,6 +9,7 @@ type UserService interface {
     UserReader
     UserWriter
     UserAuthenticator
+    UserLifecycle
 }
 
 type UserReader interface {
@@ -22,11 +23,68 @@ type UserReader interface {
 type UserWriter interface {
     Register(ctx context.Context, user *domain.User) error
     
+    // UpdateUser changes an account's email, name, date of birth and
+    // password on an admin's behalf, ignoring every other field. A changed
+    // email must be verified again.
     UpdateUser(ctx context.Context, id uint, user *domain.User) error
     
     DeleteUser(ctx context.Context, id uint) error
+
+    SetRole(ctx context.Context, id uint, role domain.Role) (*domain.User, error)
+
+    // PromoteBootstrapAdmin makes the user an admin if their verified email
+    // is one of the bootstrap admin emails.
+    PromoteBootstrapAdmin(ctx context.Context, id uint) error
+
+    // CheckPassword reports why password is not acceptable for the account
+    // with email, or returns nil. Register does not check, as it also
+    // creates accounts with generated passwords.
+    CheckPassword(password, email string) error
+
+    // SetPassword replaces the user's password and ends all their sessions.
+    SetPassword(ctx context.Context, id uint, password string) error
+
+    // UpdateProfile changes the fields set in req on the user's own profile.
+    UpdateProfile(ctx context.Context, id uint, req domain.UpdateProfileRequest) (*domain.User, error)
+
+    // ChangePassword replaces the password once current is confirmed, and
+    // signs the user out of every session except keepSession.
+    ChangePassword(ctx context.Context, id uint, current, next, keepSession string) error
 }
 
 type UserAuthenticator interface {
     Login(ctx context.Context, email, password string) (*domain.User, error)
+
+    // IsDisabled reports whether requests made as userID must be refused,
+    // which includes users that no longer exist.
+    IsDisabled(ctx context.Context, userID uint) (bool, error)
+
+    // IsAdmin reports whether userID is an existing admin account.
+    IsAdmin(ctx context.Context, userID uint) (bool, error)
+
+    // RecordLogin notes when and from where the user last signed in.
+    RecordLogin(ctx context.Context, id uint, ip string) error
+}
+
+// UserLifecycle lets admins control whether an account can be used without
+// deleting it.
+type UserLifecycle interface {
+    // Disable blocks the account and ends its sessions. Admins cannot
+    // disable themselves or the last enabled admin.
+    Disable(ctx context.Context, id uint, reason string) (*domain.User, error)
+
+    Enable(ctx context.Context, id uint) (*domain.User, error)
+
+    // VerifyEmail marks the user's current email verified without a link.
+    VerifyEmail(ctx context.Context, id uint) (*domain.User, error)
+
+    // RequirePasswordChange makes the next login end in a password change
+    // and ends the user's sessions.
+    RequirePasswordChange(ctx context.Context, id uint) error
+
+    // SetTemporaryPassword sets a password the user must change at their
+    // next login, generating one when password is empty, and returns it.
+    SetTemporaryPassword(ctx context.Context, id uint, password string) (string, error)
+
+    AccountStatus(ctx context.Context, id uint) (*domain.AccountStatus, error)
 }
\ No newline at end of file
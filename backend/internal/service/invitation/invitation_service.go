@@ -0,0 +1,310 @@
+package invitation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/repository/interfaces"
+	invitationInterface "tech-test/backend/internal/service/interfaces/invitation"
+	userInterface "tech-test/backend/internal/service/interfaces/user"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+type service struct {
+	repo           interfaces.InvitationRepository
+	orgRepo        interfaces.OrganizationRepository
+	users          userInterface.UserWriter
+	mailer         mailer.Mailer
+	mode           domain.RegistrationMode
+	allowedDomains map[string]bool
+	url            string
+	ttl            time.Duration
+	logger         *zap.Logger
+}
+
+// NewService returns an invitation service enforcing cfg. On an invite-only
+// instance the first admin is imported from USER_SEED_FILE. mailer may be
+// nil, in which case invitations are only handed to the admin.
+func NewService(repo interfaces.InvitationRepository, orgRepo interfaces.OrganizationRepository, users userInterface.UserWriter, mail mailer.Mailer, cfg config.RegistrationConfig, logger *zap.Logger) (invitationInterface.Service, error) {
+	mode := domain.RegistrationMode(cfg.Mode)
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("unknown REGISTRATION_MODE %q", cfg.Mode)
+	}
+	allowed := make(map[string]bool, len(cfg.AllowedDomains))
+	for _, d := range cfg.AllowedDomains {
+		allowed[strings.ToLower(strings.TrimPrefix(d, "@"))] = true
+	}
+	if mode == domain.RegistrationDomain && len(allowed) == 0 {
+		logger.Warn("REGISTRATION_MODE=domain without REGISTRATION_ALLOWED_DOMAINS, only invited users can sign up")
+	}
+
+	return &service{
+		repo:           repo,
+		orgRepo:        orgRepo,
+		users:          users,
+		mailer:         mail,
+		mode:           mode,
+		allowedDomains: allowed,
+		url:            cfg.InvitationURL,
+		ttl:            cfg.InvitationTTL,
+		logger:         logger,
+	}, nil
+}
+
+func (s *service) Policy() domain.RegistrationPolicy {
+	policy := domain.RegistrationPolicy{Mode: s.mode}
+	if s.mode == domain.RegistrationDomain {
+		for d := range s.allowedDomains {
+			policy.AllowedDomains = append(policy.AllowedDomains, d)
+		}
+		sort.Strings(policy.AllowedDomains)
+	}
+	return policy
+}
+
+func (s *service) Register(ctx context.Context, user *domain.User, code string) error {
+	email := normalizeEmail(user.Email)
+	code = strings.TrimSpace(code)
+
+	// Checked first, so a weak password does not use up the invitation.
+	if err := s.users.CheckPassword(user.Password, email); err != nil {
+		return err
+	}
+
+	var invitation *domain.Invitation
+	if code != "" {
+		var err error
+		if invitation, err = s.repo.ClaimCode(ctx, hashCode(code), email, time.Now().UTC()); err != nil {
+			return err
+		}
+	} else if err := s.checkPolicy(email); err != nil {
+		return err
+	}
+	return s.create(ctx, user, invitation)
+}
+
+func (s *service) Provision(ctx context.Context, user *domain.User) error {
+	email := normalizeEmail(user.Email)
+
+	invitation, err := s.repo.ClaimForEmail(ctx, email, time.Now().UTC())
+	switch {
+	case errors.Is(err, domain.ErrInvitationInvalid):
+		if err := s.checkPolicy(email); err != nil {
+			return err
+		}
+		invitation = nil
+	case err != nil:
+		return err
+	}
+	return s.create(ctx, user, invitation)
+}
+
+// checkPolicy decides whether email may sign up without an invitation.
+func (s *service) checkPolicy(email string) error {
+	switch {
+	case s.mode == domain.RegistrationOpen:
+		return nil
+	case s.mode == domain.RegistrationDomain:
+		_, emailDomain, _ := strings.Cut(email, "@")
+		if s.allowedDomains[emailDomain] {
+			return nil
+		}
+		return domain.NewAPIError(
+			403,
+			domain.ErrCodeAuthorization,
+			"Registration is not open to this email domain",
+			nil,
+		)
+	default:
+		return domain.ErrRegistrationClosed
+	}
+}
+
+// create registers user with what invitation grants, if there is one. The
+// invitation has already been claimed, and is released if the account
+// cannot be created.
+func (s *service) create(ctx context.Context, user *domain.User, invitation *domain.Invitation) error {
+	if invitation == nil {
+		return s.users.Register(ctx, user)
+	}
+
+	user.Role = invitation.Role
+	if err := s.users.Register(ctx, user); err != nil {
+		if releaseErr := s.repo.Release(ctx, invitation.ID); releaseErr != nil {
+			s.logger.Error("Failed to release invitation",
+				zap.Uint("invitationID", invitation.ID),
+				zap.Error(releaseErr))
+		}
+		return err
+	}
+
+	// The account exists from here on; what follows is logged rather than
+	// failing the sign-up.
+	if err := s.repo.SetAcceptedBy(ctx, invitation.ID, user.ID); err != nil {
+		s.logger.Error("Failed to record invitation acceptance",
+			zap.Uint("invitationID", invitation.ID),
+			zap.Error(err))
+	}
+	if invitation.OrganizationID != nil {
+		member := &domain.OrganizationMember{
+			OrganizationID: *invitation.OrganizationID,
+			UserID:         user.ID,
+			Role:           invitation.OrganizationRole,
+		}
+		if err := s.orgRepo.AddMember(ctx, member); err != nil {
+			s.logger.Warn("Failed to add invited user to organization",
+				zap.Uint("invitationID", invitation.ID),
+				zap.Uint("organizationID", member.OrganizationID),
+				zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Invitation accepted",
+		zap.Uint("invitationID", invitation.ID),
+		zap.Uint("userID", user.ID))
+	return nil
+}
+
+func (s *service) Create(ctx context.Context, req domain.CreateInvitationRequest) (*domain.CreatedInvitation, error) {
+	actor := domain.ActorFromContext(ctx)
+	if !actor.Admin {
+		return nil, domain.ErrForbidden
+	}
+
+	invitation := &domain.Invitation{
+		Email:     normalizeEmail(req.Email),
+		Role:      req.Role,
+		CreatedBy: actor.UserID,
+		ExpiresAt: time.Now().UTC().Add(s.ttl),
+	}
+	if invitation.Email != "" && !strings.Contains(invitation.Email, "@") {
+		return nil, domain.NewInvalidInputError("email is not a valid address")
+	}
+	if invitation.Role == "" {
+		invitation.Role = domain.RoleMember
+	}
+	if !invitation.Role.Valid() {
+		return nil, domain.NewInvalidInputError("role must be admin or member")
+	}
+	if req.ExpiresInHours < 0 {
+		return nil, domain.NewInvalidInputError("expiresInHours must be positive")
+	}
+	if req.ExpiresInHours > 0 {
+		invitation.ExpiresAt = time.Now().UTC().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+	}
+
+	if req.OrganizationID != nil {
+		org, err := s.orgRepo.GetByID(ctx, *req.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		invitation.OrganizationID = &org.ID
+		invitation.OrganizationRole = req.OrganizationRole
+		if invitation.OrganizationRole == "" {
+			invitation.OrganizationRole = domain.OrgRoleMember
+		}
+		if !invitation.OrganizationRole.IsValid() {
+			return nil, domain.NewInvalidInputError("organizationRole must be owner, admin or member")
+		}
+	}
+
+	code, err := randomCode()
+	if err != nil {
+		return nil, domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to generate invitation code", err)
+	}
+	invitation.CodeHash = hashCode(code)
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	created := &domain.CreatedInvitation{
+		Invitation: invitation,
+		Code:       code,
+		Link:       s.link(code),
+	}
+	if invitation.Email != "" && s.mailer != nil {
+		created.Sent = s.send(ctx, invitation, created.Link)
+	}
+
+	s.logger.Info("Invitation created",
+		zap.Uint("invitationID", invitation.ID),
+		zap.Uint("createdBy", actor.UserID),
+		zap.String("role", string(invitation.Role)),
+		zap.Bool("sent", created.Sent))
+	return created, nil
+}
+
+func (s *service) List(ctx context.Context, limit int) ([]domain.Invitation, error) {
+	switch {
+	case limit <= 0:
+		limit = defaultListLimit
+	case limit > maxListLimit:
+		limit = maxListLimit
+	}
+	return s.repo.List(ctx, limit)
+}
+
+func (s *service) Revoke(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Invitation revoked", zap.Uint("invitationID", id))
+	return nil
+}
+
+// send emails the invitation and reports whether it went out.
+func (s *service) send(ctx context.Context, invitation *domain.Invitation, link string) bool {
+	msg := mailer.Message{
+		To:      invitation.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"Hello,\n\nYou have been invited to create an account. "+
+				"To sign up, open this link before %s:\n\n%s\n",
+			invitation.ExpiresAt.Format(time.RFC1123), link),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.Error("Failed to send invitation email",
+			zap.Uint("invitationID", invitation.ID),
+			zap.Error(err))
+		return false
+	}
+	return true
+}
+
+func (s *service) link(code string) string {
+	return s.url + "?invite=" + url.QueryEscape(code)
+}
+
+func randomCode() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
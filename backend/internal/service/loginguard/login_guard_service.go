@@ -0,0 +1,217 @@
+package loginguard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	loginGuardInterface "tech-test/backend/internal/service/interfaces/loginguard"
+)
+
+const (
+	// freeFailures is how many failures an account gets before each
+	// further attempt is delayed.
+	freeFailures = 1
+	baseDelay    = time.Second
+	maxDelay     = 30 * time.Second
+
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+type service struct {
+	repo   interfaces.LoginGuardRepository
+	config config.LoginGuardConfig
+	logger *zap.Logger
+}
+
+func NewService(repo interfaces.LoginGuardRepository, config config.LoginGuardConfig, logger *zap.Logger) loginGuardInterface.Service {
+	return &service{
+		repo:   repo,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (s *service) Check(ctx context.Context, email, ip string) (time.Duration, error) {
+	throttles, err := s.repo.GetThrottles(ctx, []string{accountKey(email), ipKey(ip)})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	var wait time.Duration
+	for _, throttle := range throttles {
+		until := throttle.NextAttemptAt
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(until) {
+			until = *throttle.LockedUntil
+		}
+		if d := until.Sub(now); d > wait {
+			wait = d
+		}
+	}
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	if err := s.repo.RecordAttempt(ctx, &domain.LoginAttempt{
+		Email:  normalizeEmail(email),
+		IP:     ip,
+		Reason: domain.LoginFailureThrottled,
+	}); err != nil {
+		s.logger.Warn("Failed to record throttled login", zap.Error(err))
+	}
+	return wait, domain.ErrTooManyLoginAttempts
+}
+
+func (s *service) RecordFailure(ctx context.Context, email, ip, reason string) error {
+	email = normalizeEmail(email)
+	if err := s.repo.RecordAttempt(ctx, &domain.LoginAttempt{
+		Email:  email,
+		IP:     ip,
+		Reason: reason,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.fail(ctx, domain.LockoutScopeAccount, email, s.config.MaxFailures, true); err != nil {
+		return err
+	}
+	// Addresses are not delayed, since many people can share one.
+	return s.fail(ctx, domain.LockoutScopeIP, ip, s.config.IPMaxFailures, false)
+}
+
+// fail counts a failure against one throttle, delaying the next attempt
+// when progressive is set and locking it out at max failures.
+func (s *service) fail(ctx context.Context, scope, subject string, max int, progressive bool) error {
+	key := accountKey(subject)
+	if scope == domain.LockoutScopeIP {
+		key = ipKey(subject)
+	}
+
+	now := time.Now().UTC()
+	var lockout *domain.LockoutEvent
+	err := s.repo.UpdateThrottle(ctx, key, func(throttle *domain.LoginThrottle) {
+		if now.Sub(throttle.LastFailureAt) > s.config.Window {
+			throttle.Failures = 0
+		}
+		throttle.Failures++
+		throttle.LastFailureAt = now
+
+		if throttle.Failures >= max {
+			lockedUntil := now.Add(s.config.LockoutDuration)
+			throttle.LockedUntil = &lockedUntil
+			lockout = &domain.LockoutEvent{
+				Scope:       scope,
+				Subject:     subject,
+				Failures:    throttle.Failures,
+				LockedUntil: lockedUntil,
+			}
+			// The next failure after the lockout starts a new count.
+			throttle.Failures = 0
+			return
+		}
+		if progressive {
+			throttle.NextAttemptAt = now.Add(delay(throttle.Failures))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if lockout != nil {
+		s.logger.Warn("Locked out after repeated failed logins",
+			zap.String("scope", scope),
+			zap.String("subject", subject),
+			zap.Time("lockedUntil", lockout.LockedUntil))
+		return s.repo.RecordLockout(ctx, lockout)
+	}
+	return nil
+}
+
+func (s *service) RecordSuccess(ctx context.Context, email, ip string, userID uint) error {
+	email = normalizeEmail(email)
+	if err := s.repo.RecordAttempt(ctx, &domain.LoginAttempt{
+		Email:   email,
+		IP:      ip,
+		UserID:  &userID,
+		Success: true,
+	}); err != nil {
+		return err
+	}
+	return s.repo.DeleteThrottle(ctx, accountKey(email))
+}
+
+func (s *service) ListAttempts(ctx context.Context, filter domain.LoginAttemptFilter) ([]domain.LoginAttempt, error) {
+	filter.Email = normalizeEmail(filter.Email)
+	filter.Limit = clampLimit(filter.Limit)
+	return s.repo.ListAttempts(ctx, filter)
+}
+
+func (s *service) ListLockouts(ctx context.Context, limit int) ([]domain.LockoutEvent, error) {
+	return s.repo.ListLockouts(ctx, clampLimit(limit))
+}
+
+func (s *service) Clear(ctx context.Context, req domain.ClearLockoutRequest) error {
+	if req.Email == "" && req.IP == "" {
+		return domain.NewInvalidInputError("email or ip is required")
+	}
+
+	if req.Email != "" {
+		if err := s.repo.DeleteThrottle(ctx, accountKey(req.Email)); err != nil {
+			return err
+		}
+	}
+	if req.IP != "" {
+		if err := s.repo.DeleteThrottle(ctx, ipKey(req.IP)); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Cleared login lockout",
+		zap.String("email", req.Email),
+		zap.String("ip", req.IP))
+	return nil
+}
+
+// delay doubles with each failure past the free ones, up to maxDelay.
+func delay(failures int) time.Duration {
+	n := failures - freeFailures
+	if n <= 0 {
+		return 0
+	}
+	if n > 5 {
+		return maxDelay
+	}
+	d := baseDelay << (n - 1)
+	if d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
+
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func accountKey(email string) string {
+	return "email:" + normalizeEmail(email)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,428 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	mfaInterface "tech-test/backend/internal/service/interfaces/mfa"
+	"tech-test/backend/internal/utils"
+)
+
+const (
+	challengeTTL         = 5 * time.Minute
+	maxChallengeAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+type service struct {
+	repo     interfaces.MFARepository
+	settings interfaces.SettingRepository
+	userRepo interfaces.UserRepository
+	issuer   string
+	logger   *zap.Logger
+}
+
+func NewService(
+	repo interfaces.MFARepository,
+	settings interfaces.SettingRepository,
+	userRepo interfaces.UserRepository,
+	issuer string,
+	logger *zap.Logger,
+) mfaInterface.Service {
+	return &service{
+		repo:     repo,
+		settings: settings,
+		userRepo: userRepo,
+		issuer:   issuer,
+		logger:   logger,
+	}
+}
+
+func (s *service) Status(ctx context.Context, userID uint) (*domain.MFAStatus, error) {
+	required, err := s.IsRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &domain.MFAStatus{Required: required}
+
+	credential, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential != nil && credential.Enabled {
+		status.TOTPEnabled = true
+		if status.RecoveryCodesRemaining, err = s.repo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *service) BeginEnrollment(ctx context.Context, userID uint) (*domain.TOTPEnrollment, error) {
+	s.logger.Debug("Beginning TOTP enrollment", zap.Uint("userID", userID))
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential != nil && credential.Enabled {
+		return nil, domain.NewAPIError(
+			409,
+			domain.ErrCodeConflict,
+			"Two-factor authentication is already enabled",
+			nil,
+		)
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to generate TOTP secret",
+			err,
+		)
+	}
+	if err := s.repo.SaveTOTP(ctx, &domain.TOTPCredential{UserID: userID, Secret: secret}); err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *service) ConfirmEnrollment(ctx context.Context, userID uint, code string) ([]string, error) {
+	s.logger.Debug("Confirming TOTP enrollment", zap.Uint("userID", userID))
+
+	credential, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, domain.NewInvalidInputError("start enrollment first")
+	}
+	if credential.Enabled {
+		return nil, domain.NewAPIError(
+			409,
+			domain.ErrCodeConflict,
+			"Two-factor authentication is already enabled",
+			nil,
+		)
+	}
+
+	ok, err := s.checkTOTP(ctx, credential, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+	return s.enable(ctx, credential)
+}
+
+func (s *service) Disable(ctx context.Context, userID uint, code string) error {
+	s.logger.Debug("Disabling TOTP", zap.Uint("userID", userID))
+
+	required, err := s.IsRequired(ctx)
+	if err != nil {
+		return err
+	}
+	if required {
+		return domain.NewAPIError(
+			403,
+			domain.ErrCodeAuthorization,
+			"Two-factor authentication is required for all accounts",
+			nil,
+		)
+	}
+
+	credential, err := s.enabledCredential(ctx, userID)
+	if err != nil {
+		return err
+	}
+	ok, err := s.checkCode(ctx, credential, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+	return s.repo.DeleteTOTP(ctx, userID)
+}
+
+func (s *service) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	s.logger.Debug("Regenerating recovery codes", zap.Uint("userID", userID))
+
+	credential, err := s.enabledCredential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.checkTOTP(ctx, credential, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+	return s.newRecoveryCodes(ctx, userID)
+}
+
+func (s *service) Reset(ctx context.Context, userID uint) error {
+	s.logger.Info("Resetting two-factor authentication", zap.Uint("userID", userID))
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	return s.repo.DeleteTOTP(ctx, userID)
+}
+
+func (s *service) IsRequired(ctx context.Context) (bool, error) {
+	value, err := s.settings.Get(ctx, domain.SettingMFARequired)
+	if err != nil {
+		return false, err
+	}
+	return value == "true", nil
+}
+
+func (s *service) SetRequired(ctx context.Context, required bool) error {
+	s.logger.Info("Setting 2FA policy", zap.Bool("required", required))
+
+	value := "false"
+	if required {
+		value = "true"
+	}
+	return s.settings.Set(ctx, domain.SettingMFARequired, value)
+}
+
+func (s *service) BeginLogin(ctx context.Context, user *domain.User) (*domain.LoginChallenge, error) {
+	credential, err := s.repo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := credential != nil && credential.Enabled
+
+	required, err := s.IsRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled && !required {
+		return nil, nil
+	}
+
+	raw, err := randomToken()
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Failed to create login challenge",
+			err,
+		)
+	}
+	challenge := &domain.MFAChallenge{
+		UserID:    user.ID,
+		TokenHash: hashSecret(raw),
+		ExpiresAt: time.Now().UTC().Add(challengeTTL),
+	}
+	if err := s.repo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginChallenge{
+		MFARequired:        true,
+		Challenge:          raw,
+		EnrollmentRequired: !enabled,
+		ExpiresIn:          int64(challengeTTL.Seconds()),
+	}, nil
+}
+
+func (s *service) EnrollWithChallenge(ctx context.Context, raw string) (*domain.TOTPEnrollment, error) {
+	challenge, err := s.challenge(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	return s.BeginEnrollment(ctx, challenge.UserID)
+}
+
+func (s *service) ChallengeUser(ctx context.Context, raw string) (*domain.User, error) {
+	challenge, err := s.challenge(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	return user, err
+}
+
+func (s *service) CompleteLogin(ctx context.Context, req domain.MFALoginRequest) (*domain.User, []string, error) {
+	challenge, err := s.challenge(ctx, req.Challenge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, nil, domain.ErrInvalidMFAChallenge
+		}
+		return nil, nil, err
+	}
+
+	credential, err := s.repo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if credential == nil {
+		return nil, nil, domain.NewInvalidInputError("enroll an authenticator first")
+	}
+
+	var ok bool
+	if credential.Enabled && req.RecoveryCode != "" {
+		ok, err = s.repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(req.RecoveryCode))
+	} else {
+		ok, err = s.checkTOTP(ctx, credential, req.Code)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		if err := s.repo.RecordChallengeAttempt(ctx, challenge.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, domain.ErrInvalidMFACode
+	}
+
+	var recoveryCodes []string
+	if !credential.Enabled {
+		if recoveryCodes, err = s.enable(ctx, credential); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.repo.DeleteChallenge(ctx, challenge.ID); err != nil {
+		return nil, nil, err
+	}
+	return user, recoveryCodes, nil
+}
+
+// challenge looks up a live login challenge, discarding it once it has
+// expired or had too many wrong codes.
+func (s *service) challenge(ctx context.Context, raw string) (*domain.MFAChallenge, error) {
+	if raw == "" {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	challenge, err := s.repo.GetChallenge(ctx, hashSecret(raw))
+	if err != nil {
+		return nil, err
+	}
+	if challenge.IsExpired() || challenge.Attempts >= maxChallengeAttempts {
+		if err := s.repo.DeleteChallenge(ctx, challenge.ID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	return challenge, nil
+}
+
+func (s *service) enabledCredential(ctx context.Context, userID uint) (*domain.TOTPCredential, error) {
+	credential, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil || !credential.Enabled {
+		return nil, domain.NewInvalidInputError("two-factor authentication is not enabled")
+	}
+	return credential, nil
+}
+
+func (s *service) enable(ctx context.Context, credential *domain.TOTPCredential) ([]string, error) {
+	credential.Enabled = true
+	if err := s.repo.SaveTOTP(ctx, credential); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(ctx, credential.UserID)
+}
+
+// checkCode accepts either a TOTP code or an unused recovery code.
+func (s *service) checkCode(ctx context.Context, credential *domain.TOTPCredential, code string) (bool, error) {
+	ok, err := s.checkTOTP(ctx, credential, code)
+	if err != nil || ok {
+		return ok, err
+	}
+	return s.repo.UseRecoveryCode(ctx, credential.UserID, hashRecoveryCode(code))
+}
+
+// checkTOTP validates code and records its time step so it cannot be used
+// a second time.
+func (s *service) checkTOTP(ctx context.Context, credential *domain.TOTPCredential, code string) (bool, error) {
+	step, ok := utils.ValidateTOTP(credential.Secret, code, time.Now(), credential.LastStep)
+	if !ok {
+		return false, nil
+	}
+	advanced, err := s.repo.AdvanceTOTPStep(ctx, credential.UserID, step)
+	if err != nil || !advanced {
+		return false, err
+	}
+	credential.LastStep = step
+	return true, nil
+}
+
+func (s *service) newRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, domain.NewAPIError(
+				500,
+				domain.ErrCodeInternal,
+				"Failed to generate recovery codes",
+				err,
+			)
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes[i] = code[:8] + "-" + code[8:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashRecoveryCode ignores case, spaces and dashes so codes can be typed
+// loosely.
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	return hashSecret(normalized)
+}
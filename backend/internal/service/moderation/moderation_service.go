@@ -0,0 +1,209 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/repository/interfaces"
+	moderationInterface "tech-test/backend/internal/service/interfaces/moderation"
+)
+
+const maxReportDetails = 2000
+
+type service struct {
+	reportRepo interfaces.AbuseReportRepository
+	fileRepo   interfaces.FileRepository
+	userRepo   interfaces.UserRepository
+	mailer     mailer.Mailer
+	logger     *zap.Logger
+}
+
+// NewService creates the moderation service. mailer may be nil, in which
+// case owner notifications are only logged.
+func NewService(
+	reportRepo interfaces.AbuseReportRepository,
+	fileRepo interfaces.FileRepository,
+	userRepo interfaces.UserRepository,
+	mailer mailer.Mailer,
+	logger *zap.Logger,
+) moderationInterface.Service {
+	return &service{
+		reportRepo: reportRepo,
+		fileRepo:   fileRepo,
+		userRepo:   userRepo,
+		mailer:     mailer,
+		logger:     logger,
+	}
+}
+
+func (s *service) Report(ctx context.Context, shareID, reporterIP string, req domain.AbuseReportRequest) (*domain.AbuseReport, error) {
+	s.logger.Debug("Filing abuse report",
+		zap.String("shareId", shareID),
+		zap.String("reason", req.Reason))
+
+	if !domain.AbuseReasons[req.Reason] {
+		return nil, domain.NewInvalidInputError("reason must be one of copyright, malware, phishing, illegal, other")
+	}
+	if len(req.Details) > maxReportDetails {
+		return nil, domain.NewInvalidInputError(fmt.Sprintf("details cannot exceed %d characters", maxReportDetails))
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email != "" {
+		addr, err := mail.ParseAddress(email)
+		if err != nil {
+			return nil, domain.NewInvalidInputError("invalid email")
+		}
+		email = addr.Address
+	}
+
+	file, err := s.fileRepo.GetFileByShareID(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.AbuseReport{
+		FileID:        file.ID,
+		ShareID:       shareID,
+		Reason:        req.Reason,
+		Details:       strings.TrimSpace(req.Details),
+		ReporterEmail: email,
+		ReporterIP:    reporterIP,
+		Status:        domain.AbuseReportOpen,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Abuse report filed",
+		zap.Uint("reportID", report.ID),
+		zap.Uint("fileID", file.ID),
+		zap.String("reason", report.Reason))
+
+	return report, nil
+}
+
+func (s *service) ListReports(ctx context.Context, status string) ([]domain.AbuseReport, error) {
+	s.logger.Debug("Listing abuse reports", zap.String("status", status))
+	return s.reportRepo.List(ctx, status)
+}
+
+func (s *service) Resolve(ctx context.Context, adminID, reportID uint, req domain.ResolveReportRequest) (*domain.AbuseReport, error) {
+	s.logger.Debug("Resolving abuse report",
+		zap.Uint("reportID", reportID),
+		zap.String("action", req.Action))
+
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != domain.AbuseReportOpen {
+		return nil, domain.NewAPIError(
+			409,
+			domain.ErrCodeConflict,
+			"Report has already been resolved",
+			nil,
+		)
+	}
+
+	status := domain.AbuseReportActioned
+	switch req.Action {
+	case domain.ModerationDismiss:
+		status = domain.AbuseReportDismissed
+	case domain.ModerationDisableShare:
+		if err := s.fileRepo.SetShareDisabled(ctx, report.FileID, true); err != nil {
+			return nil, err
+		}
+	case domain.ModerationQuarantine:
+		if err := s.fileRepo.SetQuarantined(ctx, report.FileID, true); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, domain.NewInvalidInputError("action must be one of dismiss, disable_share, quarantine")
+	}
+
+	now := time.Now().UTC()
+	report.Status = status
+	report.Action = req.Action
+	report.Note = strings.TrimSpace(req.Note)
+	report.ResolvedBy = &adminID
+	report.ResolvedAt = &now
+	if err := s.reportRepo.Update(ctx, report); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Abuse report resolved",
+		zap.Uint("reportID", report.ID),
+		zap.Uint("adminID", adminID),
+		zap.String("action", report.Action))
+
+	if status == domain.AbuseReportActioned {
+		s.notifyOwner(ctx, report)
+	}
+
+	return report, nil
+}
+
+func (s *service) ReleaseFile(ctx context.Context, fileID uint) error {
+	s.logger.Info("Releasing file from moderation", zap.Uint("fileID", fileID))
+	if err := s.fileRepo.SetQuarantined(ctx, fileID, false); err != nil {
+		return err
+	}
+	return s.fileRepo.SetShareDisabled(ctx, fileID, false)
+}
+
+// notifyOwner tells the file owner what happened to their file. Failures are
+// logged only; the moderation action itself has already been applied.
+func (s *service) notifyOwner(ctx context.Context, report *domain.AbuseReport) {
+	file, err := s.fileRepo.GetByID(ctx, report.FileID)
+	if err != nil {
+		s.logger.Error("Failed to load reported file for notification", zap.Error(err))
+		return
+	}
+	owner, err := s.userRepo.GetByID(ctx, file.UserID)
+	if err != nil {
+		s.logger.Error("Failed to load file owner for notification", zap.Error(err))
+		return
+	}
+
+	var action string
+	switch report.Action {
+	case domain.ModerationDisableShare:
+		action = "its public share link has been disabled"
+	case domain.ModerationQuarantine:
+		action = "it has been quarantined and can no longer be shared or downloaded"
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hello %s,\n\n", owner.FirstName)
+	fmt.Fprintf(&body, "Your file \"%s\" was reported (%s) and after review %s.\n", file.Name, report.Reason, action)
+	if report.Note != "" {
+		fmt.Fprintf(&body, "\nModerator note:\n%s\n", report.Note)
+	}
+	body.WriteString("\nIf you believe this is a mistake, please reply to this email.\n")
+
+	msg := mailer.Message{
+		To:      owner.Email,
+		Subject: fmt.Sprintf("Action taken on your file \"%s\"", file.Name),
+		Body:    body.String(),
+	}
+
+	if s.mailer == nil {
+		s.logger.Warn("Email delivery disabled, owner not notified",
+			zap.Uint("userID", owner.ID),
+			zap.Uint("reportID", report.ID))
+		return
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.Error("Failed to notify file owner",
+			zap.Uint("userID", owner.ID),
+			zap.Uint("reportID", report.ID),
+			zap.Error(err))
+	}
+}
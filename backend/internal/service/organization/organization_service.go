@@ -0,0 +1,512 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	orgInterface "tech-test/backend/internal/service/interfaces/organization"
+)
+
+const (
+	maxNameLength = 100
+	// invitationTTL is how long an invitation to join can be accepted.
+	invitationTTL = 7 * 24 * time.Hour
+)
+
+type service struct {
+	repo         interfaces.OrganizationRepository
+	fileRepo     interfaces.FileRepository
+	userRepo     interfaces.UserRepository
+	defaultQuota int64
+	logger       *zap.Logger
+}
+
+// NewService returns an organization service. New organizations start with
+// defaultQuota bytes of storage, zero meaning unlimited.
+func NewService(repo interfaces.OrganizationRepository, fileRepo interfaces.FileRepository, userRepo interfaces.UserRepository, defaultQuota int64, logger *zap.Logger) orgInterface.Service {
+	return &service{
+		repo:         repo,
+		fileRepo:     fileRepo,
+		userRepo:     userRepo,
+		defaultQuota: defaultQuota,
+		logger:       logger,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req domain.CreateOrganizationRequest) (*domain.Organization, error) {
+	actor := domain.ActorFromContext(ctx)
+	if actor.UserID == 0 {
+		return nil, domain.ErrUnauthorized
+	}
+	name, err := validateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	org := &domain.Organization{
+		Name:         name,
+		StorageQuota: s.defaultQuota,
+	}
+	owner := &domain.OrganizationMember{
+		UserID: actor.UserID,
+		Role:   domain.OrgRoleOwner,
+	}
+	if err := s.repo.Create(ctx, org, owner); err != nil {
+		return nil, err
+	}
+	org.Role = domain.OrgRoleOwner
+
+	s.logger.Info("Organization created",
+		zap.Uint("organizationID", org.ID),
+		zap.Uint("ownerID", actor.UserID))
+	return org, nil
+}
+
+func (s *service) List(ctx context.Context) ([]domain.Organization, error) {
+	actor := domain.ActorFromContext(ctx)
+	if actor.UserID == 0 {
+		return nil, domain.ErrUnauthorized
+	}
+	return s.repo.ListForUser(ctx, actor.UserID)
+}
+
+func (s *service) Get(ctx context.Context, id uint) (*domain.Organization, error) {
+	org, _, err := s.load(ctx, id)
+	return org, err
+}
+
+func (s *service) GetStorageUsage(ctx context.Context, id uint) (*domain.StorageUsage, error) {
+	org, _, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.fileRepo.GetStorageUsage(ctx, domain.OrganizationFiles(id))
+	if err != nil {
+		return nil, err
+	}
+	usage.Quota = org.StorageQuota
+	return usage, nil
+}
+
+func (s *service) Update(ctx context.Context, id uint, req domain.UpdateOrganizationRequest) (*domain.Organization, error) {
+	org, member, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage(member) {
+		return nil, domain.ErrForbidden
+	}
+	if org.Name, err = validateName(req.Name); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uint) error {
+	_, member, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.Role != domain.OrgRoleOwner {
+		return domain.ErrForbidden
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("Organization deleted",
+		zap.Uint("organizationID", id),
+		zap.Uint("userID", member.UserID))
+	return nil
+}
+
+func (s *service) ListMembers(ctx context.Context, id uint) ([]domain.OrganizationMember, error) {
+	if _, _, err := s.load(ctx, id); err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Members are joined with their accounts here rather than in SQL, since
+	// users may be kept in a different store.
+	listed := make([]domain.OrganizationMember, 0, len(members))
+	for _, m := range members {
+		user, err := s.userRepo.GetByID(ctx, m.UserID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		listed = append(listed, withUser(m, user))
+	}
+	return listed, nil
+}
+
+func (s *service) InviteMember(ctx context.Context, id uint, req domain.InviteMemberRequest) (*domain.OrganizationInvitation, error) {
+	_, caller, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage(caller) {
+		return nil, domain.ErrForbidden
+	}
+
+	role := req.Role
+	if role == "" {
+		role = domain.OrgRoleMember
+	}
+	if !role.IsValid() {
+		return nil, domain.NewInvalidInputError("role must be owner, admin or member")
+	}
+	if role == domain.OrgRoleOwner && caller.Role != domain.OrgRoleOwner {
+		return nil, domain.ErrForbidden
+	}
+
+	email := normalizeEmail(req.Email)
+	if email == "" {
+		return nil, domain.NewInvalidInputError("email is required")
+	}
+	if !strings.Contains(email, "@") {
+		return nil, domain.NewInvalidInputError("email is not a valid address")
+	}
+	// Members are listed anyway, so this tells the caller nothing new;
+	// whether anyone else has an account stays hidden.
+	if user, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		if _, err := s.repo.GetMember(ctx, id, user.ID); err == nil {
+			return nil, domain.NewAPIError(
+				409,
+				domain.ErrCodeConflict,
+				"User is already a member",
+				nil,
+			)
+		}
+	}
+
+	invitation := &domain.OrganizationInvitation{
+		OrganizationID: id,
+		Email:          email,
+		Role:           role,
+		InvitedBy:      caller.UserID,
+		ExpiresAt:      time.Now().UTC().Add(invitationTTL),
+	}
+	if err := s.repo.SaveInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Organization member invited",
+		zap.Uint("organizationID", id),
+		zap.Uint("invitationID", invitation.ID),
+		zap.String("role", string(role)),
+		zap.Uint("invitedBy", caller.UserID))
+	return invitation, nil
+}
+
+func (s *service) ListInvitations(ctx context.Context, id uint) ([]domain.OrganizationInvitation, error) {
+	_, caller, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage(caller) {
+		return nil, domain.ErrForbidden
+	}
+	return s.repo.ListInvitations(ctx, id)
+}
+
+func (s *service) RevokeInvitation(ctx context.Context, id, invitationID uint) error {
+	_, caller, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !canManage(caller) {
+		return domain.ErrForbidden
+	}
+	invitation, err := s.repo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.OrganizationID != id {
+		return domain.ErrOrgInvitationNotFound
+	}
+	if err := s.repo.DeleteInvitation(ctx, invitationID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Organization invitation revoked",
+		zap.Uint("organizationID", id),
+		zap.Uint("invitationID", invitationID),
+		zap.Uint("revokedBy", caller.UserID))
+	return nil
+}
+
+func (s *service) ListMyInvitations(ctx context.Context) ([]domain.OrganizationInvitation, error) {
+	user, err := s.invitee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvitationsForEmail(ctx, normalizeEmail(user.Email), time.Now().UTC())
+}
+
+func (s *service) AcceptInvitation(ctx context.Context, invitationID uint) (*domain.Organization, error) {
+	user, invitation, err := s.invitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &domain.OrganizationMember{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         user.ID,
+		Role:           invitation.Role,
+	}
+	if err := s.repo.AcceptInvitation(ctx, invitationID, member); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Organization invitation accepted",
+		zap.Uint("organizationID", invitation.OrganizationID),
+		zap.Uint("invitationID", invitationID),
+		zap.Uint("userID", user.ID),
+		zap.String("role", string(invitation.Role)))
+	return s.Get(ctx, invitation.OrganizationID)
+}
+
+func (s *service) DeclineInvitation(ctx context.Context, invitationID uint) error {
+	user, invitation, err := s.invitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteInvitation(ctx, invitationID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Organization invitation declined",
+		zap.Uint("organizationID", invitation.OrganizationID),
+		zap.Uint("invitationID", invitationID),
+		zap.Uint("userID", user.ID))
+	return nil
+}
+
+func (s *service) UpdateMemberRole(ctx context.Context, id, userID uint, role domain.OrgRole) error {
+	_, caller, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !canManage(caller) {
+		return domain.ErrForbidden
+	}
+	if !role.IsValid() {
+		return domain.NewInvalidInputError("role must be owner, admin or member")
+	}
+
+	target, err := s.repo.GetMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	// Only owners may make or unmake owners.
+	if (role == domain.OrgRoleOwner || target.Role == domain.OrgRoleOwner) && caller.Role != domain.OrgRoleOwner {
+		return domain.ErrForbidden
+	}
+	if target.Role == role {
+		return nil
+	}
+	if target.Role == domain.OrgRoleOwner {
+		if err := s.ensureAnotherOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, id, userID, role); err != nil {
+		return err
+	}
+
+	s.logger.Info("Organization member role changed",
+		zap.Uint("organizationID", id),
+		zap.Uint("userID", userID),
+		zap.String("from", string(target.Role)),
+		zap.String("to", string(role)),
+		zap.Uint("changedBy", caller.UserID))
+	return nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, id, userID uint) error {
+	_, caller, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if caller == nil {
+		return domain.ErrForbidden
+	}
+
+	target := caller
+	if userID != caller.UserID {
+		if !canManage(caller) {
+			return domain.ErrForbidden
+		}
+		if target, err = s.repo.GetMember(ctx, id, userID); err != nil {
+			return err
+		}
+		if target.Role == domain.OrgRoleOwner && caller.Role != domain.OrgRoleOwner {
+			return domain.ErrForbidden
+		}
+	}
+	if target.Role == domain.OrgRoleOwner {
+		if err := s.ensureAnotherOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.RemoveMember(ctx, id, userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Organization member removed",
+		zap.Uint("organizationID", id),
+		zap.Uint("userID", userID),
+		zap.Uint("removedBy", caller.UserID))
+	return nil
+}
+
+func (s *service) SetStorageQuota(ctx context.Context, id uint, quota int64) error {
+	if !domain.ActorFromContext(ctx).Admin {
+		return domain.ErrForbidden
+	}
+	if quota < 0 {
+		return domain.NewInvalidInputError("quota must not be negative")
+	}
+
+	org, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	org.StorageQuota = quota
+	if err := s.repo.Update(ctx, org); err != nil {
+		return err
+	}
+
+	s.logger.Info("Organization storage quota set",
+		zap.Uint("organizationID", id),
+		zap.Int64("quota", quota))
+	return nil
+}
+
+// load fetches an organization with the caller's membership. Site admins
+// may see organizations they do not belong to, in which case the membership
+// is nil; to anyone else those do not exist.
+func (s *service) load(ctx context.Context, id uint) (*domain.Organization, *domain.OrganizationMember, error) {
+	actor := domain.ActorFromContext(ctx)
+	if actor.UserID == 0 {
+		return nil, nil, domain.ErrUnauthorized
+	}
+
+	org, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member, err := s.repo.GetMember(ctx, id, actor.UserID)
+	switch {
+	case err == nil:
+		org.Role = member.Role
+		return org, member, nil
+	case errors.Is(err, domain.ErrMemberNotFound) && actor.Admin:
+		return org, nil, nil
+	case errors.Is(err, domain.ErrMemberNotFound):
+		return nil, nil, domain.NewNotFoundError("Organization")
+	default:
+		return nil, nil, err
+	}
+}
+
+// invitee returns the caller's account, which must have a verified email
+// to act on invitations sent to it.
+func (s *service) invitee(ctx context.Context) (*domain.User, error) {
+	actor := domain.ActorFromContext(ctx)
+	if actor.UserID == 0 {
+		return nil, domain.ErrUnauthorized
+	}
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsEmailVerified() {
+		return nil, domain.ErrOrgInvitationUnverified
+	}
+	return user, nil
+}
+
+// invitation fetches an unexpired invitation sent to the caller. Anyone
+// else's invitations do not exist to them.
+func (s *service) invitation(ctx context.Context, id uint) (*domain.User, *domain.OrganizationInvitation, error) {
+	user, err := s.invitee(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	invitation, err := s.repo.GetInvitation(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if invitation.Email != normalizeEmail(user.Email) || invitation.IsExpired() {
+		return nil, nil, domain.ErrOrgInvitationNotFound
+	}
+	return user, invitation, nil
+}
+
+// ensureAnotherOwner fails unless the organization has more than one owner,
+// so that one can step down or leave.
+func (s *service) ensureAnotherOwner(ctx context.Context, id uint) error {
+	members, err := s.repo.ListMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+	owners := 0
+	for _, m := range members {
+		if m.Role == domain.OrgRoleOwner {
+			owners++
+		}
+	}
+	if owners <= 1 {
+		return domain.NewAPIError(
+			409,
+			domain.ErrCodeConflict,
+			"An organization must keep at least one owner",
+			nil,
+		)
+	}
+	return nil
+}
+
+func canManage(member *domain.OrganizationMember) bool {
+	return member != nil && member.Role.CanManage()
+}
+
+func withUser(member domain.OrganizationMember, user *domain.User) domain.OrganizationMember {
+	member.Email = user.Email
+	member.FirstName = user.FirstName
+	member.Surname = user.Surname
+	return member
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", domain.NewInvalidInputError("name is required")
+	}
+	if len(name) > maxNameLength {
+		return "", domain.NewInvalidInputError("name must be at most 100 characters")
+	}
+	return name, nil
+}
@@ -0,0 +1,202 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/testutil"
+)
+
+type fixture struct {
+	*testutil.Env
+	repo  interfaces.OrganizationRepository
+	svc   *service
+	owner *domain.User
+	org   *domain.Organization
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	env := testutil.NewEnv(t)
+	repo := sqlite.NewOrganizationRepository(env.DB)
+	f := &fixture{
+		Env:  env,
+		repo: repo,
+		svc:  NewService(repo, sqlite.NewFileRepository(env.DB), env.Users, 0, zap.NewNop()).(*service),
+	}
+	f.owner = f.createUser(t, "owner@example.com", true)
+
+	org, err := f.svc.Create(f.as(f.owner), domain.CreateOrganizationRequest{Name: "Finance"})
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	f.org = org
+	return f
+}
+
+func (f *fixture) createUser(t *testing.T, email string, verified bool) *domain.User {
+	t.Helper()
+	user := &domain.User{Email: email, FirstName: "Alice"}
+	if verified {
+		now := time.Now().UTC()
+		user.EmailVerifiedAt = &now
+	}
+	return f.CreateUser(t, user)
+}
+
+func (f *fixture) as(user *domain.User) context.Context {
+	return domain.ContextWithActor(context.Background(), domain.Actor{UserID: user.ID})
+}
+
+func (f *fixture) isMember(t *testing.T, user *domain.User) bool {
+	t.Helper()
+	_, err := f.repo.GetMember(context.Background(), f.org.ID, user.ID)
+	if err != nil && !errors.Is(err, domain.ErrMemberNotFound) {
+		t.Fatalf("GetMember: %v", err)
+	}
+	return err == nil
+}
+
+func TestInviteMemberDoesNotRevealAccounts(t *testing.T) {
+	f := newFixture(t)
+	existing := f.createUser(t, "bob@example.com", true)
+
+	invite := func(email string) *domain.OrganizationInvitation {
+		t.Helper()
+		invitation, err := f.svc.InviteMember(f.as(f.owner), f.org.ID, domain.InviteMemberRequest{Email: email})
+		if err != nil {
+			t.Fatalf("InviteMember(%q): %v", email, err)
+		}
+		return invitation
+	}
+	known := invite(" Bob@Example.com ")
+	unknown := invite("nobody@example.com")
+
+	// Apart from the address and ID, both answers are the same.
+	known.ID, unknown.ID = 0, 0
+	known.Email, unknown.Email = "", ""
+	known.CreatedAt, unknown.CreatedAt = time.Time{}, time.Time{}
+	known.ExpiresAt, unknown.ExpiresAt = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(known, unknown) {
+		t.Errorf("invitations differ:\n%+v\n%+v", known, unknown)
+	}
+	if f.isMember(t, existing) {
+		t.Error("invited user became a member without accepting")
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	f := newFixture(t)
+	bob := f.createUser(t, "bob@example.com", true)
+	invitation, err := f.svc.InviteMember(f.as(f.owner), f.org.ID, domain.InviteMemberRequest{Email: "bob@example.com", Role: domain.OrgRoleAdmin})
+	if err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+
+	// Nobody else can see or use the invitation.
+	carol := f.createUser(t, "carol@example.com", true)
+	if _, err := f.svc.AcceptInvitation(f.as(carol), invitation.ID); !errors.Is(err, domain.ErrOrgInvitationNotFound) {
+		t.Errorf("accepting someone else's invitation: got %v, want ErrOrgInvitationNotFound", err)
+	}
+
+	invitations, err := f.svc.ListMyInvitations(f.as(bob))
+	if err != nil {
+		t.Fatalf("ListMyInvitations: %v", err)
+	}
+	if len(invitations) != 1 || invitations[0].ID != invitation.ID || invitations[0].OrganizationName != "Finance" {
+		t.Fatalf("unexpected invitations %+v", invitations)
+	}
+
+	org, err := f.svc.AcceptInvitation(f.as(bob), invitation.ID)
+	if err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	if org.ID != f.org.ID || org.Role != domain.OrgRoleAdmin {
+		t.Errorf("unexpected organization %+v", org)
+	}
+	if !f.isMember(t, bob) {
+		t.Error("accepting did not add the member")
+	}
+
+	// The invitation is used up.
+	if _, err := f.svc.AcceptInvitation(f.as(bob), invitation.ID); !errors.Is(err, domain.ErrOrgInvitationNotFound) {
+		t.Errorf("accepting twice: got %v, want ErrOrgInvitationNotFound", err)
+	}
+}
+
+func TestAcceptInvitationNeedsVerifiedEmail(t *testing.T) {
+	f := newFixture(t)
+	invitation, err := f.svc.InviteMember(f.as(f.owner), f.org.ID, domain.InviteMemberRequest{Email: "bob@example.com"})
+	if err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+	// Someone signs up with the invited address they do not own.
+	bob := f.createUser(t, "bob@example.com", false)
+
+	if _, err := f.svc.ListMyInvitations(f.as(bob)); !errors.Is(err, domain.ErrOrgInvitationUnverified) {
+		t.Errorf("ListMyInvitations: got %v, want ErrOrgInvitationUnverified", err)
+	}
+	if _, err := f.svc.AcceptInvitation(f.as(bob), invitation.ID); !errors.Is(err, domain.ErrOrgInvitationUnverified) {
+		t.Errorf("AcceptInvitation: got %v, want ErrOrgInvitationUnverified", err)
+	}
+	if f.isMember(t, bob) {
+		t.Error("unverified user joined")
+	}
+}
+
+func TestDeclineAndRevokeInvitation(t *testing.T) {
+	f := newFixture(t)
+	bob := f.createUser(t, "bob@example.com", true)
+	invite := func() *domain.OrganizationInvitation {
+		t.Helper()
+		invitation, err := f.svc.InviteMember(f.as(f.owner), f.org.ID, domain.InviteMemberRequest{Email: "bob@example.com"})
+		if err != nil {
+			t.Fatalf("InviteMember: %v", err)
+		}
+		return invitation
+	}
+
+	declined := invite()
+	if err := f.svc.DeclineInvitation(f.as(bob), declined.ID); err != nil {
+		t.Fatalf("DeclineInvitation: %v", err)
+	}
+	revoked := invite()
+	if err := f.svc.RevokeInvitation(f.as(f.owner), f.org.ID, revoked.ID); err != nil {
+		t.Fatalf("RevokeInvitation: %v", err)
+	}
+
+	for _, id := range []uint{declined.ID, revoked.ID} {
+		if _, err := f.svc.AcceptInvitation(f.as(bob), id); !errors.Is(err, domain.ErrOrgInvitationNotFound) {
+			t.Errorf("invitation %d: got %v, want ErrOrgInvitationNotFound", id, err)
+		}
+	}
+	if f.isMember(t, bob) {
+		t.Error("bob joined")
+	}
+}
+
+func TestInviteMemberChecksCallerRole(t *testing.T) {
+	f := newFixture(t)
+	member := f.createUser(t, "member@example.com", true)
+	if err := f.repo.AddMember(context.Background(), &domain.OrganizationMember{OrganizationID: f.org.ID, UserID: member.ID, Role: domain.OrgRoleMember}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := f.svc.InviteMember(f.as(member), f.org.ID, domain.InviteMemberRequest{Email: "bob@example.com"})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("member inviting: got %v, want ErrForbidden", err)
+	}
+	_, err = f.svc.InviteMember(f.as(f.owner), f.org.ID, domain.InviteMemberRequest{Email: "member@example.com"})
+	var apiErr *domain.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 409 {
+		t.Errorf("inviting a member: got %v, want a conflict", err)
+	}
+}
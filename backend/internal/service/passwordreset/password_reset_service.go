@@ -0,0 +1,227 @@
+package passwordreset
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/repository/interfaces"
+	passwordResetInterface "tech-test/backend/internal/service/interfaces/passwordreset"
+	userInterface "tech-test/backend/internal/service/interfaces/user"
+)
+
+const (
+	// resendInterval limits how often an account is sent a reset link.
+	resendInterval = time.Minute
+	// sendTimeout bounds the background lookup and delivery of a link.
+	sendTimeout = time.Minute
+)
+
+type service struct {
+	repo     interfaces.PasswordResetRepository
+	userRepo interfaces.UserRepository
+	users    userInterface.UserWriter
+	mailer   mailer.Mailer
+	resetURL string
+	ttl      time.Duration
+	logger   *zap.Logger
+
+	// pending tracks reset emails still being sent.
+	pending sync.WaitGroup
+}
+
+// NewService creates the password reset service. resetURL is the frontend
+// page that receives the token as a "token" query parameter. mailer may be
+// nil, in which case resets are rejected as unavailable.
+func NewService(
+	repo interfaces.PasswordResetRepository,
+	userRepo interfaces.UserRepository,
+	users userInterface.UserWriter,
+	mailer mailer.Mailer,
+	resetURL string,
+	ttl time.Duration,
+	logger *zap.Logger,
+) passwordResetInterface.Service {
+	return &service{
+		repo:     repo,
+		userRepo: userRepo,
+		users:    users,
+		mailer:   mailer,
+		resetURL: resetURL,
+		ttl:      ttl,
+		logger:   logger,
+	}
+}
+
+func (s *service) RequestReset(ctx context.Context, email string) error {
+	if s.mailer == nil {
+		return domain.NewAPIError(
+			503,
+			domain.ErrCodeInternal,
+			"Email delivery is not configured",
+			nil,
+		)
+	}
+
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return domain.NewInvalidInputError("email is required")
+	}
+
+	// The lookup and delivery happen after the response, so neither its
+	// content nor its timing tells registered and unknown addresses apart.
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+		defer cancel()
+		s.sendReset(ctx, email)
+	}()
+	return nil
+}
+
+// sendReset emails a reset link to the account registered with email, if
+// there is one. Failures are only logged, as the caller has already been
+// answered.
+func (s *service) sendReset(ctx context.Context, email string) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			s.logger.Debug("Password reset requested for unknown email")
+		} else {
+			s.logger.Error("Failed to look up password reset email", zap.Error(err))
+		}
+		return
+	}
+
+	// Silently, as refusing would reveal the address is registered.
+	last, err := s.repo.LatestCreatedAt(ctx, user.ID)
+	if err != nil {
+		s.logger.Error("Failed to check recent password resets", zap.Uint("userID", user.ID), zap.Error(err))
+		return
+	}
+	if last != nil && time.Since(*last) < resendInterval {
+		s.logger.Info("Password reset requested again too soon", zap.Uint("userID", user.ID))
+		return
+	}
+
+	raw, err := s.issue(ctx, user.ID)
+	if err != nil {
+		s.logger.Error("Failed to issue password reset token", zap.Uint("userID", user.ID), zap.Error(err))
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nSomeone asked to reset the password for your account. "+
+				"To choose a new password, open this link within %s:\n\n%s\n\n"+
+				"If this wasn't you, you can ignore this email; your password has not changed.\n",
+			user.FirstName, s.ttl, s.link(raw)),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.Error("Failed to send password reset email",
+			zap.Uint("userID", user.ID),
+			zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Password reset requested", zap.Uint("userID", user.ID))
+}
+
+// BeginForcedChange issues a reset token for a user who has just proved
+// their password but must replace it before being given a session.
+func (s *service) BeginForcedChange(ctx context.Context, user *domain.User) (*domain.PasswordChangeChallenge, error) {
+	raw, err := s.issue(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Password change required at login", zap.Uint("userID", user.ID))
+	return &domain.PasswordChangeChallenge{
+		PasswordChangeRequired: true,
+		ResetToken:             raw,
+		ExpiresIn:              int64(s.ttl.Seconds()),
+	}, nil
+}
+
+func (s *service) ResetPassword(ctx context.Context, token, password string) error {
+	if token == "" {
+		return domain.ErrInvalidResetToken
+	}
+
+	consumed, err := s.repo.Consume(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	if err := s.users.SetPassword(ctx, consumed.UserID, password); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrInvalidResetToken
+		}
+		// A password the policy refuses should not cost the user their link.
+		var apiErr *domain.APIError
+		if errors.As(err, &apiErr) && apiErr.Code == domain.ErrCodeInvalidInput {
+			if releaseErr := s.repo.Release(ctx, consumed.ID); releaseErr != nil {
+				s.logger.Error("Failed to release password reset token",
+					zap.Uint("userID", consumed.UserID),
+					zap.Error(releaseErr))
+			}
+		}
+		return err
+	}
+	// Older links the user requested are no longer needed.
+	if err := s.repo.DeleteByUser(ctx, consumed.UserID); err != nil {
+		s.logger.Warn("Failed to delete remaining reset tokens",
+			zap.Uint("userID", consumed.UserID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Password reset completed", zap.Uint("userID", consumed.UserID))
+	return nil
+}
+
+// issue stores a new reset token for userID and returns it.
+func (s *service) issue(ctx context.Context, userID uint) (string, error) {
+	raw, err := randomToken()
+	if err != nil {
+		return "", domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to generate reset token", err)
+	}
+	token := &domain.PasswordResetToken{
+		UserID:    userID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().UTC().Add(s.ttl),
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (s *service) link(token string) string {
+	return s.resetURL + "?token=" + url.QueryEscape(token)
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,318 @@
+package passwordreset
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/jwtkeys"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/passwordpolicy"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/service/audit"
+	tokenInterface "tech-test/backend/internal/service/interfaces/token"
+	"tech-test/backend/internal/service/token"
+	"tech-test/backend/internal/service/user"
+	"tech-test/backend/internal/testutil"
+	"tech-test/backend/internal/utils"
+)
+
+const (
+	testResetURL = "https://files.example.com/reset-password"
+	newPassword  = "N3w-Passw0rd!"
+)
+
+type fixture struct {
+	*testutil.Env
+	svc    *service
+	repo   interfaces.PasswordResetRepository
+	tokens tokenInterface.Service
+	user   *domain.User
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	logger := zap.NewNop()
+	jwtConfig := config.JWTConfig{
+		Secret:          "test-secret",
+		Issuer:          "test",
+		Audience:        "test",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+	}
+	keys, err := jwtkeys.Load(jwtConfig)
+	if err != nil {
+		t.Fatalf("failed to load keys: %v", err)
+	}
+	policy, err := passwordpolicy.New(config.PasswordPolicyConfig{MinLength: 8, MaxLength: 128, RequireDigit: true})
+	if err != nil {
+		t.Fatalf("failed to build password policy: %v", err)
+	}
+	hash, err := utils.HashPassword("0ld-Passw0rd!")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	env := testutil.NewEnv(t)
+	f := &fixture{
+		Env:  env,
+		repo: sqlite.NewPasswordResetRepository(env.DB),
+		user: env.CreateUser(t, &domain.User{Email: "alice@example.com", Password: hash, FirstName: "Alice", Surname: "Example"}),
+	}
+	f.tokens = token.NewService(sqlite.NewTokenRepository(env.DB), f.Users, jwtConfig, keys, logger)
+	users := user.NewService(f.Users, logger, nil, f.tokens, policy, audit.NewService(sqlite.NewAuditRepository(env.DB), logger))
+	f.svc = NewService(f.repo, f.Users, users, f.Mailer(), testResetURL, time.Hour, logger).(*service)
+	return f
+}
+
+var tokenPattern = regexp.MustCompile(`\?token=(\S+)`)
+
+// requestToken asks for a reset and returns the token from the email.
+func (f *fixture) requestToken(t *testing.T) string {
+	t.Helper()
+
+	before := len(f.Sink.Messages())
+	f.allowResend(t)
+	f.request(t, f.user.Email)
+	messages := f.Sink.Messages()
+	if len(messages) != before+1 {
+		t.Fatalf("sink received %d new messages, want 1", len(messages)-before)
+	}
+	match := tokenPattern.FindStringSubmatch(messages[len(messages)-1].Body)
+	if match == nil {
+		t.Fatalf("no reset link in email:\n%s", messages[len(messages)-1].Body)
+	}
+	raw, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("invalid token in link: %v", err)
+	}
+	return raw
+}
+
+// request asks for a reset and waits for the email to be sent.
+func (f *fixture) request(t *testing.T, email string) {
+	t.Helper()
+
+	if err := f.svc.RequestReset(context.Background(), email); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	f.svc.pending.Wait()
+}
+
+// allowResend backdates the user's reset tokens past the resend interval.
+func (f *fixture) allowResend(t *testing.T) {
+	t.Helper()
+
+	err := f.DB.Model(&domain.PasswordResetToken{}).
+		Where("user_id = ?", f.user.ID).
+		Update("created_at", time.Now().UTC().Add(-resendInterval)).Error
+	if err != nil {
+		t.Fatalf("failed to backdate reset tokens: %v", err)
+	}
+}
+
+func (f *fixture) password(t *testing.T) string {
+	t.Helper()
+
+	stored, err := f.Users.GetByID(context.Background(), f.user.ID)
+	if err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	return stored.Password
+}
+
+func TestRequestResetEmailsLink(t *testing.T) {
+	f := newFixture(t)
+	raw := f.requestToken(t)
+
+	msg := f.Sink.Messages()[0]
+	if len(msg.To) != 1 || msg.To[0] != f.user.Email {
+		t.Errorf("sent to %v, want %s", msg.To, f.user.Email)
+	}
+	if got := msg.Header.Get("Subject"); got != "Reset your password" {
+		t.Errorf("subject = %q", got)
+	}
+	if !regexp.MustCompile(regexp.QuoteMeta(testResetURL) + `\?token=`).MatchString(msg.Body) {
+		t.Errorf("link does not point at the reset page:\n%s", msg.Body)
+	}
+
+	// Only the hash is stored, so the emailed token cannot be read back.
+	if _, err := f.repo.Consume(context.Background(), raw); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Errorf("raw token accepted as a hash: %v", err)
+	}
+}
+
+func TestRequestResetUnknownEmail(t *testing.T) {
+	f := newFixture(t)
+
+	f.request(t, "nobody@example.com")
+	if got := len(f.Sink.Messages()); got != 0 {
+		t.Errorf("sink received %d messages, want 0", got)
+	}
+}
+
+func TestRequestResetIsRateLimited(t *testing.T) {
+	f := newFixture(t)
+	f.requestToken(t)
+
+	// Answered like any other request, but no second email goes out.
+	f.request(t, f.user.Email)
+	if got := len(f.Sink.Messages()); got != 1 {
+		t.Fatalf("sink received %d messages, want 1", got)
+	}
+
+	f.requestToken(t)
+	if got := len(f.Sink.Messages()); got != 2 {
+		t.Errorf("sink received %d messages after the interval, want 2", got)
+	}
+}
+
+func TestRequestResetDoesNotWaitForDelivery(t *testing.T) {
+	f := newFixture(t)
+	// A relay that accepts connections but never answers. Cleanups run in
+	// reverse, so closing it ends the delivery before the test waits for it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(f.svc.pending.Wait)
+	t.Cleanup(func() { listener.Close() })
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	f.svc.mailer = mailer.NewSMTPMailer(config.MailConfig{Host: host, Port: port, From: "noreply@example.com"})
+
+	start := time.Now()
+	if err := f.svc.RequestReset(context.Background(), f.user.Email); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("RequestReset took %v waiting on the relay", elapsed)
+	}
+}
+
+func TestRequestResetWithoutMailer(t *testing.T) {
+	f := newFixture(t)
+	f.svc.mailer = nil
+
+	var apiErr *domain.APIError
+	if err := f.svc.RequestReset(context.Background(), f.user.Email); !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		t.Fatalf("got %v, want 503", err)
+	}
+}
+
+func TestResetPasswordIsSingleUse(t *testing.T) {
+	f := newFixture(t)
+	raw := f.requestToken(t)
+
+	if err := f.svc.ResetPassword(context.Background(), raw, newPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if !utils.CheckPasswordHash(newPassword, f.password(t)) {
+		t.Fatal("password was not changed")
+	}
+
+	err := f.svc.ResetPassword(context.Background(), raw, "An0ther-Passw0rd!")
+	if !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("second use: got %v, want invalid token", err)
+	}
+	if !utils.CheckPasswordHash(newPassword, f.password(t)) {
+		t.Error("second use changed the password")
+	}
+}
+
+func TestResetPasswordInvalidatesOtherLinks(t *testing.T) {
+	f := newFixture(t)
+	first := f.requestToken(t)
+	second := f.requestToken(t)
+
+	if err := f.svc.ResetPassword(context.Background(), second, newPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if err := f.svc.ResetPassword(context.Background(), first, "An0ther-Passw0rd!"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("older link: got %v, want invalid token", err)
+	}
+}
+
+func TestResetPasswordExpiredToken(t *testing.T) {
+	f := newFixture(t)
+
+	raw := "expired-token"
+	if err := f.repo.Create(context.Background(), &domain.PasswordResetToken{
+		UserID:    f.user.ID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().UTC().Add(-time.Minute),
+	}); err != nil {
+		t.Fatalf("failed to store token: %v", err)
+	}
+
+	before := f.password(t)
+	if err := f.svc.ResetPassword(context.Background(), raw, newPassword); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("got %v, want invalid token", err)
+	}
+	if f.password(t) != before {
+		t.Error("expired token changed the password")
+	}
+}
+
+func TestResetPasswordUnknownToken(t *testing.T) {
+	f := newFixture(t)
+
+	for _, raw := range []string{"", "not-a-token"} {
+		if err := f.svc.ResetPassword(context.Background(), raw, newPassword); !errors.Is(err, domain.ErrInvalidResetToken) {
+			t.Errorf("token %q: got %v, want invalid token", raw, err)
+		}
+	}
+}
+
+func TestResetPasswordRefusedByPolicyKeepsToken(t *testing.T) {
+	f := newFixture(t)
+	raw := f.requestToken(t)
+
+	if err := f.svc.ResetPassword(context.Background(), raw, "short"); err == nil {
+		t.Fatal("weak password accepted")
+	}
+	if err := f.svc.ResetPassword(context.Background(), raw, newPassword); err != nil {
+		t.Fatalf("retry with a valid password: %v", err)
+	}
+}
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	f := newFixture(t)
+
+	ctx := context.Background()
+	client := domain.SessionClient{UserAgent: "test", IP: "203.0.113.7"}
+	var pairs []*domain.TokenPair
+	for i := 0; i < 2; i++ {
+		pair, err := f.tokens.Issue(ctx, f.user, client)
+		if err != nil {
+			t.Fatalf("Issue: %v", err)
+		}
+		pairs = append(pairs, pair)
+	}
+
+	raw := f.requestToken(t)
+	if err := f.svc.ResetPassword(ctx, raw, newPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	sessions, err := f.tokens.ListSessions(ctx, f.user.ID, "")
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("%d sessions still active after reset", len(sessions))
+	}
+	for i, pair := range pairs {
+		if _, err := f.tokens.Refresh(ctx, pair.RefreshToken, client); err == nil {
+			t.Errorf("session %d could still refresh after reset", i)
+		}
+	}
+}
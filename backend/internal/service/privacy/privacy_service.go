@@ -0,0 +1,503 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	auditInterface "tech-test/backend/internal/service/interfaces/audit"
+	privacyInterface "tech-test/backend/internal/service/interfaces/privacy"
+	tokenInterface "tech-test/backend/internal/service/interfaces/token"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+	maxReasonLength  = 500
+	// maxExportedAttempts caps the login history included in an export.
+	maxExportedAttempts = 1000
+)
+
+// Repositories are the stores a user's data is gathered from.
+type Repositories struct {
+	Users           interfaces.UserRepository
+	Files           interfaces.FileRepository
+	ShareRecipients interfaces.ShareRecipientRepository
+	Collections     interfaces.CollectionRepository
+	UploadRequests  interfaces.UploadRequestRepository
+	Organizations   interfaces.OrganizationRepository
+	Tokens          interfaces.TokenRepository
+	AccessTokens    interfaces.AccessTokenRepository
+	LoginGuard      interfaces.LoginGuardRepository
+}
+
+type service struct {
+	repo        interfaces.ErasureRepository
+	stores      Repositories
+	sessions    tokenInterface.Revoker
+	audit       auditInterface.Service
+	gracePeriod time.Duration
+	logger      *zap.Logger
+}
+
+// NewService returns a privacy service. Erasures are carried out
+// gracePeriod after they are requested.
+func NewService(repo interfaces.ErasureRepository, stores Repositories, sessions tokenInterface.Revoker, audit auditInterface.Service, gracePeriod time.Duration, logger *zap.Logger) privacyInterface.Service {
+	return &service{
+		repo:        repo,
+		stores:      stores,
+		sessions:    sessions,
+		audit:       audit,
+		gracePeriod: gracePeriod,
+		logger:      logger,
+	}
+}
+
+func (s *service) Export(ctx context.Context, userID uint) (*domain.UserExport, error) {
+	user, err := s.stores.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile := *user
+	profile.Password = ""
+
+	export := &domain.UserExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    &profile,
+	}
+	if export.Files, err = s.stores.Files.GetByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	for _, file := range export.Files {
+		recipients, err := s.stores.ShareRecipients.GetByFileID(ctx, file.ID)
+		if err != nil {
+			return nil, err
+		}
+		export.ShareRecipients = append(export.ShareRecipients, recipients...)
+	}
+	if export.Collections, err = s.stores.Collections.GetByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.UploadRequests, err = s.stores.UploadRequests.GetByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Organizations, err = s.stores.Organizations.ListForUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Sessions, err = s.stores.Tokens.ListActiveSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.AccessTokens, err = s.stores.AccessTokens.ListByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	export.LoginAttempts, err = s.stores.LoginGuard.ListAttempts(ctx, domain.LoginAttemptFilter{
+		Email: normalizeEmail(user.Email),
+		Limit: maxExportedAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	export.Erasure, err = s.repo.GetPending(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrErasureNotFound) {
+		return nil, err
+	}
+
+	s.logger.Info("User data exported", zap.Uint("userID", userID))
+	return export, nil
+}
+
+func (s *service) RequestErasure(ctx context.Context, userID uint, req domain.RequestErasureRequest) (*domain.ErasureRequest, error) {
+	actor := domain.ActorFromContext(ctx)
+	if actor.UserID == 0 {
+		return nil, domain.ErrUnauthorized
+	}
+	if actor.UserID != userID && !actor.Admin {
+		return nil, domain.ErrForbidden
+	}
+	if req.Immediate && !actor.Admin {
+		return nil, domain.NewInvalidInputError("only admins can skip the grace period")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > maxReasonLength {
+		return nil, domain.NewInvalidInputError("reason must be at most 500 characters")
+	}
+
+	user, err := s.stores.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkErasable(ctx, user); err != nil {
+		return nil, err
+	}
+	if pending, err := s.repo.GetPending(ctx, userID); err == nil {
+		if req.Immediate {
+			return pending, s.erase(ctx, pending)
+		}
+		return nil, domain.NewAPIError(
+			409,
+			domain.ErrCodeConflict,
+			"Erasure has already been requested",
+			nil,
+		)
+	} else if !errors.Is(err, domain.ErrErasureNotFound) {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	erasure := &domain.ErasureRequest{
+		UserID:       userID,
+		Status:       domain.ErasurePending,
+		RequestedBy:  actor.UserID,
+		Reason:       reason,
+		ScheduledFor: now.Add(s.gracePeriod),
+	}
+	if req.Immediate {
+		erasure.ScheduledFor = now
+	}
+	if err := s.repo.Create(ctx, erasure); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Erasure requested",
+		zap.Uint("erasureID", erasure.ID),
+		zap.Uint("userID", userID),
+		zap.Uint("requestedBy", actor.UserID),
+		zap.Time("scheduledFor", erasure.ScheduledFor))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditErasureRequested,
+		ActorID:   actor.UserID,
+		SubjectID: userID,
+		Details:   reason,
+	})
+
+	if req.Immediate {
+		if err := s.erase(ctx, erasure); err != nil {
+			return nil, err
+		}
+	}
+	return erasure, nil
+}
+
+func (s *service) GetErasure(ctx context.Context, userID uint) (*domain.ErasureRequest, error) {
+	return s.repo.GetPending(ctx, userID)
+}
+
+func (s *service) CancelErasure(ctx context.Context, userID uint) error {
+	actor := domain.ActorFromContext(ctx)
+	if actor.UserID != userID && !actor.Admin {
+		return domain.ErrForbidden
+	}
+
+	erasure, err := s.repo.GetPending(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetStatus(ctx, erasure.ID, domain.ErasureCancelled, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	s.logger.Info("Erasure cancelled",
+		zap.Uint("erasureID", erasure.ID),
+		zap.Uint("userID", userID),
+		zap.Uint("cancelledBy", actor.UserID))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditErasureCancelled,
+		ActorID:   actor.UserID,
+		SubjectID: userID,
+	})
+	return nil
+}
+
+func (s *service) ListErasures(ctx context.Context, limit int) ([]domain.ErasureRequest, error) {
+	switch {
+	case limit <= 0:
+		limit = defaultListLimit
+	case limit > maxListLimit:
+		limit = maxListLimit
+	}
+	return s.repo.List(ctx, limit)
+}
+
+func (s *service) ProcessDue(ctx context.Context) error {
+	due, err := s.repo.ListDue(ctx, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for i := range due {
+		if err := s.erase(ctx, &due[i]); err != nil {
+			failed++
+			s.logger.Error("Failed to erase user",
+				zap.Uint("erasureID", due[i].ID),
+				zap.Uint("userID", due[i].UserID),
+				zap.Error(err))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d erasures failed", failed, len(due))
+	}
+	return nil
+}
+
+// erase carries out a pending request. Each step can be repeated, so a
+// request that fails part way is finished by the next attempt.
+func (s *service) erase(ctx context.Context, erasure *domain.ErasureRequest) error {
+	user, err := s.stores.Users.GetByID(ctx, erasure.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		// The account went on an earlier attempt; only the bookkeeping is
+		// left.
+		return s.complete(ctx, erasure)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.checkLastAdmin(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.sessions.RevokeUser(ctx, user.ID); err != nil {
+		return err
+	}
+	orphaned, err := s.handOverOrganizations(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	files, err := s.stores.Files.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	personal, err := s.handOverFiles(ctx, user.ID, files, orphaned)
+	if err != nil {
+		return err
+	}
+	// Nobody is left to hand the files of the user's own organizations to,
+	// so they go with the user, including any that former members left.
+	removed := personal
+	for _, orgID := range orphaned {
+		orgFiles, err := s.repo.ListOrganizationFiles(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		removed = append(removed, orgFiles...)
+	}
+
+	// Blobs go before the rows pointing at them, so that a failure leaves
+	// nothing on disk that the retry could no longer find. Organizations go
+	// before the user's memberships, which the retry finds them by.
+	for _, file := range removed {
+		if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove file %d: %w", file.ID, err)
+		}
+	}
+	for _, orgID := range orphaned {
+		if err := s.repo.PurgeOrganization(ctx, orgID); err != nil {
+			return err
+		}
+		s.logger.Info("Organization deleted on erasure",
+			zap.Uint("organizationID", orgID),
+			zap.Uint("userID", user.ID))
+	}
+	if err := s.repo.PurgeUserData(ctx, user.ID, normalizeEmail(user.Email)); err != nil {
+		return err
+	}
+
+	if err := s.stores.Users.Delete(ctx, user.ID); err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return err
+	}
+	s.logger.Info("User erased",
+		zap.Uint("erasureID", erasure.ID),
+		zap.Uint("userID", user.ID),
+		zap.Int("files", len(removed)))
+	return s.complete(ctx, erasure)
+}
+
+func (s *service) complete(ctx context.Context, erasure *domain.ErasureRequest) error {
+	now := time.Now().UTC()
+	if err := s.repo.SetStatus(ctx, erasure.ID, domain.ErasureCompleted, now); err != nil {
+		return err
+	}
+	erasure.Status = domain.ErasureCompleted
+	erasure.CompletedAt = &now
+
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditErasureCompleted,
+		ActorID:   erasure.RequestedBy,
+		SubjectID: erasure.UserID,
+	})
+	return nil
+}
+
+// checkErasable refuses erasures that would leave the site without an
+// admin or an organization with members but no owner.
+func (s *service) checkErasable(ctx context.Context, user *domain.User) error {
+	if err := s.checkLastAdmin(ctx, user); err != nil {
+		return err
+	}
+
+	orgs, err := s.stores.Organizations.ListForUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, org := range orgs {
+		if org.Role != domain.OrgRoleOwner {
+			continue
+		}
+		members, err := s.stores.Organizations.ListMembers(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+		if len(members) > 1 && !hasOtherOwner(members, user.ID) {
+			return domain.NewAPIError(
+				409,
+				domain.ErrCodeConflict,
+				fmt.Sprintf("Make someone else an owner of %s first", org.Name),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+func (s *service) checkLastAdmin(ctx context.Context, user *domain.User) error {
+	if !user.IsAdmin() {
+		return nil
+	}
+	users, err := s.stores.Users.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if domain.IsLastAdmin(users, user.ID) {
+		return domain.ErrLastAdmin
+	}
+	return nil
+}
+
+// handOverOrganizations makes sure every organization the user owns keeps
+// an owner once they are gone, promoting its longest-standing admin, or
+// failing that member, if they were the only one. It returns the
+// organizations the user is the only member of, which are deleted with
+// them.
+func (s *service) handOverOrganizations(ctx context.Context, userID uint) ([]uint, error) {
+	orgs, err := s.stores.Organizations.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned []uint
+	for _, org := range orgs {
+		members, err := s.stores.Organizations.ListMembers(ctx, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) == 1 {
+			orphaned = append(orphaned, org.ID)
+			continue
+		}
+		if org.Role != domain.OrgRoleOwner || hasOtherOwner(members, userID) {
+			continue
+		}
+
+		successor := successorOf(members, userID)
+		if err := s.stores.Organizations.UpdateMemberRole(ctx, org.ID, successor.UserID, domain.OrgRoleOwner); err != nil {
+			return nil, err
+		}
+		s.logger.Info("Organization handed over on erasure",
+			zap.Uint("organizationID", org.ID),
+			zap.Uint("from", userID),
+			zap.Uint("to", successor.UserID))
+	}
+	return orphaned, nil
+}
+
+// handOverFiles gives the files the user uploaded to organizations to an
+// owner of each, so that erasing a member does not delete team data, and
+// returns the user's personal files. Files of the orphaned organizations,
+// which are deleted with the user, are left alone. Files of an organization
+// left without an owner stay with it until an admin deals with them.
+func (s *service) handOverFiles(ctx context.Context, userID uint, files []domain.File, orphaned []uint) ([]domain.File, error) {
+	var personal []domain.File
+	handled := make(map[uint]bool)
+	for _, orgID := range orphaned {
+		handled[orgID] = true
+	}
+	for _, file := range files {
+		if file.OrganizationID == nil {
+			personal = append(personal, file)
+			continue
+		}
+		orgID := *file.OrganizationID
+		if handled[orgID] {
+			continue
+		}
+		handled[orgID] = true
+
+		members, err := s.stores.Organizations.ListMembers(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+		owner, ok := ownerOf(members, userID)
+		if !ok {
+			s.logger.Warn("Left organization files without an owner to hand them to",
+				zap.Uint("organizationID", orgID),
+				zap.Uint("userID", userID))
+			continue
+		}
+		if err := s.repo.HandOverFiles(ctx, userID, orgID, owner); err != nil {
+			return nil, err
+		}
+		s.logger.Info("Organization files handed over on erasure",
+			zap.Uint("organizationID", orgID),
+			zap.Uint("from", userID),
+			zap.Uint("to", owner))
+	}
+	return personal, nil
+}
+
+func hasOtherOwner(members []domain.OrganizationMember, userID uint) bool {
+	for _, m := range members {
+		if m.UserID != userID && m.Role == domain.OrgRoleOwner {
+			return true
+		}
+	}
+	return false
+}
+
+// ownerOf returns an owner of the organization other than userID.
+func ownerOf(members []domain.OrganizationMember, userID uint) (uint, bool) {
+	for _, m := range members {
+		if m.UserID != userID && m.Role == domain.OrgRoleOwner {
+			return m.UserID, true
+		}
+	}
+	return 0, false
+}
+
+// successorOf picks the first admin other than userID, or the first member
+// if there is none. Members are listed in the order they joined.
+func successorOf(members []domain.OrganizationMember, userID uint) domain.OrganizationMember {
+	var successor *domain.OrganizationMember
+	for i, m := range members {
+		if m.UserID == userID {
+			continue
+		}
+		if m.Role == domain.OrgRoleAdmin {
+			return m
+		}
+		if successor == nil {
+			successor = &members[i]
+		}
+	}
+	return *successor
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
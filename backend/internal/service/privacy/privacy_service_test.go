@@ -0,0 +1,136 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/service/audit"
+	"tech-test/backend/internal/testutil"
+)
+
+// revoker stands in for the token service, which erasure only asks to end
+// the user's sessions.
+type revoker struct{}
+
+func (revoker) RevokeUser(ctx context.Context, userID uint) error { return nil }
+
+func (revoker) RevokeOtherSessions(ctx context.Context, userID uint, keep string) error { return nil }
+
+type fixture struct {
+	*testutil.Env
+	repo  interfaces.ErasureRepository
+	orgs  interfaces.OrganizationRepository
+	files interfaces.FileRepository
+	svc   *service
+	dir   string
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	env := testutil.NewEnv(t)
+	logger := zap.NewNop()
+	f := &fixture{
+		Env:   env,
+		repo:  sqlite.NewErasureRepository(env.DB),
+		orgs:  sqlite.NewOrganizationRepository(env.DB),
+		files: sqlite.NewFileRepository(env.DB),
+		dir:   t.TempDir(),
+	}
+	stores := Repositories{Users: f.Users, Files: f.files, Organizations: f.orgs}
+	f.svc = NewService(f.repo, stores, revoker{}, audit.NewService(sqlite.NewAuditRepository(env.DB), logger), 0, logger).(*service)
+	return f
+}
+
+func (f *fixture) createOrg(t *testing.T, name string, members ...*domain.User) *domain.Organization {
+	t.Helper()
+	org := &domain.Organization{Name: name}
+	if err := f.orgs.Create(context.Background(), org, &domain.OrganizationMember{UserID: members[0].ID, Role: domain.OrgRoleOwner}); err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	for _, m := range members[1:] {
+		if err := f.orgs.AddMember(context.Background(), &domain.OrganizationMember{OrganizationID: org.ID, UserID: m.ID, Role: domain.OrgRoleMember}); err != nil {
+			t.Fatalf("failed to add member: %v", err)
+		}
+	}
+	return org
+}
+
+func (f *fixture) createFile(t *testing.T, uploader *domain.User, org *domain.Organization) *domain.File {
+	t.Helper()
+	path := filepath.Join(f.dir, time.Now().Format("150405.000000000"))
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file := &domain.File{UserID: uploader.ID, Name: "report.pdf", Path: path, MimeType: "application/pdf", ContentType: "application/pdf", Size: 4}
+	if org != nil {
+		file.OrganizationID = &org.ID
+	}
+	if err := f.files.Create(context.Background(), file); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	return file
+}
+
+func (f *fixture) erase(t *testing.T, user *domain.User) {
+	t.Helper()
+	erasure := &domain.ErasureRequest{UserID: user.ID, RequestedBy: user.ID, Status: domain.ErasurePending, ScheduledFor: time.Now().UTC()}
+	if err := f.repo.Create(context.Background(), erasure); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.svc.erase(context.Background(), erasure); err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+}
+
+func TestEraseRemovesOrganizationsLeftWithoutMembers(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	alice := f.CreateUser(t, &domain.User{Email: "alice@example.com", FirstName: "Alice"})
+	bob := f.CreateUser(t, &domain.User{Email: "bob@example.com", FirstName: "Bob"})
+
+	solo := f.createOrg(t, "Solo", alice, bob)
+	own := f.createFile(t, alice, solo)
+	// Bob uploaded a file and left, so Alice is the only member.
+	left := f.createFile(t, bob, solo)
+	if err := f.orgs.RemoveMember(ctx, solo.ID, bob.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	shared := f.createOrg(t, "Shared", bob, alice)
+	kept := f.createFile(t, alice, shared)
+
+	f.erase(t, alice)
+
+	if _, err := f.orgs.GetByID(ctx, solo.ID); err == nil {
+		t.Error("organization without members was kept")
+	}
+	for _, file := range []*domain.File{own, left} {
+		if _, err := f.files.GetByID(ctx, file.ID); err == nil {
+			t.Errorf("file %d of the deleted organization was kept", file.ID)
+		}
+		if _, err := os.Stat(file.Path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("blob of file %d was kept: %v", file.ID, err)
+		}
+	}
+
+	// Files in an organization with other members pass to its owner.
+	stored, err := f.files.GetByID(ctx, kept.ID)
+	if err != nil {
+		t.Fatalf("organization file was removed: %v", err)
+	}
+	if stored.UserID != bob.ID {
+		t.Errorf("file owner = %d, want %d", stored.UserID, bob.ID)
+	}
+	if _, err := os.Stat(kept.Path); err != nil {
+		t.Errorf("blob of a handed-over file was removed: %v", err)
+	}
+}
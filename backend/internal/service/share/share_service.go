@@ -0,0 +1,212 @@
+package share
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/repository/interfaces"
+	authzInterface "tech-test/backend/internal/service/interfaces/authz"
+	shareInterface "tech-test/backend/internal/service/interfaces/share"
+)
+
+const maxRecipients = 50
+
+type service struct {
+	fileRepo      interfaces.FileRepository
+	recipientRepo interfaces.ShareRecipientRepository
+	userRepo      interfaces.UserRepository
+	authorizer    authzInterface.Authorizer
+	mailer        mailer.Mailer
+	baseURL       string
+	logger        *zap.Logger
+}
+
+// NewService creates the share service. mailer may be nil, in which case
+// sharing by email is rejected.
+func NewService(
+	fileRepo interfaces.FileRepository,
+	recipientRepo interfaces.ShareRecipientRepository,
+	userRepo interfaces.UserRepository,
+	authorizer authzInterface.Authorizer,
+	mailer mailer.Mailer,
+	baseURL string,
+	logger *zap.Logger,
+) shareInterface.Service {
+	return &service{
+		fileRepo:      fileRepo,
+		recipientRepo: recipientRepo,
+		userRepo:      userRepo,
+		authorizer:    authorizer,
+		mailer:        mailer,
+		baseURL:       baseURL,
+		logger:        logger,
+	}
+}
+
+func (s *service) Notify(ctx context.Context, file *domain.File, req domain.ShareRequest) ([]domain.ShareRecipient, error) {
+	s.logger.Debug("Notifying share recipients",
+		zap.Uint("fileID", file.ID),
+		zap.Int("recipients", len(req.Recipients)))
+
+	if len(req.Recipients) == 0 {
+		return []domain.ShareRecipient{}, nil
+	}
+	emails, err := s.checkRecipients(ctx, file, req)
+	if err != nil {
+		return nil, err
+	}
+
+	senderName := s.displayName(ctx, file.UserID)
+
+	recipients := make([]domain.ShareRecipient, 0, len(emails))
+	for _, email := range emails {
+		recipient := domain.ShareRecipient{
+			FileID:   file.ID,
+			SharedBy: file.UserID,
+			Email:    email,
+			Token:    uuid.New().String(),
+			Message:  req.Message,
+		}
+		if err := s.recipientRepo.Create(ctx, &recipient); err != nil {
+			return nil, err
+		}
+
+		msg := mailer.Message{
+			To:      email,
+			Subject: fmt.Sprintf("%s shared \"%s\" with you", senderName, file.Name),
+			Body:    s.shareEmailBody(senderName, file, &recipient),
+		}
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			s.logger.Error("Failed to send share email",
+				zap.Uint("fileID", file.ID),
+				zap.String("email", email),
+				zap.Error(err))
+			recipient.SendError = err.Error()
+		} else {
+			sentAt := time.Now().UTC()
+			recipient.SentAt = &sentAt
+		}
+
+		if err := s.recipientRepo.Update(ctx, &recipient); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
+
+func (s *service) CheckRecipients(ctx context.Context, file *domain.File, req domain.ShareRequest) error {
+	_, err := s.checkRecipients(ctx, file, req)
+	return err
+}
+
+// checkRecipients returns the parsed recipient addresses once the actor may
+// share file and email can be delivered.
+func (s *service) checkRecipients(ctx context.Context, file *domain.File, req domain.ShareRequest) ([]string, error) {
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionShare, file); err != nil {
+		return nil, err
+	}
+	if s.mailer == nil {
+		return nil, domain.NewAPIError(
+			503,
+			domain.ErrCodeInternal,
+			"Email delivery is not configured",
+			nil,
+		)
+	}
+	if len(req.Recipients) > maxRecipients {
+		return nil, domain.NewInvalidInputError(fmt.Sprintf("at most %d recipients are allowed", maxRecipients))
+	}
+
+	emails := make([]string, 0, len(req.Recipients))
+	for _, raw := range req.Recipients {
+		addr, err := mail.ParseAddress(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, domain.NewInvalidInputError("invalid recipient email: " + raw)
+		}
+		emails = append(emails, addr.Address)
+	}
+	return emails, nil
+}
+
+func (s *service) ListRecipients(ctx context.Context, fileID uint) ([]domain.ShareRecipient, error) {
+	s.logger.Debug("Listing share recipients", zap.Uint("fileID", fileID))
+
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionRead, file); err != nil {
+		return nil, err
+	}
+	return s.recipientRepo.GetByFileID(ctx, fileID)
+}
+
+func (s *service) RecordAccess(ctx context.Context, fileID uint, token string) error {
+	s.logger.Debug("Recording share access", zap.Uint("fileID", fileID))
+	return s.recipientRepo.RecordAccess(ctx, fileID, token)
+}
+
+func (s *service) Preview(ctx context.Context, shareID string) (*domain.SharePreview, error) {
+	s.logger.Debug("Building share preview", zap.String("shareId", shareID))
+
+	file, err := s.fileRepo.GetFileByShareID(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if file.IsShareExpired() {
+		return nil, domain.ErrLinkExpired
+	}
+	if file.Quarantined {
+		return nil, domain.ErrFileQuarantined
+	}
+	if file.ShareDisabled {
+		return nil, domain.ErrShareDisabled
+	}
+
+	preview := &domain.SharePreview{
+		Name:        file.Name,
+		MimeType:    file.MimeType,
+		Size:        file.Size,
+		OwnerName:   s.displayName(ctx, file.UserID),
+		PageCount:   file.PageCount,
+		SharedAt:    file.UpdatedAt,
+		ExpiresAt:   file.ShareExpiresAt,
+		PreviewURL:  fmt.Sprintf("%s/shared/%s/preview", s.baseURL, shareID),
+		DownloadURL: fmt.Sprintf("%s/shared/%s", s.baseURL, shareID),
+	}
+
+	return preview, nil
+}
+
+// displayName returns the user's full name, falling back to a neutral
+// placeholder when the user cannot be loaded.
+func (s *service) displayName(ctx context.Context, userID uint) string {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "Someone"
+	}
+	if name := strings.TrimSpace(user.FirstName + " " + user.Surname); name != "" {
+		return name
+	}
+	return "Someone"
+}
+
+func (s *service) shareEmailBody(senderName string, file *domain.File, recipient *domain.ShareRecipient) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hello,\n\n%s has shared the file \"%s\" with you.\n\n", senderName, file.Name)
+	if recipient.Message != "" {
+		fmt.Fprintf(&b, "Their message:\n\n%s\n\n", recipient.Message)
+	}
+	fmt.Fprintf(&b, "Open it here:\n%s\n\n", recipient.Link(s.baseURL, file.ShareableID))
+	b.WriteString("This link is personal to you. Please do not forward it.\n")
+	return b.String()
+}
@@ -0,0 +1,233 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/service/authz"
+	shareInterface "tech-test/backend/internal/service/interfaces/share"
+	"tech-test/backend/internal/testutil"
+)
+
+type fixture struct {
+	*testutil.Env
+	files      interfaces.FileRepository
+	recipients interfaces.ShareRecipientRepository
+	orgs       interfaces.OrganizationRepository
+	owner      *domain.User
+	other      *domain.User
+	file       *domain.File
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	env := testutil.NewEnv(t)
+	f := &fixture{
+		Env:        env,
+		files:      sqlite.NewFileRepository(env.DB),
+		recipients: sqlite.NewShareRecipientRepository(env.DB),
+		orgs:       sqlite.NewOrganizationRepository(env.DB),
+		owner:      env.CreateUser(t, &domain.User{Email: "owner@example.com", FirstName: "Olive", Surname: "Owner"}),
+		other:      env.CreateUser(t, &domain.User{Email: "other@example.com", FirstName: "Otto", Surname: "Other"}),
+	}
+
+	f.file = &domain.File{
+		UserID:      f.owner.ID,
+		Name:        "report.pdf",
+		Path:        filepath.Join(t.TempDir(), "report.pdf"),
+		MimeType:    "application/pdf",
+		ContentType: "application/pdf",
+		Size:        1024,
+		ShareableID: "share-123",
+	}
+	if err := f.files.Create(context.Background(), f.file); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	return f
+}
+
+func (f *fixture) service(m mailer.Mailer) shareInterface.Service {
+	logger := zap.NewNop()
+	return NewService(f.files, f.recipients, f.Users, authz.NewAuthorizer(f.orgs, logger), m, testutil.BaseURL, logger)
+}
+
+func asUser(user *domain.User) context.Context {
+	return domain.ContextWithActor(context.Background(), domain.Actor{UserID: user.ID})
+}
+
+// statusOf returns the HTTP status of an API error, or zero.
+func statusOf(err error) int {
+	var apiErr *domain.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func TestNotifySendsPersonalLinks(t *testing.T) {
+	f := newFixture(t)
+	svc := f.service(f.Mailer())
+
+	req := domain.ShareRequest{
+		Recipients: []string{"alice@example.com", "Bob <bob@example.com>"},
+		Message:    "Figures for Q3",
+	}
+	recipients, err := svc.Notify(asUser(f.owner), f.file, req)
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(recipients) != 2 {
+		t.Fatalf("got %d recipients, want 2", len(recipients))
+	}
+
+	messages := f.Sink.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("sink received %d messages, want 2", len(messages))
+	}
+	for i, msg := range messages {
+		r := recipients[i]
+		if r.SentAt == nil || r.SendError != "" {
+			t.Errorf("recipient %s not marked sent: %+v", r.Email, r)
+		}
+		if len(msg.To) != 1 || msg.To[0] != r.Email {
+			t.Errorf("message %d sent to %v, want %s", i, msg.To, r.Email)
+		}
+		if got := msg.Header.Get("Subject"); !strings.Contains(got, "Olive Owner") || !strings.Contains(got, "report.pdf") {
+			t.Errorf("unexpected subject %q", got)
+		}
+		link := testutil.BaseURL + "/shared/share-123/preview?r=" + r.Token
+		if !strings.Contains(msg.Body, link) {
+			t.Errorf("body for %s does not contain %s:\n%s", r.Email, link, msg.Body)
+		}
+		if !strings.Contains(msg.Body, "Figures for Q3") {
+			t.Errorf("body for %s does not contain the message", r.Email)
+		}
+	}
+	if recipients[0].Token == recipients[1].Token {
+		t.Error("recipients share a token")
+	}
+}
+
+func TestNotifyRecordsDeliveryFailure(t *testing.T) {
+	f := newFixture(t)
+	f.Sink.Reject("gone@example.com")
+	svc := f.service(f.Mailer())
+
+	req := domain.ShareRequest{Recipients: []string{"gone@example.com", "alice@example.com"}}
+	recipients, err := svc.Notify(asUser(f.owner), f.file, req)
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if recipients[0].SentAt != nil || recipients[0].SendError == "" {
+		t.Errorf("rejected recipient not marked as failed: %+v", recipients[0])
+	}
+	if recipients[1].SentAt == nil {
+		t.Errorf("second recipient not sent: %+v", recipients[1])
+	}
+	if got := len(f.Sink.Messages()); got != 1 {
+		t.Errorf("sink received %d messages, want 1", got)
+	}
+}
+
+func TestCheckRecipients(t *testing.T) {
+	f := newFixture(t)
+	withMailer := f.service(f.Mailer())
+
+	tests := []struct {
+		name   string
+		svc    shareInterface.Service
+		ctx    context.Context
+		emails []string
+		status int
+	}{
+		{name: "valid", svc: withMailer, ctx: asUser(f.owner), emails: []string{"alice@example.com"}},
+		{name: "invalid address", svc: withMailer, ctx: asUser(f.owner), emails: []string{"alice@example.com", "not-an-email"}, status: 400},
+		{name: "not the owner", svc: withMailer, ctx: asUser(f.other), emails: []string{"alice@example.com"}, status: 404},
+		{name: "anonymous", svc: withMailer, ctx: context.Background(), emails: []string{"alice@example.com"}, status: 401},
+		{name: "no mailer", svc: f.service(nil), ctx: asUser(f.owner), emails: []string{"alice@example.com"}, status: 503},
+		{name: "too many", svc: withMailer, ctx: asUser(f.owner), emails: make([]string, maxRecipients+1), status: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.svc.CheckRecipients(tt.ctx, f.file, domain.ShareRequest{Recipients: tt.emails})
+			if tt.status == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := statusOf(err); got != tt.status {
+				t.Errorf("got %v (status %d), want status %d", err, got, tt.status)
+			}
+		})
+	}
+	if got := len(f.Sink.Messages()); got != 0 {
+		t.Errorf("CheckRecipients sent %d messages", got)
+	}
+}
+
+func TestNotifyRefusesInvalidRecipientsBeforeSending(t *testing.T) {
+	f := newFixture(t)
+	svc := f.service(f.Mailer())
+
+	req := domain.ShareRequest{Recipients: []string{"alice@example.com", "nope"}}
+	if _, err := svc.Notify(asUser(f.owner), f.file, req); err == nil {
+		t.Fatal("Notify accepted an invalid recipient")
+	}
+	if got := len(f.Sink.Messages()); got != 0 {
+		t.Errorf("sink received %d messages, want 0", got)
+	}
+	stored, err := f.recipients.GetByFileID(context.Background(), f.file.ID)
+	if err != nil {
+		t.Fatalf("GetByFileID: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("stored %d recipients, want 0", len(stored))
+	}
+}
+
+func TestRecordAccessCountsPerRecipient(t *testing.T) {
+	f := newFixture(t)
+	svc := f.service(f.Mailer())
+
+	recipients, err := svc.Notify(asUser(f.owner), f.file, domain.ShareRequest{
+		Recipients: []string{"alice@example.com", "bob@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := svc.RecordAccess(context.Background(), f.file.ID, recipients[0].Token); err != nil {
+			t.Fatalf("RecordAccess: %v", err)
+		}
+	}
+	if err := svc.RecordAccess(context.Background(), f.file.ID, "unknown"); statusOf(err) != 404 {
+		t.Errorf("unknown token: got %v, want not found", err)
+	}
+
+	listed, err := svc.ListRecipients(asUser(f.owner), f.file.ID)
+	if err != nil {
+		t.Fatalf("ListRecipients: %v", err)
+	}
+	counts := map[string]int{}
+	for _, r := range listed {
+		counts[r.Email] = r.AccessCount
+	}
+	if counts["alice@example.com"] != 2 || counts["bob@example.com"] != 0 {
+		t.Errorf("unexpected access counts %v", counts)
+	}
+	if _, err := svc.ListRecipients(asUser(f.other), f.file.ID); err == nil {
+		t.Error("another user could list the recipients")
+	}
+}
@@ -0,0 +1,284 @@
+package sso
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/oidc"
+	"tech-test/backend/internal/repository/interfaces"
+	invitationInterface "tech-test/backend/internal/service/interfaces/invitation"
+	ssoInterface "tech-test/backend/internal/service/interfaces/sso"
+)
+
+const (
+	// stateTTL is how long a user has to sign in at the provider.
+	stateTTL = 10 * time.Minute
+	// ticketTTL is how long the frontend has to exchange a ticket.
+	ticketTTL = time.Minute
+)
+
+type service struct {
+	repo            interfaces.OIDCRepository
+	userRepo        interfaces.UserRepository
+	users           invitationInterface.Provisioner
+	providers       map[string]*oidc.Provider
+	order           []string
+	redirectBaseURL string
+	logger          *zap.Logger
+}
+
+// NewService creates the single sign-on service. redirectBaseURL is the
+// public backend URL providers return users to. New accounts go through
+// users, so the registration policy applies to them too.
+func NewService(
+	repo interfaces.OIDCRepository,
+	userRepo interfaces.UserRepository,
+	users invitationInterface.Provisioner,
+	providers []*oidc.Provider,
+	redirectBaseURL string,
+	logger *zap.Logger,
+) ssoInterface.Service {
+	s := &service{
+		repo:            repo,
+		userRepo:        userRepo,
+		users:           users,
+		providers:       make(map[string]*oidc.Provider, len(providers)),
+		redirectBaseURL: strings.TrimSuffix(redirectBaseURL, "/"),
+		logger:          logger,
+	}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+		s.order = append(s.order, p.Name())
+	}
+	return s
+}
+
+func (s *service) Providers() []domain.OIDCProviderInfo {
+	infos := make([]domain.OIDCProviderInfo, 0, len(s.order))
+	for _, name := range s.order {
+		infos = append(infos, domain.OIDCProviderInfo{
+			Name:        name,
+			DisplayName: s.providers[name].DisplayName(),
+			LoginURL:    s.redirectBaseURL + "/api/auth/oidc/" + name + "/login",
+		})
+	}
+	return infos
+}
+
+func (s *service) BeginLogin(ctx context.Context, provider string) (string, string, error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	var values [3]string
+	for i := range values {
+		if values[i], err = oidc.RandomString(); err != nil {
+			return "", "", domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to start sign-in", err)
+		}
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	authURL, err := p.AuthCodeURL(ctx, s.redirectURI(provider), state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return "", "", s.providerError(provider, "Identity provider is unavailable", err)
+	}
+
+	err = s.repo.CreateState(ctx, &domain.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().UTC().Add(stateTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+func (s *service) CompleteLogin(ctx context.Context, provider, state, code string) (string, error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return "", err
+	}
+	if state == "" || code == "" {
+		return "", domain.ErrInvalidOIDCState
+	}
+
+	pending, err := s.repo.ConsumeState(ctx, hashToken(state))
+	if err != nil {
+		return "", err
+	}
+	if pending.Provider != provider {
+		return "", domain.ErrInvalidOIDCState
+	}
+
+	rawIDToken, err := p.Exchange(ctx, s.redirectURI(provider), code, pending.CodeVerifier)
+	if err != nil {
+		return "", s.providerError(provider, "Identity provider rejected the sign-in", err)
+	}
+	claims, err := p.VerifyIDToken(ctx, rawIDToken, pending.Nonce)
+	if err != nil {
+		return "", s.providerError(provider, "Identity provider returned an invalid ID token", err)
+	}
+
+	user, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		return "", err
+	}
+
+	ticket, err := oidc.RandomString()
+	if err != nil {
+		return "", domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to finish sign-in", err)
+	}
+	err = s.repo.CreateTicket(ctx, &domain.OIDCLoginTicket{
+		TokenHash: hashToken(ticket),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(ticketTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s.logger.Info("Signed in with identity provider",
+		zap.String("provider", provider),
+		zap.Uint("userID", user.ID))
+	return ticket, nil
+}
+
+func (s *service) ExchangeTicket(ctx context.Context, ticket string) (*domain.User, error) {
+	if ticket == "" {
+		return nil, domain.ErrInvalidOIDCTicket
+	}
+
+	consumed, err := s.repo.ConsumeTicket(ctx, hashToken(ticket))
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, consumed.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidOIDCTicket
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// resolveUser finds the account linked to the provider's subject. The first
+// time a subject signs in it is linked to the account with the same email,
+// as long as that account has verified it, or a new account is provisioned.
+func (s *service) resolveUser(ctx context.Context, provider string, claims *oidc.IDTokenClaims) (*domain.User, error) {
+	now := time.Now().UTC()
+
+	identity, err := s.repo.GetIdentity(ctx, provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		if err := s.repo.TouchIdentity(ctx, identity.ID, now); err != nil {
+			s.logger.Warn("Failed to record identity sign-in", zap.Uint("identityID", identity.ID), zap.Error(err))
+		}
+		return s.userRepo.GetByID(ctx, identity.UserID)
+	}
+
+	// Linking by email is only safe when the provider vouches for it.
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.ErrOIDCEmailUnverified
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		// An unverified account may have been registered by someone else
+		// to wait for the owner, so it must not inherit their sign-in.
+		if !user.IsEmailVerified() {
+			return nil, domain.ErrOIDCAccountUnverified
+		}
+	case errors.Is(err, domain.ErrUserNotFound):
+		if user, err = s.provision(ctx, claims, now); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	err = s.repo.CreateIdentity(ctx, &domain.UserIdentity{
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Linked identity provider account",
+		zap.String("provider", provider),
+		zap.Uint("userID", user.ID))
+	return user, nil
+}
+
+// provision creates an account for a first-time SSO user. It gets a random
+// password, so it can only sign in through the provider until the user
+// resets it.
+func (s *service) provision(ctx context.Context, claims *oidc.IDTokenClaims, now time.Time) (*domain.User, error) {
+	password, err := oidc.RandomString()
+	if err != nil {
+		return nil, domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to provision account", err)
+	}
+
+	firstName, surname := claims.GivenName, claims.FamilyName
+	if firstName == "" && surname == "" {
+		firstName, surname, _ = strings.Cut(strings.TrimSpace(claims.Name), " ")
+	}
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	user := &domain.User{
+		Email:           claims.Email,
+		Password:        password,
+		FirstName:       firstName,
+		Surname:         surname,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.users.Provision(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Provisioned user from identity provider", zap.Uint("userID", user.ID))
+	return user, nil
+}
+
+func (s *service) provider(name string) (*oidc.Provider, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, domain.NewNotFoundError("identity provider")
+	}
+	return p, nil
+}
+
+func (s *service) redirectURI(provider string) string {
+	return s.redirectBaseURL + "/api/auth/oidc/" + provider + "/callback"
+}
+
+// providerError logs the detailed failure and returns a generic one, since
+// provider responses can include details the user should not see.
+func (s *service) providerError(provider, message string, err error) error {
+	s.logger.Warn(message, zap.String("provider", provider), zap.Error(err))
+	return domain.NewAPIError(502, domain.ErrCodeAuthentication, message, err)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,287 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/oidc"
+	"tech-test/backend/internal/oidc/oidctest"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/testutil"
+)
+
+const providerName = "test"
+
+// provisioner creates accounts directly and records them, standing in for
+// the invitation service's registration policy.
+type provisioner struct {
+	users       interfaces.UserRepository
+	provisioned []*domain.User
+}
+
+func (p *provisioner) Provision(ctx context.Context, user *domain.User) error {
+	if err := p.users.Create(ctx, user); err != nil {
+		return err
+	}
+	p.provisioned = append(p.provisioned, user)
+	return nil
+}
+
+type fixture struct {
+	*testutil.Env
+	idp         *oidctest.Provider
+	repo        interfaces.OIDCRepository
+	provisioner *provisioner
+	svc         *service
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	env := testutil.NewEnv(t)
+	f := &fixture{
+		Env:         env,
+		idp:         oidctest.NewProvider(t),
+		repo:        sqlite.NewOIDCRepository(env.DB),
+		provisioner: &provisioner{users: env.Users},
+	}
+	providers := []*oidc.Provider{oidc.NewProvider(f.idp.Config(providerName), nil)}
+	f.svc = NewService(f.repo, f.Users, f.provisioner, providers, testutil.BaseURL, zap.NewNop()).(*service)
+	return f
+}
+
+func (f *fixture) createUser(t *testing.T, email string, verified bool) *domain.User {
+	t.Helper()
+	user := f.CreateUser(t, &domain.User{Email: email, FirstName: "Alice"})
+	if verified {
+		user = f.verify(t, user)
+	}
+	return user
+}
+
+func (f *fixture) verify(t *testing.T, user *domain.User) *domain.User {
+	t.Helper()
+	user.EmailVerifiedAt = &user.CreatedAt
+	if err := f.Users.Update(context.Background(), user.ID, user); err != nil {
+		t.Fatalf("failed to verify user: %v", err)
+	}
+	return user
+}
+
+func (f *fixture) identity(t *testing.T, subject string) *domain.UserIdentity {
+	t.Helper()
+	identity, err := f.repo.GetIdentity(context.Background(), providerName, subject)
+	if err != nil {
+		t.Fatalf("failed to get identity: %v", err)
+	}
+	return identity
+}
+
+func claims(subject, email string, verified bool) *oidc.IDTokenClaims {
+	return &oidc.IDTokenClaims{Subject: subject, Email: email, EmailVerified: verified, Name: "Alice Example"}
+}
+
+func TestResolveUserReturnsLinkedAccount(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	linked := f.createUser(t, "alice@example.com", true)
+	f.createUser(t, "alice@other.example.com", true)
+
+	if _, err := f.svc.resolveUser(ctx, providerName, claims("alice", "alice@example.com", true)); err != nil {
+		t.Fatalf("first sign-in: %v", err)
+	}
+
+	// Later sign-ins follow the subject, whatever the token's email says.
+	for _, c := range []*oidc.IDTokenClaims{
+		claims("alice", "alice@other.example.com", true),
+		claims("alice", "", false),
+	} {
+		user, err := f.svc.resolveUser(ctx, providerName, c)
+		if err != nil {
+			t.Fatalf("resolveUser(%q): %v", c.Email, err)
+		}
+		if user.ID != linked.ID {
+			t.Errorf("email %q: got user %d, want the linked user %d", c.Email, user.ID, linked.ID)
+		}
+	}
+	if len(f.provisioner.provisioned) != 0 {
+		t.Errorf("provisioned %d accounts, want none", len(f.provisioner.provisioned))
+	}
+}
+
+func TestResolveUserRejectsUnverifiedEmail(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.createUser(t, "alice@example.com", true)
+
+	for _, c := range []*oidc.IDTokenClaims{
+		claims("alice", "alice@example.com", false),
+		claims("bob", "bob@example.com", false),
+		claims("carol", "", true),
+	} {
+		_, err := f.svc.resolveUser(ctx, providerName, c)
+		if !errors.Is(err, domain.ErrOIDCEmailUnverified) {
+			t.Errorf("subject %q: got %v, want ErrOIDCEmailUnverified", c.Subject, err)
+		}
+		if f.identity(t, c.Subject) != nil {
+			t.Errorf("subject %q was linked", c.Subject)
+		}
+	}
+	if len(f.provisioner.provisioned) != 0 {
+		t.Errorf("provisioned %d accounts, want none", len(f.provisioner.provisioned))
+	}
+}
+
+func TestResolveUserLinksAccountWithVerifiedEmail(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	existing := f.createUser(t, "alice@example.com", true)
+
+	user, err := f.svc.resolveUser(ctx, providerName, claims("alice", "alice@example.com", true))
+	if err != nil {
+		t.Fatalf("resolveUser: %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Fatalf("got user %d, want existing user %d", user.ID, existing.ID)
+	}
+
+	identity := f.identity(t, "alice")
+	if identity == nil || identity.UserID != existing.ID || identity.Email != "alice@example.com" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+	if len(f.provisioner.provisioned) != 0 {
+		t.Errorf("provisioned %d accounts, want none", len(f.provisioner.provisioned))
+	}
+}
+
+func TestResolveUserRefusesUnverifiedAccount(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	// Someone registered the address first and never proved they own it.
+	existing := f.createUser(t, "alice@example.com", false)
+
+	_, err := f.svc.resolveUser(ctx, providerName, claims("alice", "alice@example.com", true))
+	if !errors.Is(err, domain.ErrOIDCAccountUnverified) {
+		t.Fatalf("got %v, want ErrOIDCAccountUnverified", err)
+	}
+	if f.identity(t, "alice") != nil {
+		t.Error("unverified account was linked")
+	}
+	stored, err := f.Users.GetByID(ctx, existing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.IsEmailVerified() {
+		t.Error("unverified account was marked verified")
+	}
+	if len(f.provisioner.provisioned) != 0 {
+		t.Errorf("provisioned %d accounts, want none", len(f.provisioner.provisioned))
+	}
+
+	// Once the owner verifies the address, the provider can be linked.
+	f.verify(t, existing)
+	user, err := f.svc.resolveUser(ctx, providerName, claims("alice", "alice@example.com", true))
+	if err != nil {
+		t.Fatalf("resolveUser after verifying: %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("got user %d, want existing user %d", user.ID, existing.ID)
+	}
+}
+
+func TestResolveUserProvisionsNewAccount(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	user, err := f.svc.resolveUser(ctx, providerName, claims("bob", "bob@example.com", true))
+	if err != nil {
+		t.Fatalf("resolveUser: %v", err)
+	}
+	if len(f.provisioner.provisioned) != 1 {
+		t.Fatalf("provisioned %d accounts, want 1", len(f.provisioner.provisioned))
+	}
+	if user.Email != "bob@example.com" || user.FirstName != "Alice" || user.Surname != "Example" || !user.IsEmailVerified() {
+		t.Errorf("unexpected provisioned user %+v", user)
+	}
+	if identity := f.identity(t, "bob"); identity == nil || identity.UserID != user.ID {
+		t.Errorf("unexpected identity %+v", identity)
+	}
+}
+
+func TestLoginFlow(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	existing := f.createUser(t, "alice@example.com", true)
+
+	authURL, state, err := f.svc.BeginLogin(ctx, providerName)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid authorization URL %q: %v", authURL, err)
+	}
+	query := parsed.Query()
+	if query.Get("state") != state {
+		t.Fatalf("authorization URL state %q, want %q", query.Get("state"), state)
+	}
+	if got, want := query.Get("redirect_uri"), testutil.BaseURL+"/api/auth/oidc/test/callback"; got != want {
+		t.Errorf("redirect_uri = %q, want %q", got, want)
+	}
+
+	// The user signs in at the provider, which redirects back with a code.
+	idToken := f.idp.Sign(t, f.idp.Claims("alice", query.Get("nonce")))
+	code := f.idp.Authorize(query.Get("code_challenge"), idToken)
+
+	ticket, err := f.svc.CompleteLogin(ctx, providerName, state, code)
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	user, err := f.svc.ExchangeTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("ExchangeTicket: %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("signed in as user %d, want %d", user.ID, existing.ID)
+	}
+
+	if _, err := f.svc.ExchangeTicket(ctx, ticket); !errors.Is(err, domain.ErrInvalidOIDCTicket) {
+		t.Errorf("second ticket exchange: got %v, want ErrInvalidOIDCTicket", err)
+	}
+	code = f.idp.Authorize(query.Get("code_challenge"), idToken)
+	if _, err := f.svc.CompleteLogin(ctx, providerName, state, code); !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Errorf("reused state: got %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestLoginFlowRejectsTokenForAnotherNonce(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	authURL, state, err := f.svc.BeginLogin(ctx, providerName)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A token replayed from another sign-in carries that sign-in's nonce.
+	idToken := f.idp.Sign(t, f.idp.Claims("alice", "another-nonce"))
+	code := f.idp.Authorize(parsed.Query().Get("code_challenge"), idToken)
+
+	_, err = f.svc.CompleteLogin(ctx, providerName, state, code)
+	var apiErr *domain.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 502 {
+		t.Fatalf("got %v, want a 502 provider error", err)
+	}
+	if f.identity(t, "alice") != nil {
+		t.Error("identity was linked from a rejected token")
+	}
+}
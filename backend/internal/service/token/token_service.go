@@ -0,0 +1,298 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/jwtkeys"
+	"tech-test/backend/internal/repository/interfaces"
+	tokenInterface "tech-test/backend/internal/service/interfaces/token"
+	"tech-test/backend/internal/utils"
+)
+
+// sessionTouchInterval limits how often a session's last-seen time is
+// written while its access tokens are in use.
+const sessionTouchInterval = time.Minute
+
+type service struct {
+	repo       interfaces.TokenRepository
+	userRepo   interfaces.UserRepository
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	keys       *jwtkeys.Keyring
+	logger     *zap.Logger
+}
+
+func NewService(repo interfaces.TokenRepository, userRepo interfaces.UserRepository, cfg config.JWTConfig, keys *jwtkeys.Keyring, logger *zap.Logger) tokenInterface.Service {
+	return &service{
+		repo:       repo,
+		userRepo:   userRepo,
+		keys:       keys,
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
+		logger:     logger,
+	}
+}
+
+func (s *service) Issue(ctx context.Context, user *domain.User, client domain.SessionClient) (*domain.TokenPair, error) {
+	s.logger.Debug("Issuing tokens", zap.Uint("userID", user.ID))
+
+	raw, refresh, err := s.newRefreshToken(user.ID, uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session := &domain.Session{
+		ID:         refresh.FamilyID,
+		UserID:     user.ID,
+		Device:     utils.DeviceName(client.UserAgent),
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  refresh.ExpiresAt,
+	}
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRefreshToken(ctx, refresh); err != nil {
+		return nil, err
+	}
+	return s.pair(user, session.ID, raw)
+}
+
+func (s *service) IssueImpersonation(ctx context.Context, impersonation *domain.Impersonation, admin, user *domain.User, client domain.SessionClient) (string, error) {
+	s.logger.Debug("Issuing impersonation token",
+		zap.Uint("adminID", admin.ID),
+		zap.Uint("userID", user.ID))
+
+	session := &domain.Session{
+		ID:         impersonation.ID,
+		UserID:     user.ID,
+		Device:     "Support session",
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		LastSeenAt: time.Now().UTC(),
+		ExpiresAt:  impersonation.ExpiresAt,
+	}
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return "", err
+	}
+
+	act := utils.ActorClaim{
+		UserID:           admin.ID,
+		Email:            admin.Email,
+		AllowDestructive: impersonation.AllowDestructive,
+	}
+	token, err := utils.GenerateImpersonationToken(s.keys, user.ID, user.Role, session.ID, act, time.Until(impersonation.ExpiresAt))
+	if err != nil {
+		return "", domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Error generating token",
+			err,
+		)
+	}
+	return token, nil
+}
+
+func (s *service) Refresh(ctx context.Context, refreshToken string, client domain.SessionClient) (*domain.TokenPair, error) {
+	current, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		s.logger.Warn("Refresh token reuse detected, revoking session",
+			zap.Uint("userID", current.UserID),
+			zap.Uint("tokenID", current.ID))
+		if err := s.repo.RevokeSession(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+	if current.IsExpired() {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	raw, next, err := s.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RotateRefreshToken(ctx, current, next); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			// Lost a race with another refresh of the same token.
+			if err := s.repo.RevokeSession(ctx, current.FamilyID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	// Sessions from before sessions were recorded have no row to update.
+	if err := s.repo.TouchSession(ctx, current.FamilyID, time.Now().UTC(), next.ExpiresAt, client); err != nil {
+		s.logger.Warn("Failed to update session", zap.Error(err))
+	}
+	return s.pair(user, current.FamilyID, raw)
+}
+
+func (s *service) Logout(ctx context.Context, userID uint, sessionID, jti string, expiresAt time.Time, refreshToken string) error {
+	s.logger.Debug("Logging out", zap.Uint("userID", userID))
+
+	if err := s.repo.RevokeAccessToken(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+	if sessionID != "" {
+		return s.repo.RevokeSession(ctx, sessionID)
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
+	}
+	if token.UserID != userID {
+		return nil
+	}
+	return s.repo.RevokeSession(ctx, token.FamilyID)
+}
+
+func (s *service) RevokeUser(ctx context.Context, userID uint) error {
+	s.logger.Info("Revoking all sessions", zap.Uint("userID", userID))
+
+	if err := s.repo.SetUserRevokedAt(ctx, userID, time.Now().UTC()); err != nil {
+		return err
+	}
+	return s.repo.RevokeUserSessions(ctx, userID, "")
+}
+
+func (s *service) IsRevoked(ctx context.Context, userID uint, sessionID, jti string, issuedAt time.Time) (bool, error) {
+	revokedAt, err := s.repo.GetUserRevokedAt(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	// Token timestamps have second precision, so compare at that precision.
+	if revokedAt != nil && issuedAt.Before(revokedAt.Truncate(time.Second)) {
+		return true, nil
+	}
+
+	if sessionID != "" {
+		session, err := s.repo.GetSession(ctx, sessionID)
+		if err != nil {
+			if errors.Is(err, domain.ErrSessionNotFound) {
+				return true, nil
+			}
+			return false, err
+		}
+		if session.RevokedAt != nil || session.UserID != userID {
+			return true, nil
+		}
+
+		now := time.Now().UTC()
+		if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+			if err := s.repo.TouchSession(ctx, sessionID, now, time.Time{}, domain.SessionClient{}); err != nil {
+				s.logger.Warn("Failed to update session", zap.Error(err))
+			}
+		}
+	}
+	return s.repo.IsAccessTokenRevoked(ctx, jti)
+}
+
+func (s *service) ListSessions(ctx context.Context, userID uint, currentID string) ([]domain.Session, error) {
+	sessions, err := s.repo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+func (s *service) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	session, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return domain.ErrSessionNotFound
+	}
+
+	s.logger.Info("Revoking session",
+		zap.Uint("userID", userID),
+		zap.String("sessionID", sessionID))
+	return s.repo.RevokeSession(ctx, sessionID)
+}
+
+func (s *service) RevokeOtherSessions(ctx context.Context, userID uint, keep string) error {
+	s.logger.Info("Revoking other sessions",
+		zap.Uint("userID", userID),
+		zap.String("keptSessionID", keep))
+	return s.repo.RevokeUserSessions(ctx, userID, keep)
+}
+
+func (s *service) pair(user *domain.User, sessionID, refreshToken string) (*domain.TokenPair, error) {
+	accessToken, err := utils.GenerateToken(s.keys, user.ID, user.Role, sessionID, s.accessTTL)
+	if err != nil {
+		return nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Error generating token",
+			err,
+		)
+	}
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
+}
+
+// newRefreshToken returns the raw token for the client along with the
+// record to store, which only holds its hash.
+func (s *service) newRefreshToken(userID uint, familyID string) (string, *domain.RefreshToken, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, domain.NewAPIError(
+			500,
+			domain.ErrCodeInternal,
+			"Error generating refresh token",
+			err,
+		)
+	}
+	raw := base64.RawURLEncoding.EncodeToString(b)
+
+	return raw, &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().UTC().Add(s.refreshTTL),
+	}, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,169 @@
+package uploadrequest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/repository/interfaces"
+	authzInterface "tech-test/backend/internal/service/interfaces/authz"
+	uploadRequestInterface "tech-test/backend/internal/service/interfaces/uploadrequest"
+	"tech-test/backend/internal/utils"
+)
+
+const defaultExpiry = 7 * 24 * time.Hour
+
+type service struct {
+	repo       interfaces.UploadRequestRepository
+	fileRepo   interfaces.FileRepository
+	authorizer authzInterface.Authorizer
+	logger     *zap.Logger
+	maxSize    int64
+}
+
+func NewService(
+	repo interfaces.UploadRequestRepository,
+	fileRepo interfaces.FileRepository,
+	authorizer authzInterface.Authorizer,
+	logger *zap.Logger,
+	maxSize int64,
+) uploadRequestInterface.Service {
+	return &service{
+		repo:       repo,
+		fileRepo:   fileRepo,
+		authorizer: authorizer,
+		logger:     logger,
+		maxSize:    maxSize,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uint, req domain.CreateUploadRequestRequest) (*domain.UploadRequest, error) {
+	s.logger.Debug("Creating upload request",
+		zap.Uint("userID", userID),
+		zap.String("target", req.Target))
+
+	target := strings.TrimSpace(req.Target)
+	if target == "" {
+		return nil, domain.NewInvalidInputError("target is required")
+	}
+
+	for _, t := range req.AllowedTypes {
+		if !domain.AllowedMimeTypes[t] {
+			return nil, domain.NewInvalidInputError("unsupported file type: " + t)
+		}
+	}
+
+	maxSize := req.MaxSize
+	if maxSize <= 0 || maxSize > s.maxSize {
+		maxSize = s.maxSize
+	}
+
+	expiresAt := req.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(defaultExpiry)
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, domain.NewInvalidInputError("expiresAt must be in the future")
+	}
+
+	uploadRequest := &domain.UploadRequest{
+		UserID:       userID,
+		Token:        uuid.New().String(),
+		Target:       target,
+		AllowedTypes: strings.Join(req.AllowedTypes, ","),
+		MaxSize:      maxSize,
+		ExpiresAt:    expiresAt.UTC(),
+	}
+
+	if req.Password != "" {
+		hash, err := utils.HashPassword(req.Password)
+		if err != nil {
+			s.logger.Error("Failed to hash upload request password", zap.Error(err))
+			return nil, domain.WrapError(err)
+		}
+		uploadRequest.PasswordHash = hash
+	}
+
+	if err := s.repo.Create(ctx, uploadRequest); err != nil {
+		return nil, err
+	}
+	return uploadRequest, nil
+}
+
+func (s *service) ListByUser(ctx context.Context, userID uint) ([]domain.UploadRequest, error) {
+	s.logger.Debug("Listing upload requests", zap.Uint("userID", userID))
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+func (s *service) Delete(ctx context.Context, id uint) error {
+	s.logger.Debug("Deleting upload request", zap.Uint("id", id))
+
+	req, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizer.Can(ctx, domain.ActorFromContext(ctx), domain.ActionDelete, req); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) GetOpen(ctx context.Context, token string) (*domain.UploadRequest, error) {
+	s.logger.Debug("Getting upload request by token", zap.String("token", token))
+
+	req, err := s.repo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsExpired() {
+		return nil, domain.ErrLinkExpired
+	}
+	return req, nil
+}
+
+func (s *service) Authorize(ctx context.Context, token, password string) (*domain.UploadRequest, error) {
+	req, err := s.GetOpen(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PasswordHash != "" && !utils.CheckPasswordHash(password, req.PasswordHash) {
+		return nil, domain.NewAPIError(
+			http.StatusUnauthorized,
+			domain.ErrCodeAuthentication,
+			"Invalid upload request password",
+			errors.New("password mismatch"),
+		)
+	}
+	return req, nil
+}
+
+func (s *service) Submit(ctx context.Context, token, password string, file *domain.File) error {
+	req, err := s.Authorize(ctx, token, password)
+	if err != nil {
+		return err
+	}
+
+	if err := req.Allows(file.MimeType, file.Size); err != nil {
+		return err
+	}
+
+	file.UserID = req.UserID
+	file.ExternallySubmitted = true
+	file.UploadRequestID = &req.ID
+	if file.MimeType == "application/pdf" {
+		file.PageCount = utils.PDFPageCount(file.Path)
+	}
+
+	s.logger.Info("Accepting external submission",
+		zap.Uint("uploadRequestID", req.ID),
+		zap.Uint("userID", req.UserID),
+		zap.String("name", file.Name))
+
+	return s.fileRepo.Create(ctx, file)
+}
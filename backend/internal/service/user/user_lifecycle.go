@@ -0,0 +1,274 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/utils"
+)
+
+// temporaryPasswordLength is long enough to pass any sensible minimum
+// length, and the password only has to be typed once.
+const temporaryPasswordLength = 20
+
+// Disable stops the user from logging in and ends their sessions. Tokens
+// they hold, including personal access tokens, are refused while the
+// account stays disabled.
+func (s *Service) Disable(ctx context.Context, id uint, reason string) (*domain.User, error) {
+	actor := domain.ActorFromContext(ctx)
+	if actor.UserID == id {
+		return nil, domain.NewAPIError(
+			409,
+			domain.ErrCodeConflict,
+			"You cannot disable your own account",
+			nil,
+		)
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDisabled() {
+		return user, nil
+	}
+	if user.IsAdmin() {
+		last, err := s.isLastAdmin(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return nil, domain.NewAPIError(
+				409,
+				domain.ErrCodeConflict,
+				"Cannot disable the last admin",
+				nil,
+			)
+		}
+	}
+
+	now := time.Now().UTC()
+	reason = strings.TrimSpace(reason)
+	if err := s.repo.SetDisabled(ctx, id, &now, reason); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RevokeUser(ctx, id); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User disabled", zap.Uint("id", id), zap.Uint("by", actor.UserID))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditUserDisabled,
+		ActorID:   actor.UserID,
+		SubjectID: id,
+		Details:   reason,
+	})
+	return s.repo.GetByID(ctx, id)
+}
+
+// Enable lets a disabled user log in again.
+func (s *Service) Enable(ctx context.Context, id uint) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsDisabled() {
+		return user, nil
+	}
+	if err := s.repo.SetDisabled(ctx, id, nil, ""); err != nil {
+		return nil, err
+	}
+
+	actor := domain.ActorFromContext(ctx)
+	s.logger.Info("User enabled", zap.Uint("id", id), zap.Uint("by", actor.UserID))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditUserEnabled,
+		ActorID:   actor.UserID,
+		SubjectID: id,
+	})
+	return s.repo.GetByID(ctx, id)
+}
+
+// VerifyEmail marks the user's email verified on an admin's word, for
+// deployments that cannot send verification links.
+func (s *Service) VerifyEmail(ctx context.Context, id uint) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsEmailVerified() {
+		return user, nil
+	}
+	now := time.Now().UTC()
+	if err := s.repo.SetEmailVerified(ctx, id, &now); err != nil {
+		return nil, err
+	}
+
+	actor := domain.ActorFromContext(ctx)
+	s.logger.Info("Email verified by admin", zap.Uint("id", id), zap.Uint("by", actor.UserID))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditEmailVerified,
+		ActorID:   actor.UserID,
+		SubjectID: id,
+		Details:   user.Email,
+	})
+	return s.repo.GetByID(ctx, id)
+}
+
+// RequirePasswordChange makes the user choose a new password at their next
+// login, and ends their sessions so that happens straight away.
+func (s *Service) RequirePasswordChange(ctx context.Context, id uint) error {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.SetPasswordChangeRequired(ctx, id, true); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeUser(ctx, id); err != nil {
+		return err
+	}
+
+	actor := domain.ActorFromContext(ctx)
+	s.logger.Info("Password change required", zap.Uint("id", id), zap.Uint("by", actor.UserID))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditPasswordChangeRequired,
+		ActorID:   actor.UserID,
+		SubjectID: id,
+	})
+	return nil
+}
+
+// SetTemporaryPassword replaces the user's password with one they must
+// change at their next login, generating it when password is empty. It
+// returns the password so the admin can pass it on.
+func (s *Service) SetTemporaryPassword(ctx context.Context, id uint, password string) (string, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if password == "" {
+		if password, err = generatePassword(temporaryPasswordLength); err != nil {
+			return "", domain.NewAPIError(500, domain.ErrCodeInternal, "Failed to generate password", err)
+		}
+	}
+	if err := s.policy.Check(password, user.Email); err != nil {
+		return "", err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		s.logger.Error("Failed to hash password", zap.Error(err))
+		return "", err
+	}
+	if err := s.repo.SetPassword(ctx, id, hashedPassword, true); err != nil {
+		return "", err
+	}
+	if err := s.sessions.RevokeUser(ctx, id); err != nil {
+		return "", err
+	}
+
+	actor := domain.ActorFromContext(ctx)
+	s.logger.Info("Temporary password set", zap.Uint("id", id), zap.Uint("by", actor.UserID))
+	s.audit.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AuditTemporaryPasswordSet,
+		ActorID:   actor.UserID,
+		SubjectID: id,
+	})
+	return password, nil
+}
+
+func (s *Service) AccountStatus(ctx context.Context, id uint) (*domain.AccountStatus, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AccountStatus{
+		UserID:                 user.ID,
+		Disabled:               user.IsDisabled(),
+		DisabledAt:             user.DisabledAt,
+		DisabledReason:         user.DisabledReason,
+		PasswordChangeRequired: user.PasswordChangeRequired,
+		LastLoginAt:            user.LastLoginAt,
+		LastLoginIP:            user.LastLoginIP,
+	}, nil
+}
+
+// IsDisabled reports whether requests for userID should be refused. Users
+// that no longer exist are treated as disabled.
+func (s *Service) IsDisabled(ctx context.Context, userID uint) (bool, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return user.IsDisabled(), nil
+}
+
+// IsAdmin reports whether userID is an admin's account. Users that no
+// longer exist are not.
+func (s *Service) IsAdmin(ctx context.Context, userID uint) (bool, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.IsAdmin(), nil
+}
+
+func (s *Service) RecordLogin(ctx context.Context, id uint, ip string) error {
+	return s.repo.RecordLogin(ctx, id, time.Now().UTC(), ip)
+}
+
+// generatePassword returns a random password with at least one character
+// from each class a policy can require.
+func generatePassword(length int) (string, error) {
+	classes := []string{
+		"ABCDEFGHJKLMNPQRSTUVWXYZ",
+		"abcdefghijkmnopqrstuvwxyz",
+		"23456789",
+		"!#$%&*+-=?@^_",
+	}
+	all := strings.Join(classes, "")
+
+	password := make([]byte, 0, length)
+	for i := 0; i < length; i++ {
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Move the guaranteed characters away from the front.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+	return set[n.Int64()], nil
+}
@@ -3,30 +3,57 @@ package user
 import (
 	"context"
 	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/passwordhash"
+	"tech-test/backend/internal/passwordpolicy"
 	"tech-test/backend/internal/repository/interfaces"
+	auditInterface "tech-test/backend/internal/service/interfaces/audit"
+	tokenInterface "tech-test/backend/internal/service/interfaces/token"
 	userInterface "tech-test/backend/internal/service/interfaces/user"
 	"tech-test/backend/internal/utils"
 	"go.uber.org/zap"
 	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type Service struct {
-	repo   interfaces.UserRepository
-	logger *zap.Logger
+	repo            interfaces.UserRepository
+	logger          *zap.Logger
+	bootstrapAdmins map[string]bool
+	sessions        tokenInterface.Revoker
+	policy          *passwordpolicy.Policy
+	audit           auditInterface.Service
 }
 
 var _ userInterface.UserService = (*Service)(nil)
 
-func NewService(repo interfaces.UserRepository, logger *zap.Logger) *Service {
+// NewService creates the user service. Accounts whose email appears in
+// bootstrapAdmins are given the admin role once that email is verified, so a
+// fresh deployment always has a way to reach the admin endpoints. sessions is used to end a user's
+// sessions when their password or role changes or they are deleted. Passwords
+// users choose are checked against policy. Admin changes to an account's
+// status are recorded in audit.
+func NewService(repo interfaces.UserRepository, logger *zap.Logger, bootstrapAdmins []string, sessions tokenInterface.Revoker, policy *passwordpolicy.Policy, audit auditInterface.Service) *Service {
 	if repo == nil {
 		panic("repo cannot be nil")
 	}
 	if logger == nil {
 		panic("logger cannot be nil")
 	}
+	admins := make(map[string]bool, len(bootstrapAdmins))
+	for _, email := range bootstrapAdmins {
+		admins[strings.ToLower(email)] = true
+	}
 	return &Service{
-		repo:   repo,
-		logger: logger,
+		repo:            repo,
+		logger:          logger,
+		bootstrapAdmins: admins,
+		sessions:        sessions,
+		policy:          policy,
+		audit:           audit,
 	}
 }
 
@@ -55,17 +82,278 @@ func (s *Service) Register(ctx context.Context, user *domain.User) error {
 	}
 	user.Password = hashedPassword
 
+	switch {
+	case user.Role == "":
+		user.Role = domain.RoleMember
+	case !user.Role.Valid():
+		return domain.NewInvalidInputError("role must be admin or member")
+	}
+
 	return s.repo.Create(ctx, user)
 }
 
+// UpdateUser changes a user's email, name, date of birth and password.
+// Nothing else in user is used: roles change through SetRole, and whether
+// the account is disabled or must change its password through the
+// lifecycle methods, which check and audit those changes. Empty fields keep
+// their current values, and setting a new password ends the user's existing
+// sessions. Only the user can verify an email, so changing it leaves the
+// account unverified until they confirm the new address.
 func (s *Service) UpdateUser(ctx context.Context, id uint, user *domain.User) error {
 	s.logger.Debug("Updating user", zap.Uint("id", id))
-	return s.repo.Update(ctx, id, user)
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	changes := &domain.User{
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Surname:   user.Surname,
+		DOB:       user.DOB,
+	}
+	emailChanged := changes.Email != "" && !strings.EqualFold(changes.Email, existing.Email)
+
+	passwordChanged := user.Password != ""
+	if passwordChanged {
+		email := changes.Email
+		if email == "" {
+			email = existing.Email
+		}
+		if err := s.policy.Check(user.Password, email); err != nil {
+			return err
+		}
+		hashedPassword, err := utils.HashPassword(user.Password)
+		if err != nil {
+			s.logger.Error("Failed to hash password during update", zap.Error(err))
+			return err
+		}
+		changes.Password = hashedPassword
+	}
+
+	if err := s.repo.Update(ctx, id, changes); err != nil {
+		return err
+	}
+	if emailChanged && existing.IsEmailVerified() {
+		if err := s.repo.SetEmailVerified(ctx, id, nil); err != nil {
+			return err
+		}
+	}
+	if passwordChanged {
+		return s.sessions.RevokeUser(ctx, id)
+	}
+	return nil
+}
+
+func (s *Service) CheckPassword(password, email string) error {
+	return s.policy.Check(password, email)
+}
+
+func (s *Service) SetPassword(ctx context.Context, id uint, password string) error {
+	s.logger.Debug("Setting password", zap.Uint("id", id))
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.policy.Check(password, user.Email); err != nil {
+		return err
+	}
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		s.logger.Error("Failed to hash password", zap.Error(err))
+		return err
+	}
+	if err := s.repo.SetPassword(ctx, id, hashedPassword, false); err != nil {
+		return err
+	}
+	return s.sessions.RevokeUser(ctx, id)
+}
+
+func (s *Service) UpdateProfile(ctx context.Context, id uint, req domain.UpdateProfileRequest) (*domain.User, error) {
+	s.logger.Debug("Updating profile", zap.Uint("id", id))
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *user
+	if req.FirstName != nil {
+		updated.FirstName = strings.TrimSpace(*req.FirstName)
+		if updated.FirstName == "" {
+			return nil, domain.NewInvalidInputError("firstName must not be empty")
+		}
+	}
+	if req.Surname != nil {
+		updated.Surname = strings.TrimSpace(*req.Surname)
+		if updated.Surname == "" {
+			return nil, domain.NewInvalidInputError("surname must not be empty")
+		}
+	}
+	if req.DOB != nil {
+		dob, err := time.Parse("2006-01-02", *req.DOB)
+		if err != nil {
+			return nil, domain.NewInvalidInputError("dob must be a date such as 1990-01-31")
+		}
+		updated.DOB = dob
+	}
+
+	if err := s.repo.Update(ctx, id, &updated); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *Service) ChangePassword(ctx context.Context, id uint, current, next, keepSession string) error {
+	s.logger.Debug("Changing password", zap.Uint("id", id))
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(current, user.Password) {
+		return domain.ErrIncorrectPassword
+	}
+	if current == next {
+		return domain.NewInvalidInputError("newPassword must differ from the current password")
+	}
+	if err := s.policy.Check(next, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(next)
+	if err != nil {
+		s.logger.Error("Failed to hash password", zap.Error(err))
+		return err
+	}
+	if err := s.repo.SetPassword(ctx, id, hashedPassword, false); err != nil {
+		return err
+	}
+
+	s.logger.Info("Password changed", zap.Uint("id", id))
+	// Tokens issued before sessions were recorded cannot be told apart, so
+	// without a session to keep every one of them is revoked.
+	if keepSession == "" {
+		return s.sessions.RevokeUser(ctx, id)
+	}
+	return s.sessions.RevokeOtherSessions(ctx, id, keepSession)
+}
+
+func (s *Service) SetRole(ctx context.Context, id uint, role domain.Role) (*domain.User, error) {
+	s.logger.Debug("Setting user role",
+		zap.Uint("id", id),
+		zap.String("role", string(role)))
+
+	if !role.Valid() {
+		return nil, domain.NewInvalidInputError("role must be admin or member")
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	if user.IsAdmin() {
+		last, err := s.isLastAdmin(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return nil, domain.ErrLastAdmin
+		}
+	}
+
+	updated := *user
+	updated.Role = role
+	if err := s.repo.Update(ctx, id, &updated); err != nil {
+		return nil, err
+	}
+	// Roles are carried in access tokens, so make the user sign in again.
+	if err := s.sessions.RevokeUser(ctx, id); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// BootstrapAdmins promotes existing accounts listed in the bootstrap admin
+// emails. Only verified addresses count, since anyone can register an
+// unverified one; accounts verified later are promoted by
+// PromoteBootstrapAdmin.
+func (s *Service) BootstrapAdmins(ctx context.Context) error {
+	if len(s.bootstrapAdmins) == 0 {
+		return nil
+	}
+
+	users, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range users {
+		if err := s.promoteBootstrapAdmin(ctx, &users[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PromoteBootstrapAdmin gives the admin role to the user if their email is
+// a bootstrap admin email and has been verified.
+func (s *Service) PromoteBootstrapAdmin(ctx context.Context, id uint) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.promoteBootstrapAdmin(ctx, user)
+}
+
+func (s *Service) promoteBootstrapAdmin(ctx context.Context, user *domain.User) error {
+	if user.IsAdmin() || !s.bootstrapAdmins[strings.ToLower(user.Email)] {
+		return nil
+	}
+	if !user.IsEmailVerified() {
+		s.logger.Warn("Not promoting bootstrap admin until their email is verified", zap.String("email", user.Email))
+		return nil
+	}
+
+	s.logger.Info("Promoting bootstrap admin", zap.String("email", user.Email))
+	_, err := s.SetRole(ctx, user.ID, domain.RoleAdmin)
+	return err
+}
+
+// isLastAdmin reports whether no enabled admin would be left without the
+// user id.
+func (s *Service) isLastAdmin(ctx context.Context, id uint) (bool, error) {
+	users, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return false, err
+	}
+	return domain.IsLastAdmin(users, id), nil
 }
 
 func (s *Service) DeleteUser(ctx context.Context, id uint) error {
 	s.logger.Debug("Deleting user", zap.Uint("id", id))
-	return s.repo.Delete(ctx, id)
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.IsAdmin() {
+		last, err := s.isLastAdmin(ctx, id)
+		if err != nil {
+			return err
+		}
+		if last {
+			return domain.ErrLastAdmin
+		}
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.sessions.RevokeUser(ctx, id)
 }
 
 func (s *Service) Login(ctx context.Context, email, password string) (*domain.User, error) {
@@ -73,12 +361,56 @@ func (s *Service) Login(ctx context.Context, email, password string) (*domain.Us
 	
 	user, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, err
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+		// Hash anyway so unknown emails take as long as wrong passwords.
+		utils.CheckPasswordHash(password, dummyPasswordHash())
+		return nil, domain.ErrInvalidCredentials
 	}
 
-	if !utils.CheckPasswordHash(password, user.Password) {
-		return nil, errors.New("invalid credentials")
+	match, rehash := passwordhash.Verify(password, user.Password)
+	if !match {
+		return nil, domain.ErrInvalidCredentials
+	}
+	// Only say the account is disabled to someone who knows its password.
+	if user.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+	if rehash {
+		s.rehashPassword(ctx, user, password)
 	}
 
 	return user, nil
 }
+
+// rehashPassword replaces a bcrypt or outdated argon2id hash now that the
+// password is known. The login goes ahead even if this fails; it is tried
+// again next time.
+func (s *Service) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	hashedPassword, err := passwordhash.Hash(password)
+	if err != nil {
+		s.logger.Error("Failed to rehash password", zap.Uint("id", user.ID), zap.Error(err))
+		return
+	}
+	if err := s.repo.Update(ctx, user.ID, &domain.User{Password: hashedPassword}); err != nil {
+		s.logger.Warn("Failed to store rehashed password", zap.Uint("id", user.ID), zap.Error(err))
+		return
+	}
+	user.Password = hashedPassword
+	s.logger.Info("Upgraded password hash", zap.Uint("id", user.ID))
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// dummyPasswordHash returns a hash with the same cost as real ones, for
+// comparing against when no account matches.
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = utils.HashPassword(uuid.NewString())
+	})
+	return dummyHash
+}
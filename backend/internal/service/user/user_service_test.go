@@ -0,0 +1,182 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/passwordpolicy"
+	"tech-test/backend/internal/repository/sqlite"
+	"tech-test/backend/internal/service/audit"
+	auditInterface "tech-test/backend/internal/service/interfaces/audit"
+	"tech-test/backend/internal/testutil"
+)
+
+// revoker records whose sessions were ended.
+type revoker struct {
+	revoked []uint
+}
+
+func (r *revoker) RevokeUser(ctx context.Context, userID uint) error {
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+func (r *revoker) RevokeOtherSessions(ctx context.Context, userID uint, keep string) error {
+	return nil
+}
+
+type fixture struct {
+	*testutil.Env
+	svc      *Service
+	sessions *revoker
+	audit    auditInterface.Service
+	admin    *domain.User
+	user     *domain.User
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	logger := zap.NewNop()
+	policy, err := passwordpolicy.New(config.PasswordPolicyConfig{MinLength: 8, MaxLength: 128, RequireDigit: true})
+	if err != nil {
+		t.Fatalf("failed to build password policy: %v", err)
+	}
+
+	env := testutil.NewEnv(t)
+	verifiedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	f := &fixture{
+		Env:      env,
+		sessions: &revoker{},
+		audit:    audit.NewService(sqlite.NewAuditRepository(env.DB), logger),
+		admin:    env.CreateUser(t, &domain.User{Email: "admin@example.com", FirstName: "Ada", Surname: "Admin", Role: domain.RoleAdmin}),
+		user:     env.CreateUser(t, &domain.User{Email: "alice@example.com", FirstName: "Alice", Surname: "Example", EmailVerifiedAt: &verifiedAt}),
+	}
+	f.svc = NewService(f.Users, logger, nil, f.sessions, policy, f.audit)
+	return f
+}
+
+func (f *fixture) asAdmin() context.Context {
+	return domain.ContextWithActor(context.Background(), domain.Actor{UserID: f.admin.ID, Admin: true})
+}
+
+func (f *fixture) reload(t *testing.T, id uint) *domain.User {
+	t.Helper()
+
+	stored, err := f.Users.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to reload user: %v", err)
+	}
+	return stored
+}
+
+func TestUpdateUserChangedEmailMustBeVerifiedAgain(t *testing.T) {
+	f := newFixture(t)
+
+	err := f.svc.UpdateUser(f.asAdmin(), f.user.ID, &domain.User{Email: "alice@new.example.com", FirstName: "Alice"})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	stored := f.reload(t, f.user.ID)
+	if stored.Email != "alice@new.example.com" {
+		t.Errorf("email = %q", stored.Email)
+	}
+	if stored.IsEmailVerified() {
+		t.Error("changed email is still verified")
+	}
+}
+
+func TestUpdateUserKeepsVerificationOfUnchangedEmail(t *testing.T) {
+	f := newFixture(t)
+
+	for _, email := range []string{"", "alice@example.com", "Alice@Example.com"} {
+		err := f.svc.UpdateUser(f.asAdmin(), f.user.ID, &domain.User{Email: email, FirstName: "Alicia"})
+		if err != nil {
+			t.Fatalf("UpdateUser(%q): %v", email, err)
+		}
+		if stored := f.reload(t, f.user.ID); !stored.IsEmailVerified() || !stored.EmailVerifiedAt.Equal(*f.user.EmailVerifiedAt) {
+			t.Errorf("email %q: verification changed to %v", email, stored.EmailVerifiedAt)
+		}
+	}
+}
+
+func TestUpdateUserIgnoresEmailVerifiedAt(t *testing.T) {
+	f := newFixture(t)
+	unverified := f.CreateUser(t, &domain.User{Email: "bob@example.com", FirstName: "Bob"})
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name  string
+		email string
+	}{
+		{"same email", "bob@example.com"},
+		{"new email", "bob@new.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.svc.UpdateUser(f.asAdmin(), unverified.ID, &domain.User{Email: tt.email, FirstName: "Bob", EmailVerifiedAt: &now})
+			if err != nil {
+				t.Fatalf("UpdateUser: %v", err)
+			}
+			if f.reload(t, unverified.ID).IsEmailVerified() {
+				t.Error("emailVerifiedAt was taken from the request")
+			}
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	f := newFixture(t)
+	unverified := f.CreateUser(t, &domain.User{Email: "bob@example.com", FirstName: "Bob"})
+
+	user, err := f.svc.VerifyEmail(f.asAdmin(), unverified.ID)
+	if err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if !user.IsEmailVerified() || !f.reload(t, unverified.ID).IsEmailVerified() {
+		t.Fatal("email was not verified")
+	}
+
+	// Verifying again changes nothing and records nothing.
+	if _, err := f.svc.VerifyEmail(f.asAdmin(), unverified.ID); err != nil {
+		t.Fatalf("second VerifyEmail: %v", err)
+	}
+	events, err := f.audit.List(context.Background(), domain.AuditFilter{Action: domain.AuditEmailVerified})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(events) != 1 || events[0].ActorID != f.admin.ID || events[0].SubjectID != unverified.ID {
+		t.Errorf("unexpected audit events %+v", events)
+	}
+}
+
+func TestUpdateUserOnlyChangesProfile(t *testing.T) {
+	f := newFixture(t)
+	now := time.Now().UTC()
+
+	err := f.svc.UpdateUser(f.asAdmin(), f.user.ID, &domain.User{
+		FirstName:              "Alicia",
+		Role:                   domain.RoleAdmin,
+		DisabledAt:             &now,
+		DisabledReason:         "through the back door",
+		PasswordChangeRequired: true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+
+	stored := f.reload(t, f.user.ID)
+	if stored.FirstName != "Alicia" || stored.Surname != "Example" || stored.Email != "alice@example.com" {
+		t.Errorf("profile not updated as asked: %+v", stored)
+	}
+	if stored.Role != domain.RoleMember || stored.IsDisabled() || stored.DisabledReason != "" || stored.PasswordChangeRequired {
+		t.Errorf("update changed more than the profile: %+v", stored)
+	}
+	if len(f.sessions.revoked) != 0 {
+		t.Errorf("sessions revoked without a password change: %v", f.sessions.revoked)
+	}
+}
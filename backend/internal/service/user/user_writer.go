@@ -3,8 +3,8 @@ package user
 import (
 	"context"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/passwordhash"
 	"tech-test/backend/internal/repository/interfaces"
 	userInterface "tech-test/backend/internal/service/interfaces/user"
 )
@@ -24,13 +24,13 @@ func NewWriter(repo interfaces.UserRepository, logger *zap.Logger) userInterface
 func (w *writer) Register(ctx context.Context, user *domain.User) error {
 	w.logger.Debug("Registering new user", zap.String("email", user.Email))
 	
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := passwordhash.Hash(user.Password)
 	if err != nil {
 		w.logger.Error("Failed to hash password", zap.Error(err))
 		return err
 	}
 	
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	return w.repo.Create(ctx, user)
 }
 
@@ -41,12 +41,12 @@ func (w *writer) UpdateUser(ctx context.Context, id uint, user *domain.User) err
 	)
 	
 	if user.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		hashedPassword, err := passwordhash.Hash(user.Password)
 		if err != nil {
 			w.logger.Error("Failed to hash password during update", zap.Error(err))
 			return err
 		}
-		user.Password = string(hashedPassword)
+		user.Password = hashedPassword
 	}
 	
 	return w.repo.Update(ctx, id, user)
@@ -0,0 +1,66 @@
+// Package testutil sets up what service and handler tests share: a
+// migrated SQLite database in a temporary directory, a user repository to
+// seed accounts with, and an SMTP sink to deliver mail to. Package
+// fixtures embed an Env and add the repositories and services they test.
+package testutil
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+	"tech-test/backend/internal/config"
+	"tech-test/backend/internal/database"
+	"tech-test/backend/internal/domain"
+	"tech-test/backend/internal/mailer"
+	"tech-test/backend/internal/mailer/mailertest"
+	"tech-test/backend/internal/repository/interfaces"
+	"tech-test/backend/internal/repository/sqlite"
+)
+
+// BaseURL is the public URL tests build services with.
+const BaseURL = "https://files.example.com"
+
+type Env struct {
+	DB    *gorm.DB
+	Users interfaces.UserRepository
+	Sink  *mailertest.SMTPSink
+}
+
+// NewEnv creates an empty database, closed when the test ends, and a sink
+// with no messages.
+func NewEnv(t *testing.T) *Env {
+	t.Helper()
+
+	db, err := database.SetupDB(config.DatabaseConfig{DBPath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("failed to set up database: %v", err)
+	}
+	t.Cleanup(func() { database.CloseDB(db) })
+
+	return &Env{
+		DB:    db,
+		Users: sqlite.NewUserRepository(db),
+		Sink:  mailertest.NewSMTPSink(t),
+	}
+}
+
+// Mailer returns a mailer that delivers to the sink.
+func (e *Env) Mailer() mailer.Mailer {
+	return mailer.NewSMTPMailer(e.Sink.Config())
+}
+
+// CreateUser stores user, giving it a placeholder password hash unless it
+// has one, and returns it with its ID set.
+func (e *Env) CreateUser(t *testing.T, user *domain.User) *domain.User {
+	t.Helper()
+
+	if user.Password == "" {
+		user.Password = "x"
+	}
+	if err := e.Users.Create(context.Background(), user); err != nil {
+		t.Fatalf("failed to create user %s: %v", user.Email, err)
+	}
+	return user
+}
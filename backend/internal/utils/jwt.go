@@ -3,61 +3,91 @@
 
    import (
        "fmt"
-       "os"
+       "strconv"
        "time"
        "github.com/golang-jwt/jwt/v4"
+       "github.com/google/uuid"
+       "tech-test/backend/internal/domain"
+       "tech-test/backend/internal/jwtkeys"
    )
 
    
 
-   var JWTSecret = []byte(getJWTSecret())
-
    type Claims struct {
-       UserID uint `json:"user_id"` 
+       UserID uint        `json:"user_id"` 
+       Role   domain.Role `json:"role"`
+       // SessionID ties the token to the login it was issued for.
+       SessionID string `json:"sid,omitempty"`
+       // Act is set on impersonation tokens and names the administrator
+       // acting as UserID (RFC 8693).
+       Act *ActorClaim `json:"act,omitempty"`
        jwt.RegisteredClaims
    }
 
+   // ActorClaim identifies an impersonating administrator.
+   type ActorClaim struct {
+       Subject string `json:"sub"`
+       UserID  uint   `json:"user_id"`
+       Email   string `json:"email,omitempty"`
+       // AllowDestructive permits deletes during the impersonation.
+       AllowDestructive bool `json:"allow_destructive,omitempty"`
+   }
+
    func (c *Claims) Valid() error {
        return c.RegisteredClaims.Valid()
    }
 
-   func getJWTSecret() string {
-       secret := os.Getenv("JWT_SECRET")
-       if secret == "" {
-           return "your-secret-key"
-       }
-       return secret
+   // GenerateToken issues an access token valid for ttl. Each token gets a
+   // unique ID so it can be revoked on its own.
+   func GenerateToken(keys *jwtkeys.Keyring, userID uint, role domain.Role, sessionID string, ttl time.Duration) (string, error) {
+       return keys.Sign(newClaims(keys, userID, role, sessionID, ttl))
+   }
+
+   // GenerateImpersonationToken issues an access token for userID that
+   // records act as the administrator really making the requests.
+   func GenerateImpersonationToken(keys *jwtkeys.Keyring, userID uint, role domain.Role, sessionID string, act ActorClaim, ttl time.Duration) (string, error) {
+       claims := newClaims(keys, userID, role, sessionID, ttl)
+       act.Subject = strconv.FormatUint(uint64(act.UserID), 10)
+       claims.Act = &act
+       return keys.Sign(claims)
    }
 
-   func GenerateToken(userID uint) (string, error) {
-       claims := &Claims{
-           UserID: userID,
+   func newClaims(keys *jwtkeys.Keyring, userID uint, role domain.Role, sessionID string, ttl time.Duration) *Claims {
+       now := time.Now()
+       return &Claims{
+           UserID:    userID,
+           Role:      role,
+           SessionID: sessionID,
            RegisteredClaims: jwt.RegisteredClaims{
-               ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-               IssuedAt:  jwt.NewNumericDate(time.Now()),
-               NotBefore: jwt.NewNumericDate(time.Now()),
+               ID:        uuid.New().String(),
+               Issuer:    keys.Issuer(),
+               Subject:   strconv.FormatUint(uint64(userID), 10),
+               Audience:  jwt.ClaimStrings{keys.Audience()},
+               ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+               IssuedAt:  jwt.NewNumericDate(now),
+               NotBefore: jwt.NewNumericDate(now),
            },
        }
-
-       token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-       return token.SignedString(JWTSecret)
    }
 
-   func ValidateToken(tokenString string, secret []byte, method jwt.SigningMethod) (*Claims, error) {
-       token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-           if token.Method != method {
-               return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-           }
-           return secret, nil
-       })
-
+   // ValidateToken checks an access token's signature against keys, and its
+   // lifetime, issuer and audience.
+   func ValidateToken(tokenString string, keys *jwtkeys.Keyring) (*Claims, error) {
+       parser := jwt.NewParser(jwt.WithValidMethods(keys.Methods()))
+       token, err := parser.ParseWithClaims(tokenString, &Claims{}, keys.Keyfunc)
        if err != nil {
            return nil, err
        }
 
-       if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-           return claims, nil
+       claims, ok := token.Claims.(*Claims)
+       if !ok || !token.Valid {
+           return nil, fmt.Errorf("invalid token")
        }
-
-       return nil, fmt.Errorf("invalid token")
+       if !claims.VerifyIssuer(keys.Issuer(), true) {
+           return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+       }
+       if !claims.VerifyAudience(keys.Audience(), true) {
+           return nil, fmt.Errorf("token not issued for this audience")
+       }
+       return claims, nil
    }
@@ -1,15 +1,14 @@
 package utils
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"tech-test/backend/internal/passwordhash"
 )
 
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	return string(bytes), err
+	return passwordhash.Hash(password)
 }
 
 func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	match, _ := passwordhash.Verify(password, hash)
+	return match
 }
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"regexp"
+)
+
+// pdfPagePattern matches page objects but not the /Pages tree nodes.
+var pdfPagePattern = regexp.MustCompile(`/Type\s*/Page[^s]`)
+
+const (
+	pdfChunkSize = 64 * 1024
+	// pdfOverlap is kept from the end of each chunk so page objects split
+	// across two chunks are still found.
+	pdfOverlap = 64
+)
+
+// CountPDFPages gives a best-effort page count by counting page objects in
+// the raw file, reading it in chunks. PDFs whose page objects live in
+// compressed object streams report zero, which callers should treat as
+// unknown.
+func CountPDFPages(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 0, pdfOverlap+pdfChunkSize)
+	pages := 0
+	for {
+		carried := len(buf)
+		n, err := io.ReadFull(f, buf[carried:carried+pdfChunkSize])
+		buf = buf[:carried+n]
+
+		for _, loc := range pdfPagePattern.FindAllIndex(buf, -1) {
+			// Matches ending inside the carried bytes were counted with
+			// the previous chunk.
+			if loc[1] > carried {
+				pages++
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return pages, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		keep := pdfOverlap
+		if keep > len(buf) {
+			keep = len(buf)
+		}
+		buf = buf[:copy(buf, buf[len(buf)-keep:])]
+	}
+}
+
+// PDFPageCount counts the pages of the PDF at path, returning nil when the
+// count is unknown.
+func PDFPageCount(path string) *int {
+	pages, err := CountPDFPages(path)
+	if err != nil || pages == 0 {
+		return nil
+	}
+	return &pages
+}
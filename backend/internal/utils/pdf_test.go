@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCountPDFPages(t *testing.T) {
+	page := "<< /Type /Page /Parent 2 0 R >>\n"
+	tree := "<< /Type /Pages /Count 3 >>\n"
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "empty", content: "", want: 0},
+		{name: "pages tree only", content: tree, want: 0},
+		{name: "small", content: tree + strings.Repeat(page, 3), want: 3},
+		// Pad so page objects straddle the chunk boundaries.
+		{name: "across chunks", content: strings.Repeat(strings.Repeat("x", pdfChunkSize-7)+page, 4), want: 4},
+		{name: "large", content: strings.Repeat(page+strings.Repeat(" ", 1000), 500), want: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "doc.pdf")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := CountPDFPages(path)
+			if err != nil {
+				t.Fatalf("CountPDFPages: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d pages, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFPageCountUnknown(t *testing.T) {
+	if got := PDFPageCount(filepath.Join(t.TempDir(), "missing.pdf")); got != nil {
+		t.Errorf("missing file: got %d, want nil", *got)
+	}
+}
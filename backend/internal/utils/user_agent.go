@@ -0,0 +1,55 @@
+package utils
+
+import "strings"
+
+// Checked in order, since e.g. Edge and Chrome both send "Chrome/" and
+// Chrome also sends "Safari/".
+var (
+	browsers = []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"CriOS/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	}
+	platforms = []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// DeviceName gives a short label such as "Firefox on Windows" for a
+// User-Agent header, so people can recognise their sessions.
+func DeviceName(userAgent string) string {
+	browser := match(userAgent, browsers)
+	platform := match(userAgent, platforms)
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	case userAgent == "":
+		return "Unknown device"
+	}
+	// Fall back to the product token, e.g. "python-requests".
+	product, _, _ := strings.Cut(userAgent, "/")
+	return product
+}
+
+func match(userAgent string, candidates []struct{ token, name string }) string {
+	for _, c := range candidates {
+		if strings.Contains(userAgent, c.token) {
+			return c.name
+		}
+	}
+	return ""
+}
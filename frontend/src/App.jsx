@@ -1,6 +1,7 @@
 import { BrowserRouter as Router, Routes, Route, Navigate } from 'react-router-dom';
 import { Header } from './layout/Header.jsx';
 import { Footer } from './layout/Footer.jsx';
+import { ImpersonationBanner } from './components/ImpersonationBanner.jsx';
 import { AuthProvider } from './context/AuthContext.jsx';
 import { routes } from './Routes.jsx';
 import { SharedFile } from './components/SharedFile.jsx';
@@ -15,6 +16,7 @@ function App() {
       <AuthProvider>
         <div className="d-flex flex-column min-vh-100">
           <Header />
+          <ImpersonationBanner />
           <main className="flex-grow-1">
             <Routes>
               <Route path="/" element={<Navigate to="/login" replace />} />
@@ -1,6 +1,9 @@
 import { Navigate } from 'react-router-dom';
 import { LoginPage } from './pages/login/Login.page.jsx';
 import { RegisterPage } from './pages/register/Register.page.jsx';
+import { ForgotPasswordPage, ResetPasswordPage } from './pages/password/PasswordReset.page.jsx';
+import { VerifyEmailPage } from './pages/verify-email/VerifyEmail.page.jsx';
+import { OIDCCallbackPage } from './pages/oidc/OIDCCallback.page.jsx';
 import { DashboardPage } from './pages/dashboard/Dashboard.page.jsx';
 import { SharedFilePage } from './pages/shared/[shareId]/SharedFile.page.jsx';
 import { PrivateRoute } from './components/PrivateRoute.jsx'; 
@@ -14,6 +17,22 @@ export const routes = [
     path: '/register',
     element: <RegisterPage />
   },
+  {
+    path: '/forgot-password',
+    element: <ForgotPasswordPage />
+  },
+  {
+    path: '/reset-password',
+    element: <ResetPasswordPage />
+  },
+  {
+    path: '/verify-email',
+    element: <VerifyEmailPage />
+  },
+  {
+    path: '/oidc/callback',
+    element: <OIDCCallbackPage />
+  },
   {
     path: '/dashboard',
     element: (
@@ -11,6 +11,7 @@ import SearchBar from './SearchBar';
 import DownloadButton from './DownloadButton';
 import ShareButton from './ShareButton';
 import DeleteButton from './DeleteButton';
+import { VerifyEmailBanner } from './VerifyEmailBanner';
 
 export const Dashboard = () => {
   const { user } = useAuth();
@@ -149,6 +150,7 @@ export const Dashboard = () => {
           <Col>
             <h1 className="fw-bold">Dashboard</h1>
             <p className="text-muted">Welcome back{user?.email ? `, ${user.email}` : ''}</p>
+            <VerifyEmailBanner />
           </Col>
         </Row>
 
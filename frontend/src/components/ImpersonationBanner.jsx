@@ -0,0 +1,35 @@
+import { useState } from 'react';
+import { Alert, Button, Container } from 'react-bootstrap';
+import { useAuth } from '../hooks/useAuth';
+import { authService } from '../services/auth';
+
+// Shown on every page while an admin is acting as another user, so it is
+// never mistaken for their own account.
+export const ImpersonationBanner = () => {
+  const { user } = useAuth();
+  const [stopping, setStopping] = useState(false);
+
+  const impersonator = user?.impersonatedBy;
+  if (!impersonator) return null;
+
+  const handleStop = async () => {
+    setStopping(true);
+    await authService.stopImpersonating();
+    window.location.href = '/user-management';
+  };
+
+  return (
+    <Alert variant="danger" className="rounded-0 mb-0 py-2">
+      <Container className="d-flex justify-content-between align-items-center">
+        <span>
+          You ({impersonator.adminEmail}) are signed in as <strong>{user.email}</strong> until{' '}
+          {new Date(impersonator.expiresAt).toLocaleTimeString()}. Your actions are recorded
+          {impersonator.allowDestructive ? '.' : '. This session is read-only.'}
+        </span>
+        <Button variant="light" size="sm" onClick={handleStop} disabled={stopping}>
+          {stopping ? 'Stopping...' : 'Stop impersonating'}
+        </Button>
+      </Container>
+    </Alert>
+  );
+};
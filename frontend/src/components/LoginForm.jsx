@@ -1,11 +1,39 @@
+import { useEffect, useState } from 'react';
 import { Form, Button, Container, Row, Col, Card } from 'react-bootstrap';
 import { Link } from 'react-router-dom';
 import { useLoginForm } from '../hooks/useLoginForm';
 import { ErrorAlert } from './common/ErrorAlert';
+import { authService } from '../services/auth';
 
 export const LoginForm = () => {
-  const { state, dispatch, handleSubmit } = useLoginForm();
-  const { email, password, error, loading } = state;
+  const { state, dispatch, handleSubmit, finish, restart } = useLoginForm();
+  const { email, password, code, challenge, enrollment, recoveryCodes, error, loading } = state;
+  const [providers, setProviders] = useState([]);
+
+  useEffect(() => {
+    authService.getOIDCProviders()
+      .then(setProviders)
+      .catch(() => setProviders([]));
+  }, []);
+
+  if (recoveryCodes) {
+    return (
+      <div className="min-vh-100 d-flex align-items-center justify-content-center" style={{ background: '#f5f5f5', padding: '20px' }}>
+        <Card className="shadow-lg" style={{ maxWidth: '400px', margin: '0 auto' }}>
+          <Card.Body className="p-4">
+            <h2 className="text-center mb-3">Save your recovery codes</h2>
+            <p className="text-muted">
+              Each code can be used once if you lose your authenticator. They will not be shown again.
+            </p>
+            <pre className="bg-light p-3 text-center">{recoveryCodes.join('\n')}</pre>
+            <Button className="w-100" size="lg" onClick={finish}>
+              I have saved them
+            </Button>
+          </Card.Body>
+        </Card>
+      </div>
+    );
+  }
 
   return (
     <div className="min-vh-100 d-flex align-items-center justify-content-center" style={{ background: '#f5f5f5', padding: '20px' }}>
@@ -17,6 +45,38 @@ export const LoginForm = () => {
                 <h2 className="text-center mb-4">Login</h2>
                 <ErrorAlert error={error} />
                 <Form onSubmit={handleSubmit}>
+                  {challenge ? (
+                  <>
+                  {enrollment && (
+                    <div className="mb-3">
+                      <p className="text-muted">
+                        Two-factor authentication is required. Add this key to your authenticator app, or open the link on your phone:
+                      </p>
+                      <code className="d-block mb-2 text-break">{enrollment.secret}</code>
+                      <a href={enrollment.otpauthUri} className="small">Open in authenticator app</a>
+                    </div>
+                  )}
+                  <Form.Group className="mb-4">
+                    <Form.Label>Verification code</Form.Label>
+                    <Form.Control
+                      type="text"
+                      inputMode="text"
+                      autoComplete="one-time-code"
+                      autoFocus
+                      value={code}
+                      onChange={(e) => dispatch({ 
+                        type: 'SET_FIELD', 
+                        field: 'code', 
+                        value: e.target.value 
+                      })}
+                      required
+                      placeholder={enrollment ? 'Code from your app' : 'Authenticator or recovery code'}
+                      size="lg"
+                    />
+                  </Form.Group>
+                  </>
+                  ) : (
+                  <>
                   <Form.Group className="mb-3">
                     <Form.Label>Email</Form.Label>
                     <Form.Control
@@ -52,7 +112,12 @@ export const LoginForm = () => {
                       size="lg"
                       isInvalid={error && error.includes('password')}
                     />
+                    <div className="text-end mt-1">
+                      <small><Link to="/forgot-password">Forgot your password?</Link></small>
+                    </div>
                   </Form.Group>
+                  </>
+                  )}
 
                   <Button
                     className="w-100 mb-3"
@@ -66,13 +131,34 @@ export const LoginForm = () => {
                         Logging in...
                       </>
                     ) : (
-                      'Login'
+                      challenge ? 'Verify' : 'Login'
                     )}
                   </Button>
 
+                  {!challenge && providers.length > 0 && (
+                    <div className="mb-3">
+                      <div className="text-center text-muted small mb-2">or</div>
+                      {providers.map((provider) => (
+                        <Button
+                          key={provider.name}
+                          as="a"
+                          href={provider.loginUrl}
+                          variant="outline-secondary"
+                          className="w-100 mb-2"
+                        >
+                          Sign in with {provider.displayName}
+                        </Button>
+                      ))}
+                    </div>
+                  )}
+
                   <div className="text-center">
                     <small className="text-muted">
-                      Don&apos;t have an account? <Link to="/register">Register</Link>
+                      {challenge ? (
+                        <Button variant="link" size="sm" className="p-0" onClick={restart}>Start over</Button>
+                      ) : (
+                        <>Don&apos;t have an account? <Link to="/register">Register</Link></>
+                      )}
                     </small>
                   </div>
                 </Form>
@@ -0,0 +1,63 @@
+import { useEffect, useRef, useState } from 'react';
+import { Container, Row, Col, Card, Alert, Spinner } from 'react-bootstrap';
+import { Link, useNavigate } from 'react-router-dom';
+import { afterLoginPath, useAuth } from '../hooks/useAuth';
+
+// Receives the redirect from /api/auth/oidc/{provider}/callback, which puts
+// a one-time ticket or an error in the URL fragment.
+export const OIDCCallback = () => {
+  const { loginWithTicket } = useAuth();
+  const navigate = useNavigate();
+  const [error, setError] = useState('');
+  // Tickets are single-use, so guard against the effect running twice.
+  const exchanged = useRef(false);
+
+  useEffect(() => {
+    if (exchanged.current) return;
+    exchanged.current = true;
+
+    const params = new URLSearchParams(window.location.hash.slice(1));
+    window.history.replaceState(null, '', window.location.pathname);
+
+    if (params.get('error')) {
+      setError(params.get('error'));
+      return;
+    }
+
+    loginWithTicket(params.get('ticket') || '')
+      .then((result) => {
+        if (result.mfaRequired) {
+          navigate('/login', { replace: true, state: { mfa: result } });
+          return;
+        }
+        navigate(afterLoginPath(result), { replace: true });
+      })
+      .catch((err) => {
+        setError(err.response?.data?.message || 'Sign-in failed. Please try again.');
+      });
+  }, [loginWithTicket, navigate]);
+
+  return (
+    <div className="min-vh-100 d-flex align-items-center justify-content-center" style={{ background: '#f5f5f5', padding: '20px' }}>
+      <Container>
+        <Row className="justify-content-center">
+          <Col xs={12} md={8} lg={5}>
+            <Card className="shadow-lg" style={{ maxWidth: '400px', margin: '0 auto' }}>
+              <Card.Body className="p-4 text-center">
+                <h2 className="mb-4">Signing in</h2>
+                {error ? (
+                  <>
+                    <Alert variant="danger">{error}</Alert>
+                    <Link to="/login">Back to login</Link>
+                  </>
+                ) : (
+                  <Spinner animation="border" />
+                )}
+              </Card.Body>
+            </Card>
+          </Col>
+        </Row>
+      </Container>
+    </div>
+  );
+};
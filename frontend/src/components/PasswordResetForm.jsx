@@ -0,0 +1,156 @@
+import { useState } from 'react';
+import { Form, Button, Container, Row, Col, Card, Alert } from 'react-bootstrap';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
+import { authService } from '../services/auth';
+
+const errorMessage = (err) => {
+  const data = err.response?.data;
+  if (data?.errors?.password) return data.errors.password;
+  if (data?.message) return data.message;
+  if (typeof data === 'string' && data) return data;
+  return 'Something went wrong. Please try again.';
+};
+
+const ResetCard = ({ title, children }) => (
+  <div className="min-vh-100 d-flex align-items-center justify-content-center" style={{ background: '#f5f5f5', padding: '20px' }}>
+    <Container>
+      <Row className="justify-content-center">
+        <Col xs={12} md={8} lg={5}>
+          <Card className="shadow-lg" style={{ maxWidth: '400px', margin: '0 auto' }}>
+            <Card.Body className="p-4">
+              <h2 className="text-center mb-4">{title}</h2>
+              {children}
+            </Card.Body>
+          </Card>
+        </Col>
+      </Row>
+    </Container>
+  </div>
+);
+
+export const ForgotPasswordForm = () => {
+  const [email, setEmail] = useState('');
+  const [sent, setSent] = useState(false);
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError('');
+    setLoading(true);
+    try {
+      await authService.forgotPassword(email);
+      setSent(true);
+    } catch (err) {
+      setError(errorMessage(err));
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <ResetCard title="Forgot password">
+      {sent ? (
+        <Alert variant="success">
+          If an account exists for {email}, we have sent a link to reset its password.
+        </Alert>
+      ) : (
+        <Form onSubmit={handleSubmit}>
+          {error && <Alert variant="danger">{error}</Alert>}
+          <Form.Group className="mb-4">
+            <Form.Label>Email</Form.Label>
+            <Form.Control
+              type="email"
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              required
+              placeholder="Enter your email"
+              size="lg"
+            />
+          </Form.Group>
+          <Button className="w-100 mb-3" variant="primary" type="submit" size="lg" disabled={loading}>
+            {loading ? 'Sending...' : 'Send reset link'}
+          </Button>
+        </Form>
+      )}
+      <div className="text-center">
+        <small className="text-muted">
+          <Link to="/login">Back to login</Link>
+        </small>
+      </div>
+    </ResetCard>
+  );
+};
+
+export const ResetPasswordForm = () => {
+  const [searchParams] = useSearchParams();
+  const navigate = useNavigate();
+  const token = searchParams.get('token') || '';
+  const [password, setPassword] = useState('');
+  const [confirmPassword, setConfirmPassword] = useState('');
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError('');
+    if (password !== confirmPassword) {
+      setError('Passwords do not match');
+      return;
+    }
+
+    setLoading(true);
+    try {
+      await authService.resetPassword(token, password);
+      navigate('/login', { replace: true });
+    } catch (err) {
+      setError(errorMessage(err));
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (!token) {
+    return (
+      <ResetCard title="Reset password">
+        <Alert variant="danger">This reset link is incomplete. Please request a new one.</Alert>
+        <div className="text-center">
+          <Link to="/forgot-password">Request a new link</Link>
+        </div>
+      </ResetCard>
+    );
+  }
+
+  return (
+    <ResetCard title="Reset password">
+      <Form onSubmit={handleSubmit}>
+        {error && <Alert variant="danger">{error}</Alert>}
+        <Form.Group className="mb-3">
+          <Form.Label>New password</Form.Label>
+          <Form.Control
+            type="password"
+            autoComplete="new-password"
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            required
+            size="lg"
+          />
+        </Form.Group>
+        <Form.Group className="mb-4">
+          <Form.Label>Confirm new password</Form.Label>
+          <Form.Control
+            type="password"
+            autoComplete="new-password"
+            value={confirmPassword}
+            onChange={(e) => setConfirmPassword(e.target.value)}
+            required
+            size="lg"
+          />
+        </Form.Group>
+        <Button className="w-100 mb-3" variant="primary" type="submit" size="lg" disabled={loading}>
+          {loading ? 'Saving...' : 'Set new password'}
+        </Button>
+      </Form>
+    </ResetCard>
+  );
+};
@@ -1,7 +1,7 @@
 import { useReducer, useState } from 'react';
 import { Form, Button, Alert, Container, Row, Col, Card } from 'react-bootstrap';
 import { useAuth } from '../hooks/useAuth';
-import { useNavigate, Link } from 'react-router-dom';
+import { useNavigate, useSearchParams, Link } from 'react-router-dom';
 import { z } from 'zod';
 
 const initialFormState = {
@@ -104,6 +104,9 @@ export const RegisterForm = () => {
   const [validationErrors, setValidationErrors] = useState({});
   const { register } = useAuth();
   const navigate = useNavigate();
+  // Invitation links point here with ?invite=<code>.
+  const [searchParams] = useSearchParams();
+  const inviteCode = searchParams.get('invite') || '';
 
   const validateForm = () => {
     try {
@@ -134,7 +137,7 @@ export const RegisterForm = () => {
     try {
       setError('');
       setLoading(true);
-      await register(userData);
+      await register(inviteCode ? { ...userData, inviteCode } : userData);
       navigate('/login');
     } catch (err) {
       console.error('Registration error:', err);
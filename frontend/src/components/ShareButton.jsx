@@ -29,12 +29,12 @@ const ShareButton = ({ fileId }) => {
         }
       );
 
-      const { shareableLink } = response.data;
+      const { shareableLink, previewLink } = response.data;
       if (!shareableLink) {
         throw new Error('Invalid share response from server');
       }
 
-      setShareableLink(shareableLink);
+      setShareableLink(previewLink || shareableLink);
       setShowShareModal(true);
       toast.success('Share link generated successfully');
     } catch (err) {
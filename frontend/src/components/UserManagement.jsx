@@ -3,6 +3,7 @@ import { Card, Button, Form, ListGroup, Pagination } from 'react-bootstrap';
 import { toast } from 'react-toastify';
 import useUsers from '../hooks/useUsers';
 import UserFormModal from './UserFormModal.jsx';
+import { authService } from '../services/auth';
 
 export const UserManagement = () => {
   const {
@@ -70,6 +71,17 @@ export const UserManagement = () => {
     setShowUserModal(true);
   }, []);
 
+  const handleImpersonate = useCallback(async (user) => {
+    const reason = window.prompt(`Why do you need to sign in as ${user.email}? This is recorded in the audit log.`);
+    if (!reason?.trim()) return;
+    try {
+      await authService.impersonate(user.id, reason.trim());
+      window.location.href = '/dashboard';
+    } catch (error) {
+      toast.error(error.response?.data?.message || 'Could not impersonate user');
+    }
+  }, []);
+
   if (loading) {
     return <div>Loading users...</div>;
   }
@@ -102,6 +114,9 @@ export const UserManagement = () => {
                   </div>
                   <div>
                     <Button variant="outline-primary" size="sm" onClick={() => handleOpenEditUserModal(user)}>Edit</Button>
+                    {user.role !== 'admin' && (
+                      <Button variant="outline-secondary" size="sm" onClick={() => handleImpersonate(user)}>Impersonate</Button>
+                    )}
                     <Button variant="outline-danger" size="sm" onClick={() => deleteUser(user.id)}>Delete</Button>
                   </div>
                 </ListGroup.Item>
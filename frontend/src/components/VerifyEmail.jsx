@@ -0,0 +1,47 @@
+import { useEffect, useRef, useState } from 'react';
+import { Container, Row, Col, Card, Alert, Spinner } from 'react-bootstrap';
+import { Link, useSearchParams } from 'react-router-dom';
+import { authService } from '../services/auth';
+
+export const VerifyEmail = () => {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') || '';
+  const [status, setStatus] = useState(token ? 'verifying' : 'error');
+  const [message, setMessage] = useState(token ? '' : 'This verification link is incomplete.');
+  // Tokens are single-use, so guard against the effect running twice.
+  const submitted = useRef(false);
+
+  useEffect(() => {
+    if (!token || submitted.current) return;
+    submitted.current = true;
+
+    authService.verifyEmail(token)
+      .then(() => setStatus('done'))
+      .catch((error) => {
+        setStatus('error');
+        setMessage(error.response?.data?.message || 'Could not verify your email address.');
+      });
+  }, [token]);
+
+  return (
+    <div className="min-vh-100 d-flex align-items-center justify-content-center" style={{ background: '#f5f5f5', padding: '20px' }}>
+      <Container>
+        <Row className="justify-content-center">
+          <Col xs={12} md={8} lg={5}>
+            <Card className="shadow-lg" style={{ maxWidth: '400px', margin: '0 auto' }}>
+              <Card.Body className="p-4 text-center">
+                <h2 className="mb-4">Verify email</h2>
+                {status === 'verifying' && <Spinner animation="border" />}
+                {status === 'done' && (
+                  <Alert variant="success">Your email address is verified. You can now upload and share files.</Alert>
+                )}
+                {status === 'error' && <Alert variant="danger">{message}</Alert>}
+                <Link to="/dashboard">Go to dashboard</Link>
+              </Card.Body>
+            </Card>
+          </Col>
+        </Row>
+      </Container>
+    </div>
+  );
+};
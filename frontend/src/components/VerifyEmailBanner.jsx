@@ -0,0 +1,40 @@
+import { useEffect, useState } from 'react';
+import { Alert, Button } from 'react-bootstrap';
+import { toast } from 'react-toastify';
+import { authService } from '../services/auth';
+
+// Reminds users who have not confirmed their email that uploading and
+// sharing stay blocked until they do.
+export const VerifyEmailBanner = () => {
+  const [unverified, setUnverified] = useState(false);
+  const [sending, setSending] = useState(false);
+
+  useEffect(() => {
+    authService.getCurrentUser()
+      .then((user) => setUnverified(Boolean(user) && !user.emailVerifiedAt))
+      .catch(() => setUnverified(false));
+  }, []);
+
+  const handleResend = async () => {
+    setSending(true);
+    try {
+      await authService.resendVerification();
+      toast.success('Verification email sent.');
+    } catch (error) {
+      toast.error(error.response?.data?.message || 'Could not send the verification email.');
+    } finally {
+      setSending(false);
+    }
+  };
+
+  if (!unverified) return null;
+
+  return (
+    <Alert variant="warning" className="d-flex justify-content-between align-items-center">
+      <span>Confirm your email address to upload and share files. Check your inbox for the link.</span>
+      <Button variant="outline-dark" size="sm" onClick={handleResend} disabled={sending}>
+        {sending ? 'Sending...' : 'Resend email'}
+      </Button>
+    </Alert>
+  );
+};
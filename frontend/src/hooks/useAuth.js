@@ -3,6 +3,17 @@ import { useContext } from 'react';
 import { AuthContext } from '../context/authContextValue';
 import axios from '../lib/axios';
 
+// A login response only carries tokens once nothing else is asked of the
+// user.
+const isSession = (data) =>
+  Boolean(data) && !data.mfaRequired && !data.passwordChangeRequired;
+
+// Where to send the user after a login step resolves.
+export const afterLoginPath = (result) =>
+  result?.passwordChangeRequired
+    ? `/reset-password?token=${encodeURIComponent(result.resetToken)}`
+    : '/dashboard';
+
 export const useAuth = () => {
   const context = useContext(AuthContext);
   
@@ -28,6 +39,16 @@ export const useAuth = () => {
     }
   };
 
+  const startSession = (data) => {
+    localStorage.setItem('token', data.token);
+    localStorage.setItem('refreshToken', data.refreshToken);
+    setUser(data.user);
+  };
+
+  // Resolves with the session, with { mfaRequired, challenge } when a
+  // second factor is needed to finish logging in, or with
+  // { passwordChangeRequired, resetToken } when an admin requires a new
+  // password first.
   const login = async ({ email, password }) => {
     try {
       const response = await axios.post('/api/login', {
@@ -35,9 +56,8 @@ export const useAuth = () => {
         password
       });
       
-      if (response.data) {
-        localStorage.setItem('token', response.data.token);
-        setUser(response.data.user);
+      if (isSession(response.data)) {
+        startSession(response.data);
       }
       
       return response.data;
@@ -47,9 +67,39 @@ export const useAuth = () => {
     }
   };
 
+  const completeMfaLogin = async ({ challenge, code }) => {
+    const trimmed = code.trim();
+    const isRecoveryCode = trimmed.length > 6;
+    const response = await axios.post('/api/login/mfa', {
+      challenge,
+      ...(isRecoveryCode ? { recoveryCode: trimmed } : { code: trimmed })
+    });
+    if (isSession(response.data)) {
+      startSession(response.data);
+    }
+    return response.data;
+  };
+
+  // Finishes a single sign-on, resolving like login.
+  const loginWithTicket = async (ticket) => {
+    const response = await axios.post('/api/auth/oidc/exchange', { ticket });
+    if (isSession(response.data)) {
+      startSession(response.data);
+    }
+    return response.data;
+  };
+
+  const enrollDuringLogin = async (challenge) => {
+    const response = await axios.post('/api/login/mfa/enroll', { challenge });
+    return response.data;
+  };
+
   return { 
     user,
     login,
+    completeMfaLogin,
+    loginWithTicket,
+    enrollDuringLogin,
     logout,
     register,
     loading,
@@ -1,11 +1,16 @@
-import { useReducer } from 'react';
-import { useNavigate } from 'react-router-dom';
-import { useAuth } from './useAuth';
+import { useEffect, useReducer } from 'react';
+import { useLocation, useNavigate } from 'react-router-dom';
+import { afterLoginPath, useAuth } from './useAuth';
 import { handleLoginError } from '../utils/errorHandling';
 
 const initialState = {
   email: '',
   password: '',
+  code: '',
+  challenge: null,
+  enrollment: null,
+  recoveryCodes: null,
+  next: '/dashboard',
   error: '',
   loading: false
 };
@@ -18,6 +23,10 @@ const formReducer = (state, action) => {
       return { ...state, error: action.payload };
     case 'SET_LOADING':
       return { ...state, loading: action.payload };
+    case 'SET_CHALLENGE':
+      return { ...state, challenge: action.challenge, enrollment: action.enrollment, code: '' };
+    case 'SET_RECOVERY_CODES':
+      return { ...state, recoveryCodes: action.payload, next: action.next };
     case 'RESET_FORM':
       return initialState;
     default:
@@ -27,8 +36,45 @@ const formReducer = (state, action) => {
 
 export const useLoginForm = () => {
   const [state, dispatch] = useReducer(formReducer, initialState);
-  const { login } = useAuth();
+  const { login, completeMfaLogin, enrollDuringLogin } = useAuth();
   const navigate = useNavigate();
+  const location = useLocation();
+
+  // A single sign-on that still needs a second factor lands here with the
+  // challenge in the navigation state.
+  const pendingMfa = location.state?.mfa;
+  useEffect(() => {
+    if (!pendingMfa) return;
+    const start = async () => {
+      try {
+        const enrollment = pendingMfa.enrollmentRequired
+          ? await enrollDuringLogin(pendingMfa.challenge)
+          : null;
+        dispatch({ type: 'SET_CHALLENGE', challenge: pendingMfa.challenge, enrollment });
+      } catch (err) {
+        handleLoginError(err, dispatch);
+      }
+    };
+    start();
+    // The challenge is only good once, so run this once per navigation.
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [pendingMfa]);
+
+  const submitCode = async () => {
+    const { challenge, code } = state;
+    if (!code) {
+      dispatch({ type: 'SET_ERROR', payload: 'Please enter your verification code' });
+      return;
+    }
+
+    const session = await completeMfaLogin({ challenge, code });
+    if (session.recoveryCodes) {
+      // Shown once; the user continues to the dashboard after saving them.
+      dispatch({ type: 'SET_RECOVERY_CODES', payload: session.recoveryCodes, next: afterLoginPath(session) });
+      return;
+    }
+    navigate(afterLoginPath(session));
+  };
 
   const handleSubmit = async (e) => {
     e.preventDefault();
@@ -37,14 +83,26 @@ export const useLoginForm = () => {
     try {
       dispatch({ type: 'SET_ERROR', payload: '' });
       dispatch({ type: 'SET_LOADING', payload: true });
+
+      if (state.challenge) {
+        await submitCode();
+        return;
+      }
       
       if (!email || !password) {
         dispatch({ type: 'SET_ERROR', payload: 'Please enter both email and password' });
         return;
       }
 
-      await login({ email, password });
-      navigate('/dashboard');
+      const result = await login({ email, password });
+      if (result?.mfaRequired) {
+        const enrollment = result.enrollmentRequired
+          ? await enrollDuringLogin(result.challenge)
+          : null;
+        dispatch({ type: 'SET_CHALLENGE', challenge: result.challenge, enrollment });
+        return;
+      }
+      navigate(afterLoginPath(result));
     } catch (err) {
       handleLoginError(err, dispatch);
     } finally {
@@ -55,6 +113,8 @@ export const useLoginForm = () => {
   return {
     state,
     dispatch,
-    handleSubmit
+    handleSubmit,
+    finish: () => navigate(state.next),
+    restart: () => dispatch({ type: 'RESET_FORM' })
   };
 }; 
\ No newline at end of file
@@ -22,12 +22,12 @@ const useShare = () => {
         { headers: { Authorization: `Bearer ${token}` } }
       );
 
-      const { shareableLink } = response.data;
+      const { shareableLink, previewLink } = response.data;
       if (!shareableLink) {
         throw new Error('Invalid share response from server.');
       }
 
-      setShareableLink(shareableLink);
+      setShareableLink(previewLink || shareableLink);
       setShowShareModal(true);
       toast.success('Share link generated successfully.');
     } catch (error) {
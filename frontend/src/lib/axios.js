@@ -1,4 +1,5 @@
 import axios from 'axios';
+import { restoreImpersonator } from '../utils/impersonation';
 
 const instance = axios.create({
   baseURL: 'http://localhost:8080',
@@ -20,11 +21,51 @@ instance.interceptors.request.use(
   }
 );
 
+let refreshing = null;
+
+// Exchanges the stored refresh token for a new pair. Concurrent 401s share
+// one request, since each refresh token can only be used once.
+const refreshTokens = () => {
+  if (!refreshing) {
+    const refreshToken = localStorage.getItem('refreshToken');
+    refreshing = (refreshToken
+      ? axios.post(`${instance.defaults.baseURL}/api/token/refresh`, { refreshToken })
+      : Promise.reject(new Error('No refresh token'))
+    )
+      .then(({ data }) => {
+        localStorage.setItem('token', data.token);
+        localStorage.setItem('refreshToken', data.refreshToken);
+        return data.token;
+      })
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+};
+
 instance.interceptors.response.use(
   (response) => response,
-  (error) => {
+  async (error) => {
+    const original = error.config;
+    if (error.response?.status === 401 && original && !original._retried && !['/api/login', '/api/logout'].includes(original.url)) {
+      original._retried = true;
+      try {
+        const token = await refreshTokens();
+        original.headers.Authorization = `Bearer ${token}`;
+        return instance(original);
+      } catch {
+        // Fall through to signing the user out.
+      }
+    }
     if (error.response?.status === 401) {
+      // An expired impersonation returns the admin to their own account.
+      if (restoreImpersonator()) {
+        window.location.href = '/user-management';
+        return Promise.reject(error);
+      }
       localStorage.removeItem('token');
+      localStorage.removeItem('refreshToken');
       window.location.href = '/login';
     }
     return Promise.reject(error);
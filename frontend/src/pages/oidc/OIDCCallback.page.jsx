@@ -0,0 +1,5 @@
+import { OIDCCallback } from '../../components/OIDCCallback.jsx';
+
+export const OIDCCallbackPage = () => {
+  return <OIDCCallback />;
+};
@@ -0,0 +1,9 @@
+import { ForgotPasswordForm, ResetPasswordForm } from '../../components/PasswordResetForm.jsx';
+
+export const ForgotPasswordPage = () => {
+  return <ForgotPasswordForm />;
+};
+
+export const ResetPasswordPage = () => {
+  return <ResetPasswordForm />;
+};
@@ -0,0 +1,5 @@
+import { VerifyEmail } from '../../components/VerifyEmail.jsx';
+
+export const VerifyEmailPage = () => {
+  return <VerifyEmail />;
+};
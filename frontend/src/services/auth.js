@@ -1,4 +1,5 @@
 import axios from '../lib/axios';
+import { beginImpersonation, restoreImpersonator } from '../utils/impersonation';
 
 export const authService = {
   login: async (email, password) => {
@@ -9,11 +10,12 @@ export const authService = {
       });
       if (response.data.token) {
         localStorage.setItem('token', response.data.token);
+        localStorage.setItem('refreshToken', response.data.refreshToken);
       }
       return response.data;
     } catch (error) {
       if (error.response) {
-        throw new Error(error.response.data.message || 'An error occurred');
+        throw new Error(error.response.data.details || error.response.data.message || 'An error occurred');
       } else if (error.request) {
         throw new Error('No response from server');
       } else {
@@ -37,6 +39,49 @@ export const authService = {
     }
   },
 
+  forgotPassword: async (email) => {
+    const response = await axios.post('/api/password/forgot', { email });
+    return response.data;
+  },
+
+  resetPassword: async (token, password) => {
+    const response = await axios.post('/api/password/reset', { token, password });
+    return response.data;
+  },
+
+  verifyEmail: async (token) => {
+    const response = await axios.post('/api/email/verify', { token });
+    return response.data;
+  },
+
+  resendVerification: async () => {
+    const response = await axios.post('/api/email/verify/resend');
+    return response.data;
+  },
+
+  getOIDCProviders: async () => {
+    const response = await axios.get('/api/auth/oidc/providers');
+    return response.data.data;
+  },
+
+  // Switches to acting as the user until stopImpersonating is called or the
+  // impersonation expires.
+  impersonate: async (userId, reason) => {
+    const response = await axios.post(`/api/admin/users/${userId}/impersonate`, { reason });
+    beginImpersonation(response.data.token);
+    return response.data;
+  },
+
+  stopImpersonating: async () => {
+    try {
+      await axios.post('/api/impersonation/end');
+    } catch (error) {
+      console.error('Ending impersonation failed:', error);
+    } finally {
+      restoreImpersonator();
+    }
+  },
+
   getCurrentUser: async () => {
     try {
       const token = localStorage.getItem('token');
@@ -58,8 +103,32 @@ export const authService = {
     }
   },
 
-  logout: () => {
-    localStorage.removeItem('token');
+  updateProfile: async (fields) => {
+    const response = await axios.patch('/api/users/me', fields);
+    return response.data;
+  },
+
+  // Signs out every other session; this one stays signed in.
+  changePassword: async (currentPassword, newPassword) => {
+    const response = await axios.post('/api/users/me/password', { currentPassword, newPassword });
+    return response.data;
+  },
+
+  logout: async () => {
+    const token = localStorage.getItem('token');
+    const refreshToken = localStorage.getItem('refreshToken');
+    try {
+      if (token) {
+        await axios.post('/api/logout', { refreshToken }, {
+          headers: { 'Authorization': `Bearer ${token}` }
+        });
+      }
+    } catch (error) {
+      console.error('Logout request failed:', error);
+    } finally {
+      localStorage.removeItem('token');
+      localStorage.removeItem('refreshToken');
+    }
   },
 
   getToken: () => {
@@ -0,0 +1,22 @@
+// While an admin impersonates a user, their own tokens are kept aside so
+// they can return to their account when the impersonation ends.
+
+export const beginImpersonation = (token) => {
+  localStorage.setItem('impersonatorToken', localStorage.getItem('token') || '');
+  localStorage.setItem('impersonatorRefreshToken', localStorage.getItem('refreshToken') || '');
+  localStorage.setItem('token', token);
+  localStorage.removeItem('refreshToken');
+};
+
+// Puts the admin's own tokens back. Returns false when no impersonation was
+// in progress.
+export const restoreImpersonator = () => {
+  const token = localStorage.getItem('impersonatorToken');
+  if (!token) return false;
+
+  localStorage.setItem('token', token);
+  localStorage.setItem('refreshToken', localStorage.getItem('impersonatorRefreshToken') || '');
+  localStorage.removeItem('impersonatorToken');
+  localStorage.removeItem('impersonatorRefreshToken');
+  return true;
+};